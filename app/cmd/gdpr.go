@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"fat2fast/ikv/modules/book"
+	"fat2fast/ikv/modules/user"
+	"fat2fast/ikv/shared"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+// gdprCmd xử lý yêu cầu của chủ thể dữ liệu (trích xuất/xóa dữ liệu cá nhân) trên mọi module được kích hoạt
+var gdprCmd = &cobra.Command{
+	Use:   "gdpr",
+	Short: "Xử lý yêu cầu dữ liệu cá nhân của user",
+	Long:  "Lệnh này trích xuất hoặc xóa dữ liệu cá nhân của một user trên tất cả modules được kích hoạt",
+}
+
+var gdprExportCmd = &cobra.Command{
+	Use:   "export <user-id>",
+	Short: "Trích xuất dữ liệu cá nhân của user ra file JSON",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		userID := parseGdprUserID(args[0])
+
+		userModule := loadUserDataModules()
+		archive, err := userModule.ExportUserDataArchive(context.Background(), userID)
+		if err != nil {
+			log.Fatalf("Failed to export user data: %v", err)
+		}
+
+		data, err := json.MarshalIndent(archive, "", "  ")
+		if err != nil {
+			log.Fatalf("Failed to encode user data: %v", err)
+		}
+
+		if output == "" {
+			fmt.Println(string(data))
+			return
+		}
+		// File chứa dữ liệu cá nhân nên chỉ chủ sở hữu được đọc
+		if err := os.WriteFile(output, data, 0600); err != nil {
+			log.Fatalf("Failed to write %s: %v", output, err)
+		}
+		fmt.Printf("✅ Đã trích xuất dữ liệu của user %s vào %s\n", userID, output)
+	},
+}
+
+var gdprEraseCmd = &cobra.Command{
+	Use:   "erase <user-id>",
+	Short: "Xóa dữ liệu cá nhân của user (không thể hoàn tác)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		confirmed, _ := cmd.Flags().GetBool("yes")
+		userID := parseGdprUserID(args[0])
+
+		if !confirmed {
+			log.Fatalf("Erasing user data cannot be undone, re-run with --yes to confirm")
+		}
+
+		userModule := loadUserDataModules()
+		if err := userModule.EraseUser(context.Background(), userID); err != nil {
+			log.Fatalf("Failed to erase user data: %v", err)
+		}
+		fmt.Printf("✅ Đã xóa dữ liệu cá nhân của user %s\n", userID)
+	},
+}
+
+func parseGdprUserID(value string) uuid.UUID {
+	userID, err := uuid.Parse(value)
+	if err != nil {
+		log.Fatalf("Invalid user ID %q: %v", value, err)
+	}
+	return userID
+}
+
+// loadUserDataModules khởi tạo các module và trả về module User đã gắn registry của tất cả modules
+func loadUserDataModules() *user.Module {
+	shared.SetupLogger()
+
+	registry := shared.NewModuleRegistry()
+
+	userModule, err := user.NewModule()
+	if err != nil {
+		log.Fatalf("Failed to initialize User module: %v", err)
+	}
+	registry.RegisterModule(userModule)
+
+	bookModule, err := book.NewModule()
+	if err != nil {
+		log.Fatalf("Failed to initialize Book module: %v", err)
+	}
+	registry.RegisterModule(bookModule)
+
+	if !userModule.IsEnabled() {
+		log.Fatalf("User module is disabled")
+	}
+	userModule.SetUserDataRegistry(registry)
+
+	return userModule
+}
+
+func init() {
+	gdprExportCmd.Flags().StringP("output", "o", "", "File JSON để ghi bản trích xuất (mặc định in ra stdout)")
+	gdprEraseCmd.Flags().Bool("yes", false, "Xác nhận xóa dữ liệu")
+}
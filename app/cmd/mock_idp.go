@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"fat2fast/ikv/shared"
+	sharecomponent "fat2fast/ikv/shared/component"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/cobra"
+)
+
+// mockIdpCmd chạy một identity provider OIDC tối giản để thử social login ở môi trường dev/test.
+// Mọi yêu cầu đăng nhập được tự động chấp thuận, email lấy từ tham số login_hint hoặc flag --email.
+var mockIdpCmd = &cobra.Command{
+	Use:   "mock-idp",
+	Short: "Chạy identity provider OIDC giả lập cho social login",
+	Long: "Lệnh này chạy identity provider OIDC giả lập (authorization code + PKCE) để test social login.\n" +
+		"Bật provider 'mock' của module user bằng MODULE_USER_OAUTH_MOCK_ENABLED=true.\n" +
+		"Tham số tùy chọn ở /authorize: login_hint=<email>, email_verified=false, deny=true.",
+	Run: func(cmd *cobra.Command, args []string) {
+		port, _ := cmd.Flags().GetString("port")
+		issuer, _ := cmd.Flags().GetString("issuer")
+		email, _ := cmd.Flags().GetString("email")
+		if issuer == "" {
+			issuer = "http://localhost:" + port
+		}
+
+		idp, err := newMockIdp(issuer, email)
+		if err != nil {
+			log.Fatalf("Failed to start mock identity provider: %v", err)
+		}
+
+		r := gin.Default()
+		r.GET("/.well-known/openid-configuration", idp.discovery)
+		r.GET("/authorize", idp.authorize)
+		r.POST("/token", idp.token)
+		r.GET("/userinfo", idp.userinfo)
+		r.GET("/jwks", idp.jwks)
+
+		log.Printf("Mock identity provider listening on :%s (issuer %s)", port, issuer)
+		if err := r.Run(":" + port); err != nil {
+			log.Fatalf("Mock identity provider stopped: %v", err)
+		}
+	},
+}
+
+type mockIdpGrant struct {
+	clientID      string
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	email         string
+	emailVerified bool
+	expiresAt     time.Time
+}
+
+type mockIdp struct {
+	issuer       string
+	defaultEmail string
+	keyRing      *sharecomponent.KeyRing
+
+	mu           sync.Mutex
+	codes        map[string]*mockIdpGrant
+	accessTokens map[string]*mockIdpGrant
+}
+
+func newMockIdp(issuer string, defaultEmail string) (*mockIdp, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	signingKey := &sharecomponent.SigningKey{
+		Kid:        "mock-1",
+		Method:     jwt.SigningMethodRS256,
+		PrivateKey: privateKey,
+		PublicKey:  &privateKey.PublicKey,
+	}
+	keyRing, err := sharecomponent.NewKeyRing(signingKey.Kid, signingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mockIdp{
+		issuer:       strings.TrimSuffix(issuer, "/"),
+		defaultEmail: defaultEmail,
+		keyRing:      keyRing,
+		codes:        make(map[string]*mockIdpGrant),
+		accessTokens: make(map[string]*mockIdpGrant),
+	}, nil
+}
+
+func (idp *mockIdp) discovery(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"issuer":                                idp.issuer,
+		"authorization_endpoint":                idp.issuer + "/authorize",
+		"token_endpoint":                        idp.issuer + "/token",
+		"userinfo_endpoint":                     idp.issuer + "/userinfo",
+		"jwks_uri":                              idp.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (idp *mockIdp) authorize(c *gin.Context) {
+	redirectURI := c.Query("redirect_uri")
+	target, err := url.Parse(redirectURI)
+	if err != nil || redirectURI == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "redirect_uri is required"})
+		return
+	}
+
+	query := target.Query()
+	query.Set("state", c.Query("state"))
+
+	switch {
+	case c.Query("deny") == "true":
+		query.Set("error", "access_denied")
+		query.Set("error_description", "user denied the request")
+	case c.Query("response_type") != "code" || c.Query("client_id") == "":
+		query.Set("error", "invalid_request")
+		query.Set("error_description", "response_type=code and client_id are required")
+	case c.Query("code_challenge") == "" || c.Query("code_challenge_method") != "S256":
+		query.Set("error", "invalid_request")
+		query.Set("error_description", "PKCE with S256 is required")
+	default:
+		email := c.DefaultQuery("login_hint", idp.defaultEmail)
+		code, _ := shared.RandomStr(16)
+
+		idp.mu.Lock()
+		idp.codes[code] = &mockIdpGrant{
+			clientID:      c.Query("client_id"),
+			redirectURI:   redirectURI,
+			codeChallenge: c.Query("code_challenge"),
+			nonce:         c.Query("nonce"),
+			email:         email,
+			emailVerified: c.Query("email_verified") != "false",
+			expiresAt:     time.Now().Add(time.Minute),
+		}
+		idp.mu.Unlock()
+
+		query.Set("code", code)
+	}
+
+	target.RawQuery = query.Encode()
+	c.Redirect(http.StatusFound, target.String())
+}
+
+func (idp *mockIdp) token(c *gin.Context) {
+	if c.PostForm("grant_type") != "authorization_code" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported_grant_type"})
+		return
+	}
+
+	// Code chỉ dùng được một lần
+	idp.mu.Lock()
+	grant, ok := idp.codes[c.PostForm("code")]
+	delete(idp.codes, c.PostForm("code"))
+	idp.mu.Unlock()
+
+	if !ok || time.Now().After(grant.expiresAt) ||
+		grant.clientID != c.PostForm("client_id") || grant.redirectURI != c.PostForm("redirect_uri") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant"})
+		return
+	}
+
+	sum := sha256.Sum256([]byte(c.PostForm("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != grant.codeChallenge {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            idp.issuer,
+		"sub":            idp.subject(grant.email),
+		"aud":            grant.clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          grant.nonce,
+		"email":          grant.email,
+		"email_verified": grant.emailVerified,
+		"given_name":     strings.Split(grant.email, "@")[0],
+		"family_name":    "Mock",
+	})
+	signingKey := idp.keyRing.Active()
+	idToken.Header["kid"] = signingKey.Kid
+	rawIDToken, err := idToken.SignedString(signingKey.PrivateKey)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return
+	}
+
+	accessToken, _ := shared.RandomStr(16)
+	idp.mu.Lock()
+	idp.accessTokens[accessToken] = grant
+	idp.mu.Unlock()
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     rawIDToken,
+	})
+}
+
+func (idp *mockIdp) userinfo(c *gin.Context) {
+	accessToken := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+
+	idp.mu.Lock()
+	grant, ok := idp.accessTokens[accessToken]
+	idp.mu.Unlock()
+
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sub":            idp.subject(grant.email),
+		"email":          grant.email,
+		"email_verified": grant.emailVerified,
+		"given_name":     strings.Split(grant.email, "@")[0],
+		"family_name":    "Mock",
+	})
+}
+
+func (idp *mockIdp) jwks(c *gin.Context) {
+	c.JSON(http.StatusOK, idp.keyRing.JWKS())
+}
+
+// subject tạo sub ổn định theo email để đăng nhập lại vẫn ra cùng một tài khoản
+func (idp *mockIdp) subject(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(email)))
+	return fmt.Sprintf("mock-%x", sum[:8])
+}
+
+func init() {
+	mockIdpCmd.Flags().StringP("port", "p", "9099", "Cổng lắng nghe của identity provider giả lập")
+	mockIdpCmd.Flags().String("issuer", "", "Issuer URL (mặc định http://localhost:<port>)")
+	mockIdpCmd.Flags().String("email", "mock.user@example.com", "Email mặc định khi không có login_hint")
+}
@@ -9,6 +9,7 @@ import (
 	"fat2fast/ikv/modules/book"
 	"fat2fast/ikv/modules/user"
 	"fat2fast/ikv/shared"
+	"fat2fast/ikv/shared/middleware"
 
 	"github.com/gin-gonic/gin"
 
@@ -33,6 +34,8 @@ var rootCmd = &cobra.Command{
 		if err != nil {
 			panic(err)
 		}
+		// Mỗi request được gắn request ID để đối chiếu log và audit của cùng một request
+		r.Use(middleware.RequestID())
 		// // Setup Database
 		// db := shared.ConnectDb()
 		// // init db to app
@@ -46,18 +49,24 @@ var rootCmd = &cobra.Command{
 		// Khởi tạo module registry
 		registry := shared.NewModuleRegistry()
 
-		// Khởi tạo và đăng ký module Book
-		bookModule, err := book.NewModule()
-		if err != nil {
-			log.Fatalf("Failed to initialize Book module: %v", err)
-		}
-		registry.RegisterModule(bookModule)
 		// Khởi tạo và đăng ký module User
 		userModule, err := user.NewModule()
 		if err != nil {
 			log.Fatalf("Failed to initialize User module: %v", err)
 		}
 		registry.RegisterModule(userModule)
+		// Khởi tạo và đăng ký module Book, dùng chung middleware xác thực của module User
+		// trừ khi Book được cấu hình xác thực qua endpoint introspect (chạy tách khỏi module User)
+		bookModule, err := book.NewModule()
+		if err != nil {
+			log.Fatalf("Failed to initialize Book module: %v", err)
+		}
+		if !bookModule.HasMiddlewareProvider() {
+			bookModule.SetMiddlewareProvider(userModule.MiddlewareProvider())
+		}
+		registry.RegisterModule(bookModule)
+		// Yêu cầu trích xuất/xóa dữ liệu cá nhân của module User được xử lý trên mọi module
+		userModule.SetUserDataRegistry(registry)
 
 		// Đăng ký tất cả các module với router
 		if err := registry.RegisterAllModules(r); err != nil {
@@ -159,6 +168,14 @@ func init() {
 	moduleCmd.AddCommand(moduleListCmd)
 	rootCmd.AddCommand(moduleCmd)
 
+	// Thêm identity provider giả lập từ mock_idp.go
+	rootCmd.AddCommand(mockIdpCmd)
+
+	// Thêm gdpr command và sub commands từ gdpr.go
+	gdprCmd.AddCommand(gdprExportCmd)
+	gdprCmd.AddCommand(gdprEraseCmd)
+	rootCmd.AddCommand(gdprCmd)
+
 	// Có thể thêm flags cho các commands
 	// Ví dụ: thêm flag --verbose cho version command
 	versionCmd.Flags().BoolP("verbose", "v", false, "In thông tin chi tiết")
@@ -11,10 +11,10 @@ import (
 )
 
 // Delete xóa vĩnh viễn book
-func (r *BookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (r *BookRepository) Delete(ctx context.Context, organizationID uuid.UUID, id uuid.UUID) error {
 	db := r.dbCtx.GetMainConnection()
 
-	result := db.WithContext(ctx).Where("id = ?", id).Delete(&bookmodel.Book{})
+	result := db.WithContext(ctx).Where("organization_id = ? AND id = ?", organizationID, id).Delete(&bookmodel.Book{})
 	if result.Error != nil {
 		return errors.WithStack(result.Error)
 	}
@@ -27,12 +27,12 @@ func (r *BookRepository) Delete(ctx context.Context, id uuid.UUID) error {
 }
 
 // SoftDelete xóa mềm book (set status = deleted)
-func (r *BookRepository) SoftDelete(ctx context.Context, id uuid.UUID) error {
+func (r *BookRepository) SoftDelete(ctx context.Context, organizationID uuid.UUID, id uuid.UUID) error {
 	db := r.dbCtx.GetMainConnection()
 
 	now := time.Now()
 	result := db.WithContext(ctx).Model(&bookmodel.Book{}).
-		Where("id = ?", id).
+		Where("organization_id = ? AND id = ?", organizationID, id).
 		Updates(map[string]interface{}{
 			"status":     bookmodel.StatusDeleted,
 			"updated_at": now,
@@ -11,12 +11,12 @@ import (
 	"gorm.io/gorm"
 )
 
-// GetByID lấy book theo ID
-func (r *BookRepository) GetByID(ctx context.Context, id uuid.UUID) (*bookmodel.Book, error) {
+// GetByID lấy book theo ID trong tổ chức
+func (r *BookRepository) GetByID(ctx context.Context, organizationID uuid.UUID, id uuid.UUID) (*bookmodel.Book, error) {
 	db := r.dbCtx.GetMainConnection()
 	var book bookmodel.Book
 
-	err := db.WithContext(ctx).Where("id = ?", id).First(&book).Error
+	err := db.WithContext(ctx).Where("organization_id = ? AND id = ?", organizationID, id).First(&book).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("book not found")
@@ -27,14 +27,14 @@ func (r *BookRepository) GetByID(ctx context.Context, id uuid.UUID) (*bookmodel.
 	return &book, nil
 }
 
-// GetList lấy danh sách books với filter và pagination
-func (r *BookRepository) GetList(ctx context.Context, filter *bookmodel.ListBookFilter) ([]*bookmodel.Book, int64, error) {
+// GetList lấy danh sách books của tổ chức với filter và pagination
+func (r *BookRepository) GetList(ctx context.Context, organizationID uuid.UUID, filter *bookmodel.ListBookFilter) ([]*bookmodel.Book, int64, error) {
 	db := r.dbCtx.GetMainConnection()
 	var books []*bookmodel.Book
 	var total int64
 
 	// Build base query
-	query := db.WithContext(ctx).Model(&bookmodel.Book{})
+	query := db.WithContext(ctx).Model(&bookmodel.Book{}).Where("organization_id = ?", organizationID)
 
 	// Apply filters
 	query = r.applyFilters(query, filter)
@@ -55,13 +55,13 @@ func (r *BookRepository) GetList(ctx context.Context, filter *bookmodel.ListBook
 	return books, total, nil
 }
 
-// Exists kiểm tra book có tồn tại không
-func (r *BookRepository) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
+// Exists kiểm tra book có tồn tại trong tổ chức không
+func (r *BookRepository) Exists(ctx context.Context, organizationID uuid.UUID, id uuid.UUID) (bool, error) {
 	db := r.dbCtx.GetMainConnection()
 	var count int64
 
 	err := db.WithContext(ctx).Model(&bookmodel.Book{}).
-		Where("id = ?", id).Count(&count).Error
+		Where("organization_id = ? AND id = ?", organizationID, id).Count(&count).Error
 	if err != nil {
 		return false, errors.WithStack(err)
 	}
@@ -10,15 +10,15 @@ import (
 	"github.com/pkg/errors"
 )
 
-// Update cập nhật book theo ID
-func (r *BookRepository) Update(ctx context.Context, id uuid.UUID, book *bookmodel.Book) error {
+// Update cập nhật book theo ID trong tổ chức
+func (r *BookRepository) Update(ctx context.Context, organizationID uuid.UUID, id uuid.UUID, book *bookmodel.Book) error {
 	db := r.dbCtx.GetMainConnection()
 
 	// Set updated_at
 	book.UpdatedAt = time.Now()
 
 	// Update book
-	result := db.WithContext(ctx).Where("id = ?", id).Updates(book)
+	result := db.WithContext(ctx).Where("organization_id = ? AND id = ?", organizationID, id).Updates(book)
 	if result.Error != nil {
 		return errors.WithStack(result.Error)
 	}
@@ -31,7 +31,7 @@ func (r *BookRepository) Update(ctx context.Context, id uuid.UUID, book *bookmod
 }
 
 // UpdateFields cập nhật các fields cụ thể
-func (r *BookRepository) UpdateFields(ctx context.Context, id uuid.UUID, fields map[string]interface{}) error {
+func (r *BookRepository) UpdateFields(ctx context.Context, organizationID uuid.UUID, id uuid.UUID, fields map[string]interface{}) error {
 	db := r.dbCtx.GetMainConnection()
 
 	// Add updated_at
@@ -39,7 +39,7 @@ func (r *BookRepository) UpdateFields(ctx context.Context, id uuid.UUID, fields
 
 	// Update specific fields
 	result := db.WithContext(ctx).Model(&bookmodel.Book{}).
-		Where("id = ?", id).Updates(fields)
+		Where("organization_id = ? AND id = ?", organizationID, id).Updates(fields)
 
 	if result.Error != nil {
 		return errors.WithStack(result.Error)
@@ -53,8 +53,8 @@ func (r *BookRepository) UpdateFields(ctx context.Context, id uuid.UUID, fields
 }
 
 // UpdateStatus cập nhật status của book
-func (r *BookRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status bookmodel.BookStatus) error {
-	return r.UpdateFields(ctx, id, map[string]interface{}{
+func (r *BookRepository) UpdateStatus(ctx context.Context, organizationID uuid.UUID, id uuid.UUID, status bookmodel.BookStatus) error {
+	return r.UpdateFields(ctx, organizationID, id, map[string]interface{}{
 		"status": status,
 	})
 }
@@ -0,0 +1,40 @@
+package bookrepository
+
+import (
+	"context"
+
+	bookmodel "fat2fast/ikv/modules/book/model"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// ListByCreator lấy tất cả books do một user tạo, kể cả book đã xóa mềm
+func (r *BookRepository) ListByCreator(ctx context.Context, createdBy string) ([]*bookmodel.Book, error) {
+	db := r.dbCtx.GetMainConnection()
+	var books []*bookmodel.Book
+
+	if err := db.WithContext(ctx).Where("created_by = ?", createdBy).Order("created_at ASC").Find(&books).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return books, nil
+}
+
+// AnonymizeActor xóa thông tin người tạo/sửa khỏi các books của một user
+func (r *BookRepository) AnonymizeActor(ctx context.Context, actor string) error {
+	db := r.dbCtx.GetMainConnection()
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&bookmodel.Book{}).Where("created_by = ?", actor).Update("created_by", "").Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&bookmodel.Book{}).Where("updated_by = ?", actor).Update("updated_by", "").Error
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
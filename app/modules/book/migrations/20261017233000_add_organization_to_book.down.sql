@@ -0,0 +1,9 @@
+-- Rollback: add_organization_to_book
+-- Created at: 2026-10-17 23:30:00
+
+ALTER TABLE book_books DROP CONSTRAINT IF EXISTS book_books_organization_title_key;
+ALTER TABLE book_books ADD CONSTRAINT book_books_title_key UNIQUE (title);
+
+DROP INDEX IF EXISTS idx_book_books_organization_created;
+
+ALTER TABLE book_books DROP COLUMN IF EXISTS organization_id;
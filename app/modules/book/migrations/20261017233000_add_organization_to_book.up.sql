@@ -0,0 +1,17 @@
+-- Migration: add_organization_to_book
+-- Created at: 2026-10-17 23:30:00
+
+-- Book thuộc về một tổ chức (user_organizations của module user, không khai báo FK để module độc lập).
+ALTER TABLE book_books ADD COLUMN IF NOT EXISTS organization_id varchar(36);
+
+-- Book tạo trước khi có tổ chức được gán vào tổ chức mặc định do migration create_organization_tables
+-- của module user tạo (ID cố định).
+UPDATE book_books SET organization_id = '00000000-0000-0000-0000-000000000001' WHERE organization_id IS NULL;
+
+ALTER TABLE book_books ALTER COLUMN organization_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_book_books_organization_created ON book_books (organization_id, created_at);
+
+-- Tên sách chỉ cần duy nhất trong phạm vi một tổ chức
+ALTER TABLE book_books DROP CONSTRAINT IF EXISTS book_books_title_key;
+ALTER TABLE book_books ADD CONSTRAINT book_books_organization_title_key UNIQUE (organization_id, title);
@@ -29,18 +29,19 @@ type UpdateBookRequest struct {
 
 // BookResponse đại diện cho dữ liệu trả về khi lấy thông tin sách
 type BookResponse struct {
-	ID          uuid.UUID  `json:"id"`
-	Title       string     `json:"title"`
-	Author      string     `json:"author"`
-	Description string     `json:"description"`
-	Price       float64    `json:"price"`
-	PublishedAt time.Time  `json:"published_at"`
-	CoverImage  string     `json:"cover_image"`
-	Status      BookStatus `json:"status"`
-	CreatedBy   string     `json:"created_by"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedBy   string     `json:"updated_by"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID             uuid.UUID  `json:"id"`
+	OrganizationID uuid.UUID  `json:"organization_id"`
+	Title          string     `json:"title"`
+	Author         string     `json:"author"`
+	Description    string     `json:"description"`
+	Price          float64    `json:"price"`
+	PublishedAt    time.Time  `json:"published_at"`
+	CoverImage     string     `json:"cover_image"`
+	Status         BookStatus `json:"status"`
+	CreatedBy      string     `json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedBy      string     `json:"updated_by"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
 
 // BookListResponse đại diện cho dữ liệu trả về khi lấy danh sách sách
@@ -67,6 +68,11 @@ type ListBookFilter struct {
 	PriceMax    float64   `json:"price_max" form:"price_max" binding:"omitempty,min=0"`
 }
 
+// UserBookData đại diện cho dữ liệu sách của một user trong bản trích xuất dữ liệu cá nhân
+type UserBookData struct {
+	Books []*BookResponse `json:"books"`
+}
+
 // CreateBookResponse đại diện cho dữ liệu trả về khi tạo sách mới
 type CreateBookResponse struct {
 	ID uuid.UUID `json:"id"`
@@ -77,18 +83,19 @@ type CreateBookResponse struct {
 // ToResponse chuyển đổi Book entity sang BookResponse
 func (b *Book) ToResponse() *BookResponse {
 	return &BookResponse{
-		ID:          b.ID,
-		Title:       b.Title,
-		Author:      b.Author,
-		Description: b.Description,
-		Price:       b.Price,
-		PublishedAt: b.PublishedAt,
-		CoverImage:  b.CoverImage,
-		Status:      b.Status,
-		CreatedBy:   b.CreatedBy,
-		CreatedAt:   b.CreatedAt,
-		UpdatedBy:   b.UpdatedBy,
-		UpdatedAt:   b.UpdatedAt,
+		ID:             b.ID,
+		OrganizationID: b.OrganizationID,
+		Title:          b.Title,
+		Author:         b.Author,
+		Description:    b.Description,
+		Price:          b.Price,
+		PublishedAt:    b.PublishedAt,
+		CoverImage:     b.CoverImage,
+		Status:         b.Status,
+		CreatedBy:      b.CreatedBy,
+		CreatedAt:      b.CreatedAt,
+		UpdatedBy:      b.UpdatedBy,
+		UpdatedAt:      b.UpdatedAt,
 	}
 }
 
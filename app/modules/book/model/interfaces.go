@@ -6,7 +6,8 @@ import (
 	"github.com/google/uuid"
 )
 
-// Repository interfaces cho Book
+// Repository interfaces cho Book. Các thao tác đọc/ghi theo ID đều giới hạn trong một tổ chức,
+// book của tổ chức khác được coi như không tồn tại.
 
 // ICreateBookRepository interface cho create operations
 type ICreateBookRepository interface {
@@ -15,22 +16,28 @@ type ICreateBookRepository interface {
 
 // IReadBookRepository interface cho read operations
 type IReadBookRepository interface {
-	GetByID(ctx context.Context, id uuid.UUID) (*Book, error)
-	GetList(ctx context.Context, filter *ListBookFilter) ([]*Book, int64, error)
-	Exists(ctx context.Context, id uuid.UUID) (bool, error)
+	GetByID(ctx context.Context, organizationID uuid.UUID, id uuid.UUID) (*Book, error)
+	GetList(ctx context.Context, organizationID uuid.UUID, filter *ListBookFilter) ([]*Book, int64, error)
+	Exists(ctx context.Context, organizationID uuid.UUID, id uuid.UUID) (bool, error)
 }
 
 // IUpdateBookRepository interface cho update operations
 type IUpdateBookRepository interface {
-	Update(ctx context.Context, id uuid.UUID, book *Book) error
-	UpdateFields(ctx context.Context, id uuid.UUID, fields map[string]interface{}) error
-	UpdateStatus(ctx context.Context, id uuid.UUID, status BookStatus) error
+	Update(ctx context.Context, organizationID uuid.UUID, id uuid.UUID, book *Book) error
+	UpdateFields(ctx context.Context, organizationID uuid.UUID, id uuid.UUID, fields map[string]interface{}) error
+	UpdateStatus(ctx context.Context, organizationID uuid.UUID, id uuid.UUID, status BookStatus) error
 }
 
 // IDeleteBookRepository interface cho delete operations
 type IDeleteBookRepository interface {
-	Delete(ctx context.Context, id uuid.UUID) error
-	SoftDelete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, organizationID uuid.UUID, id uuid.UUID) error
+	SoftDelete(ctx context.Context, organizationID uuid.UUID, id uuid.UUID) error
+}
+
+// IUserDataBookRepository interface cho các operations trên dữ liệu cá nhân của user
+type IUserDataBookRepository interface {
+	ListByCreator(ctx context.Context, createdBy string) ([]*Book, error)
+	AnonymizeActor(ctx context.Context, actor string) error
 }
 
 // IBookRepository composite interface cho tất cả CRUD operations
@@ -39,4 +46,5 @@ type IBookRepository interface {
 	IReadBookRepository
 	IUpdateBookRepository
 	IDeleteBookRepository
+	IUserDataBookRepository
 }
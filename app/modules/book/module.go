@@ -1,6 +1,7 @@
 package book
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"path/filepath"
@@ -10,6 +11,7 @@ import (
 	"fat2fast/ikv/shared"
 	sharedinfras "fat2fast/ikv/shared/infras"
 	"fat2fast/ikv/shared/middleware"
+	sharedrpc "fat2fast/ikv/shared/rpc"
 
 	bookhttpgin "fat2fast/ikv/modules/book/infras/controller/http-gin"
 	bookrepository "fat2fast/ikv/modules/book/infras/repository/gorm-pgsql"
@@ -17,6 +19,7 @@ import (
 	bookurlv1 "fat2fast/ikv/modules/book/urls/v1"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -56,12 +59,17 @@ type Config struct {
 			ConnMaxLifetime string `yaml:"conn_max_lifetime"`
 		} `yaml:"performance"`
 	} `yaml:"database"`
+
+	Auth struct {
+		Introspection sharedrpc.IntrospectClientConfig `yaml:"introspection"`
+	} `yaml:"auth"`
 }
 
 // Module đại diện cho module Book
 type Module struct {
-	config Config
-	DB     *gorm.DB
+	config      Config
+	DB          *gorm.DB
+	mldProvider sharedinfras.IMiddlewareProvider
 }
 
 // NewModule tạo một instance mới của module Book
@@ -82,6 +90,15 @@ func NewModule() (*Module, error) {
 		config: config,
 	}
 
+	// Xác thực qua endpoint introspect của module User khi Book chạy tách khỏi module User
+	if config.Auth.Introspection.URL != "" {
+		introspectRpcClient := sharedrpc.NewIntrospectRpcClient(config.Auth.Introspection)
+		mldProvider := middleware.NewMiddlewareProvider(introspectRpcClient)
+		mldProvider.SetAPIKeyIntrospector(introspectRpcClient)
+		module.mldProvider = mldProvider
+		log.Printf("Module %s authenticating requests via %s", module.GetName(), config.Auth.Introspection.URL)
+	}
+
 	// Kết nối database nếu module được kích hoạt
 	if module.IsEnabled() {
 		// retry 5 times
@@ -171,9 +188,14 @@ func (m *Module) Register(router *gin.Engine) error {
 
 	log.Printf("Registering module: %s (v%s)", m.GetName(), m.config.Module.Version)
 
+	if m.mldProvider == nil {
+		return fmt.Errorf("module %s requires a middleware provider", m.GetName())
+	}
+	appCtx := sharedinfras.NewAppContext(m.GetDB(), m.mldProvider, nil)
+
 	// Dependency injection
 	controller := m.Initialize()
-	routes := bookurlv1.GetRoutes(controller)
+	routes := bookurlv1.GetRoutes(controller, appCtx.MiddlewareProvider())
 
 	log.Printf("Registering module routes")
 	router.Use(middleware.RecoverMiddleware())
@@ -183,7 +205,7 @@ func (m *Module) Register(router *gin.Engine) error {
 	bookV1 := v1.Group("/books")
 
 	for _, route := range routes {
-		bookV1.Handle(route.Method, route.Path, route.HandlerFunc)
+		bookV1.Handle(route.Method, route.Path, route.Handlers()...)
 	}
 
 	return nil
@@ -209,6 +231,36 @@ func (m *Module) GetDB() *gorm.DB {
 	return m.DB
 }
 
+// SetMiddlewareProvider thiết lập provider xác thực cho các route cần bảo vệ
+func (m *Module) SetMiddlewareProvider(mldProvider sharedinfras.IMiddlewareProvider) {
+	m.mldProvider = mldProvider
+}
+
+// HasMiddlewareProvider kiểm tra module đã có provider xác thực chưa (cấu hình auth.introspection.url)
+func (m *Module) HasMiddlewareProvider() bool {
+	return m.mldProvider != nil
+}
+
+// ExportUserData trả về các book do user tạo
+func (m *Module) ExportUserData(ctx context.Context, userID uuid.UUID) (any, error) {
+	data, err := m.userDataHandler().Export(ctx, userID)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// EraseUserData ẩn danh người tạo/sửa trên các book của user
+func (m *Module) EraseUserData(ctx context.Context, userID uuid.UUID) error {
+	return m.userDataHandler().Erase(ctx, userID)
+}
+
+// userDataHandler khởi tạo handler xử lý dữ liệu cá nhân của user
+func (m *Module) userDataHandler() *bookservice.UserDataHandler {
+	dbCtx := sharedinfras.NewDbContext(m.DB)
+	return bookservice.NewUserDataHandler(bookrepository.NewBookRepository(dbCtx))
+}
+
 // Initialize khởi tạo và dependency injection cho module
 func (m *Module) Initialize() *bookhttpgin.BookHTTPController {
 	log.Printf("Initializing book module ")
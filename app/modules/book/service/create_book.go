@@ -37,28 +37,34 @@ func (h *CreateBookCommandHandler) Execute(ctx context.Context, cmd *CreateBookC
 		return nil, err
 	}
 
+	organizationID, err := organizationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Tạo UUID mới
 	newId := uuid.New()
 	now := time.Now()
 
 	// Tạo book entity từ command
 	book := &bookmodel.Book{
-		ID:          newId,
-		Title:       cmd.Dto.Title,
-		Author:      cmd.Dto.Author,
-		Description: cmd.Dto.Description,
-		Price:       cmd.Dto.Price,
-		PublishedAt: cmd.Dto.PublishedAt,
-		CoverImage:  cmd.Dto.CoverImage,
-		Status:      bookmodel.StatusActive,
-		CreatedBy:   "system", // TODO: Lấy từ context user hiện tại
-		CreatedAt:   now,
-		UpdatedBy:   "system", // TODO: Lấy từ context user hiện tại
-		UpdatedAt:   now,
+		ID:             newId,
+		OrganizationID: organizationID,
+		Title:          cmd.Dto.Title,
+		Author:         cmd.Dto.Author,
+		Description:    cmd.Dto.Description,
+		Price:          cmd.Dto.Price,
+		PublishedAt:    cmd.Dto.PublishedAt,
+		CoverImage:     cmd.Dto.CoverImage,
+		Status:         bookmodel.StatusActive,
+		CreatedBy:      actorFromContext(ctx),
+		CreatedAt:      now,
+		UpdatedBy:      actorFromContext(ctx),
+		UpdatedAt:      now,
 	}
 
 	// Lưu vào database
-	err := h.bookRepo.Insert(ctx, book)
+	err = h.bookRepo.Insert(ctx, book)
 	if err != nil {
 		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
 	}
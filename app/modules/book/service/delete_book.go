@@ -17,9 +17,9 @@ type DeleteBookCommand struct {
 
 // IDeleteBookRepo interface cho repository delete operations
 type IDeleteBookRepo interface {
-	GetByID(ctx context.Context, id uuid.UUID) (*bookmodel.Book, error)
-	Delete(ctx context.Context, id uuid.UUID) error
-	SoftDelete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, organizationID uuid.UUID, id uuid.UUID) (*bookmodel.Book, error)
+	Delete(ctx context.Context, organizationID uuid.UUID, id uuid.UUID) error
+	SoftDelete(ctx context.Context, organizationID uuid.UUID, id uuid.UUID) error
 }
 
 // DeleteBookCommandHandler xử lý command xóa book
@@ -39,8 +39,18 @@ func (h *DeleteBookCommandHandler) Execute(ctx context.Context, cmd *DeleteBookC
 		return datatype.ErrBadRequest.WithError("Book ID is required")
 	}
 
-	// Kiểm tra book có tồn tại không
-	_, err := h.bookRepo.GetByID(ctx, cmd.ID)
+	// Chỉ admin được phép xóa vĩnh viễn
+	if !cmd.Soft && !datatype.IsAdmin(datatype.GetRequester(ctx)) {
+		return datatype.ErrForbidden.WithReason("Only admin can permanently delete a book")
+	}
+
+	organizationID, err := organizationFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Kiểm tra book có tồn tại trong tổ chức không
+	_, err = h.bookRepo.GetByID(ctx, organizationID, cmd.ID)
 	if err != nil {
 		if err.Error() == "book not found" {
 			return datatype.ErrNotFound.WithError("Book not found")
@@ -50,9 +60,9 @@ func (h *DeleteBookCommandHandler) Execute(ctx context.Context, cmd *DeleteBookC
 
 	// Thực hiện xóa book
 	if cmd.Soft {
-		err = h.bookRepo.SoftDelete(ctx, cmd.ID)
+		err = h.bookRepo.SoftDelete(ctx, organizationID, cmd.ID)
 	} else {
-		err = h.bookRepo.Delete(ctx, cmd.ID)
+		err = h.bookRepo.Delete(ctx, organizationID, cmd.ID)
 	}
 
 	if err != nil {
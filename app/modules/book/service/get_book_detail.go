@@ -16,7 +16,7 @@ type GetBookDetailQuery struct {
 
 // IGetBookDetailRepo interface cho repository read operations
 type IGetBookDetailRepo interface {
-	GetByID(ctx context.Context, id uuid.UUID) (*bookmodel.Book, error)
+	GetByID(ctx context.Context, organizationID uuid.UUID, id uuid.UUID) (*bookmodel.Book, error)
 }
 
 // GetBookDetailQueryHandler xử lý query lấy chi tiết book
@@ -36,8 +36,13 @@ func (h *GetBookDetailQueryHandler) Execute(ctx context.Context, query *GetBookD
 		return nil, datatype.ErrBadRequest.WithError("Book ID is required")
 	}
 
+	organizationID, err := organizationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Lấy book từ database
-	book, err := h.bookRepo.GetByID(ctx, query.ID)
+	book, err := h.bookRepo.GetByID(ctx, organizationID, query.ID)
 	if err != nil {
 		if err.Error() == "book not found" {
 			return nil, datatype.ErrNotFound.WithError("Book not found")
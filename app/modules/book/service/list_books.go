@@ -5,6 +5,8 @@ import (
 
 	bookmodel "fat2fast/ikv/modules/book/model"
 	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
 )
 
 // ListBooksQuery đại diện cho query lấy danh sách books
@@ -14,7 +16,7 @@ type ListBooksQuery struct {
 
 // IListBooksRepo interface cho repository list operations
 type IListBooksRepo interface {
-	GetList(ctx context.Context, filter *bookmodel.ListBookFilter) ([]*bookmodel.Book, int64, error)
+	GetList(ctx context.Context, organizationID uuid.UUID, filter *bookmodel.ListBookFilter) ([]*bookmodel.Book, int64, error)
 }
 
 // ListBooksQueryHandler xử lý query lấy danh sách books
@@ -29,11 +31,16 @@ func NewListBooksQueryHandler(bookRepo IListBooksRepo) *ListBooksQueryHandler {
 
 // Execute thực thi query lấy danh sách books
 func (h *ListBooksQueryHandler) Execute(ctx context.Context, query *ListBooksQuery) (*bookmodel.BookListResponse, error) {
+	organizationID, err := organizationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Validate và set default values cho filter
 	filter := h.normalizeFilter(query.Filter)
 
 	// Lấy danh sách books từ database
-	books, total, err := h.bookRepo.GetList(ctx, filter)
+	books, total, err := h.bookRepo.GetList(ctx, organizationID, filter)
 	if err != nil {
 		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
 	}
@@ -0,0 +1,31 @@
+package bookservice
+
+import (
+	"context"
+
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+)
+
+// actorSystem là người tạo/sửa book khi không có user đăng nhập (job, CLI)
+const actorSystem = "system"
+
+// actorFromContext trả về ID của user đang thao tác để ghi vào created_by/updated_by,
+// với token impersonation là admin đang impersonate chứ không phải user bị impersonate
+func actorFromContext(ctx context.Context) string {
+	if requester := datatype.GetRequester(ctx); requester != nil {
+		return datatype.RealActorID(requester).String()
+	}
+	return actorSystem
+}
+
+// organizationFromContext trả về tổ chức đang hoạt động của requester. Dữ liệu book thuộc về từng tổ chức
+// nên mọi command/query đều cần tổ chức, request không mang tổ chức bị từ chối.
+func organizationFromContext(ctx context.Context) (uuid.UUID, error) {
+	requester := datatype.GetRequester(ctx)
+	if requester == nil || requester.OrganizationID() == uuid.Nil {
+		return uuid.Nil, datatype.ErrForbidden.WithReason("requires an active organization")
+	}
+	return requester.OrganizationID(), nil
+}
@@ -17,8 +17,8 @@ type UpdateBookCommand struct {
 
 // IUpdateBookRepo interface cho repository update operations
 type IUpdateBookRepo interface {
-	GetByID(ctx context.Context, id uuid.UUID) (*bookmodel.Book, error)
-	UpdateFields(ctx context.Context, id uuid.UUID, fields map[string]interface{}) error
+	GetByID(ctx context.Context, organizationID uuid.UUID, id uuid.UUID) (*bookmodel.Book, error)
+	UpdateFields(ctx context.Context, organizationID uuid.UUID, id uuid.UUID, fields map[string]interface{}) error
 }
 
 // UpdateBookCommandHandler xử lý command cập nhật book
@@ -38,8 +38,13 @@ func (h *UpdateBookCommandHandler) Execute(ctx context.Context, cmd *UpdateBookC
 		return datatype.ErrBadRequest.WithError("Book ID is required")
 	}
 
-	// Kiểm tra book có tồn tại không
-	_, err := h.bookRepo.GetByID(ctx, cmd.ID)
+	organizationID, err := organizationFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Kiểm tra book có tồn tại trong tổ chức không
+	book, err := h.bookRepo.GetByID(ctx, organizationID, cmd.ID)
 	if err != nil {
 		if err.Error() == "book not found" {
 			return datatype.ErrNotFound.WithError("Book not found")
@@ -47,8 +52,13 @@ func (h *UpdateBookCommandHandler) Execute(ctx context.Context, cmd *UpdateBookC
 		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
 	}
 
+	// Chỉ admin được phép cấm book hoặc thay đổi status của book đã bị cấm
+	if err := h.checkStatusPermission(ctx, book, cmd.Dto.Status); err != nil {
+		return err
+	}
+
 	// Prepare update fields
-	updateFields := h.buildUpdateFields(&cmd.Dto)
+	updateFields := h.buildUpdateFields(&cmd.Dto, actorFromContext(ctx))
 
 	// Validate update fields
 	if err := h.validateUpdateFields(updateFields); err != nil {
@@ -56,7 +66,7 @@ func (h *UpdateBookCommandHandler) Execute(ctx context.Context, cmd *UpdateBookC
 	}
 
 	// Cập nhật book
-	err = h.bookRepo.UpdateFields(ctx, cmd.ID, updateFields)
+	err = h.bookRepo.UpdateFields(ctx, organizationID, cmd.ID, updateFields)
 	if err != nil {
 		if err.Error() == "book not found" {
 			return datatype.ErrNotFound.WithError("Book not found")
@@ -68,11 +78,11 @@ func (h *UpdateBookCommandHandler) Execute(ctx context.Context, cmd *UpdateBookC
 }
 
 // buildUpdateFields xây dựng map các fields cần update
-func (h *UpdateBookCommandHandler) buildUpdateFields(dto *bookmodel.UpdateBookRequest) map[string]interface{} {
+func (h *UpdateBookCommandHandler) buildUpdateFields(dto *bookmodel.UpdateBookRequest, updatedBy string) map[string]interface{} {
 	fields := make(map[string]interface{})
 
 	// Set updated_by
-	fields["updated_by"] = "system" // TODO: Lấy từ context user hiện tại
+	fields["updated_by"] = updatedBy
 
 	// Chỉ update các fields không empty
 	if dto.Title != "" {
@@ -100,6 +110,19 @@ func (h *UpdateBookCommandHandler) buildUpdateFields(dto *bookmodel.UpdateBookRe
 	return fields
 }
 
+// checkStatusPermission kiểm tra quyền thay đổi status liên quan tới trạng thái banned
+func (h *UpdateBookCommandHandler) checkStatusPermission(ctx context.Context, book *bookmodel.Book, status string) error {
+	if status == "" || datatype.IsAdmin(datatype.GetRequester(ctx)) {
+		return nil
+	}
+
+	if bookmodel.BookStatus(status) == bookmodel.StatusBanned || book.Status == bookmodel.StatusBanned {
+		return datatype.ErrForbidden.WithReason("Only admin can ban or unban a book")
+	}
+
+	return nil
+}
+
 // validateUpdateFields validate các fields cần update
 func (h *UpdateBookCommandHandler) validateUpdateFields(fields map[string]interface{}) error {
 	// Validate price
@@ -0,0 +1,49 @@
+package bookservice
+
+import (
+	"context"
+
+	bookmodel "fat2fast/ikv/modules/book/model"
+
+	"github.com/google/uuid"
+)
+
+// IUserDataRepo interface cho repository operations cần thiết khi xử lý dữ liệu cá nhân của user
+type IUserDataRepo interface {
+	ListByCreator(ctx context.Context, createdBy string) ([]*bookmodel.Book, error)
+	AnonymizeActor(ctx context.Context, actor string) error
+}
+
+// UserDataHandler trích xuất và xóa dữ liệu book gắn với một user
+type UserDataHandler struct {
+	bookRepo IUserDataRepo
+}
+
+// NewUserDataHandler tạo instance mới của UserDataHandler
+func NewUserDataHandler(bookRepo IUserDataRepo) *UserDataHandler {
+	return &UserDataHandler{bookRepo: bookRepo}
+}
+
+// Export trả về các book do user tạo, nil nếu user chưa tạo book nào
+func (h *UserDataHandler) Export(ctx context.Context, userID uuid.UUID) (*bookmodel.UserBookData, error) {
+	books, err := h.bookRepo.ListByCreator(ctx, userID.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(books) == 0 {
+		return nil, nil
+	}
+
+	data := &bookmodel.UserBookData{Books: make([]*bookmodel.BookResponse, len(books))}
+	for i, book := range books {
+		data.Books[i] = book.ToResponse()
+	}
+
+	return data, nil
+}
+
+// Erase gỡ user khỏi created_by/updated_by của các book. Book là nội dung của hệ thống
+// nên được giữ lại, chỉ thông tin người tạo/sửa bị ẩn danh.
+func (h *UserDataHandler) Erase(ctx context.Context, userID uuid.UUID) error {
+	return h.bookRepo.AnonymizeActor(ctx, userID.String())
+}
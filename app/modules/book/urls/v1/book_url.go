@@ -4,41 +4,49 @@ import (
 	"net/http"
 
 	bookhttpgin "fat2fast/ikv/modules/book/infras/controller/http-gin"
+	"fat2fast/ikv/shared"
+	"fat2fast/ikv/shared/datatype"
+	sharedinfras "fat2fast/ikv/shared/infras"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetRoutes trả về danh sách routes cho book module v1
-func GetRoutes(controller *bookhttpgin.BookHTTPController) []gin.RouteInfo {
-	return []gin.RouteInfo{
-		// GET / - Lấy danh sách books
+func GetRoutes(controller *bookhttpgin.BookHTTPController, mldProvider sharedinfras.IMiddlewareProvider) []shared.Route {
+	return []shared.Route{
+		// GET / - Lấy danh sách books của tổ chức đang hoạt động
 		{
 			Method:      http.MethodGet,
 			Path:        "",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireOrganization()},
 			HandlerFunc: controller.ActionListBooks,
 		},
 		// GET /:id - Lấy chi tiết book
 		{
 			Method:      http.MethodGet,
 			Path:        "/:id",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireOrganization()},
 			HandlerFunc: controller.ActionGetBookDetail,
 		},
-		// POST / - Tạo book mới
+		// POST / - Tạo book mới (chỉ admin hệ thống, đồng thời là owner/admin của tổ chức)
 		{
 			Method:      http.MethodPost,
 			Path:        "",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.CheckRoles(datatype.RoleAdmin), mldProvider.RequireOrganization(datatype.OrgRoleOwner, datatype.OrgRoleAdmin)},
 			HandlerFunc: controller.ActionCreateBook,
 		},
-		// PUT /:id - Cập nhật book
+		// PUT /:id - Cập nhật book (owner/admin của tổ chức)
 		{
 			Method:      http.MethodPut,
 			Path:        "/:id",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireOrganization(datatype.OrgRoleOwner, datatype.OrgRoleAdmin)},
 			HandlerFunc: controller.ActionUpdateBook,
 		},
-		// DELETE /:id - Xóa book
+		// DELETE /:id - Xóa book (owner/admin của tổ chức)
 		{
 			Method:      http.MethodDelete,
 			Path:        "/:id",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireOrganization(datatype.OrgRoleOwner, datatype.OrgRoleAdmin)},
 			HandlerFunc: controller.ActionDeleteBook,
 		},
 	}
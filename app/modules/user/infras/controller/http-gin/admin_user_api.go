@@ -0,0 +1,102 @@
+package userhttpgin
+
+import (
+	"net/http"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	userservice "fat2fast/ikv/modules/user/service"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ActionListUsers xử lý GET / - Admin lấy danh sách user có phân trang và bộ lọc
+func (uc *UserHTTPController) ActionListUsers(c *gin.Context) {
+	var query userservice.ListQuery
+
+	if err := c.ShouldBindQuery(&query.Paging); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+	if err := c.ShouldBindQuery(&query.Filter); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	result, err := uc.listQryHdl.Execute(c.Request.Context(), &query)
+	if err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseWithPaging(result, &query.Paging, query.Filter))
+}
+
+// ActionChangeUserRole xử lý PATCH /:id/role - Admin đổi role của user
+func (uc *UserHTTPController) ActionChangeUserRole(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+	userID := parseUserIDParam(c)
+
+	var requestBodyData usermodel.ChangeRoleForm
+
+	if err := c.ShouldBindJSON(&requestBodyData); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	cmd := &userservice.ChangeUserRoleCommand{
+		UserID:    userID,
+		ActorID:   requester.UserID(),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Dto:       requestBodyData,
+	}
+	if err := uc.changeRoleCmdHdl.Execute(c.Request.Context(), cmd); err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(true))
+}
+
+// ActionBanUser xử lý POST /:id/ban - Admin cấm user
+func (uc *UserHTTPController) ActionBanUser(c *gin.Context) {
+	uc.changeUserStatus(c, userservice.UserStatusActionBan)
+}
+
+// ActionUnbanUser xử lý POST /:id/unban - Admin bỏ cấm user
+func (uc *UserHTTPController) ActionUnbanUser(c *gin.Context) {
+	uc.changeUserStatus(c, userservice.UserStatusActionUnban)
+}
+
+// ActionDeleteUser xử lý DELETE /:id - Admin xóa mềm user
+func (uc *UserHTTPController) ActionDeleteUser(c *gin.Context) {
+	uc.changeUserStatus(c, userservice.UserStatusActionDelete)
+}
+
+// ActionRestoreUser xử lý POST /:id/restore - Admin khôi phục user đã xóa mềm
+func (uc *UserHTTPController) ActionRestoreUser(c *gin.Context) {
+	uc.changeUserStatus(c, userservice.UserStatusActionRestore)
+}
+
+func (uc *UserHTTPController) changeUserStatus(c *gin.Context, action string) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	cmd := &userservice.ChangeUserStatusCommand{
+		UserID:    parseUserIDParam(c),
+		ActorID:   requester.UserID(),
+		Action:    action,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	if err := uc.changeStatusCmdHdl.Execute(c.Request.Context(), cmd); err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(true))
+}
+
+// parseUserIDParam đọc user ID từ URL param :id
+func parseUserIDParam(c *gin.Context) uuid.UUID {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithError("Invalid user ID format"))
+	}
+	return userID
+}
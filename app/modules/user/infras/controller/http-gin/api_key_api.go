@@ -0,0 +1,71 @@
+package userhttpgin
+
+import (
+	"net/http"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	userservice "fat2fast/ikv/modules/user/service"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ActionCreateAPIKey xử lý POST /api-keys - Tạo API key, khóa đầy đủ chỉ trả về một lần
+func (uc *UserHTTPController) ActionCreateAPIKey(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	var requestBodyData usermodel.CreateAPIKeyForm
+
+	if err := c.ShouldBindJSON(&requestBodyData); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	cmd := &userservice.CreateAPIKeyCommand{
+		UserID:    requester.UserID(),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Dto:       requestBodyData,
+	}
+	result, err := uc.createAPIKeyCmdHdl.Execute(c.Request.Context(), cmd)
+	if err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusCreated, datatype.ResponseSuccess(result))
+}
+
+// ActionListAPIKeys xử lý GET /api-keys - Danh sách API key còn hiệu lực của user
+func (uc *UserHTTPController) ActionListAPIKeys(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	query := &userservice.ListAPIKeysQuery{UserID: requester.UserID()}
+	result, err := uc.listAPIKeysQryHdl.Execute(c.Request.Context(), query)
+	if err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(result))
+}
+
+// ActionRevokeAPIKey xử lý DELETE /api-keys/:keyId - Thu hồi API key
+func (uc *UserHTTPController) ActionRevokeAPIKey(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	keyID, err := uuid.Parse(c.Param("keyId"))
+	if err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithError("Invalid API key ID format"))
+	}
+
+	cmd := &userservice.RevokeAPIKeyCommand{
+		UserID:    requester.UserID(),
+		KeyID:     keyID,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	if err := uc.revokeAPIKeyCmdHdl.Execute(c.Request.Context(), cmd); err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(true))
+}
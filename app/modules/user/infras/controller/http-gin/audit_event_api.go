@@ -0,0 +1,29 @@
+package userhttpgin
+
+import (
+	"net/http"
+
+	userservice "fat2fast/ikv/modules/user/service"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActionListAuditEvents xử lý GET /audit-events - Admin tra cứu sự kiện audit theo user, loại sự kiện và khoảng thời gian
+func (uc *UserHTTPController) ActionListAuditEvents(c *gin.Context) {
+	var query userservice.ListAuditEventsQuery
+
+	if err := c.ShouldBindQuery(&query.Paging); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+	if err := c.ShouldBindQuery(&query.Filter); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	result, err := uc.listAuditEventsQryHdl.Execute(c.Request.Context(), &query)
+	if err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseWithPaging(result, &query.Paging, query.Filter))
+}
@@ -17,7 +17,9 @@ func (uc *UserHTTPController) ActionAuthenticate(c *gin.Context) {
 		panic(datatype.ErrBadRequest.WithWrap(err))
 	}
 	cmd := &userservice.AuthenticateCommand{
-		Dto: requestBodyData,
+		Dto:       requestBodyData,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
 	}
 	result, err := uc.authenticateCmdHdl.Execute(c, cmd)
 	if err != nil {
@@ -0,0 +1,81 @@
+package userhttpgin
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	userservice "fat2fast/ikv/modules/user/service"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/gin-gonic/gin"
+)
+
+// avatarFormField là tên field multipart chứa file ảnh đại diện
+const avatarFormField = "avatar"
+
+// multipartOverhead là dung lượng cho phép thêm ngoài file: boundary, header của các part và field nhỏ đi kèm
+const multipartOverhead = 64 << 10
+
+// ActionUpdateMyAvatar xử lý PUT /me/avatar - Tải lên ảnh đại diện (multipart/form-data, field "avatar")
+func (uc *UserHTTPController) ActionUpdateMyAvatar(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	// Giới hạn cả body để các part đứng trước part ảnh không thể đọc không giới hạn
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, uc.updateAvatarCmdHdl.MaxFileSize()+multipartOverhead)
+
+	file, err := multipartFile(c, avatarFormField)
+	if err != nil {
+		panic(err)
+	}
+
+	cmd := &userservice.UpdateAvatarCommand{
+		UserID: requester.UserID(),
+		File:   file,
+	}
+	result, err := uc.updateAvatarCmdHdl.Execute(c.Request.Context(), cmd)
+	if err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(result))
+}
+
+// ActionDeleteMyAvatar xử lý DELETE /me/avatar - Xóa ảnh đại diện
+func (uc *UserHTTPController) ActionDeleteMyAvatar(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	cmd := &userservice.DeleteAvatarCommand{UserID: requester.UserID()}
+	if err := uc.deleteAvatarCmdHdl.Execute(c.Request.Context(), cmd); err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(true))
+}
+
+// multipartFile đọc body multipart dạng stream tới part của field, không lưu file tạm ra đĩa;
+// body đã được giới hạn bởi http.MaxBytesReader, dung lượng file do handler kiểm tra khi đọc part
+func multipartFile(c *gin.Context, field string) (io.Reader, error) {
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, datatype.ErrUnsupportedMediaType.WithWrap(err).WithDebug(err.Error())
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, datatype.ErrBadRequest.WithError(usermodel.ErrAvatarFileRequired.Error())
+		}
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return nil, datatype.ErrRequestEntityTooLarge.WithError(usermodel.ErrAvatarTooLarge.Error())
+			}
+			return nil, datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error())
+		}
+		if part.FormName() == field && part.FileName() != "" {
+			return part, nil
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 
 	usermodel "fat2fast/ikv/modules/user/model"
 	usersevice "fat2fast/ikv/modules/user/service"
+	sharecomponent "fat2fast/ikv/shared/component"
+	"fat2fast/ikv/shared/datatype"
 )
 
 type ICreateCommandHandler interface {
@@ -19,35 +21,280 @@ type IGetProfileQueryHandler interface {
 type IUpdateProfileCommandHandler interface {
 	Execute(ctx context.Context, cmd *usersevice.UpdateProfileCommand) error
 }
+type IRefreshTokenCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.RefreshTokenCommand) (*usersevice.AuthenticateResult, error)
+}
+type ILogoutCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.LogoutCommand) error
+}
+type ILogoutAllCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.LogoutAllCommand) error
+}
+type IOAuthAuthorizeCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.OAuthAuthorizeCommand) (*usersevice.OAuthAuthorizeResult, error)
+}
+type IOAuthCallbackCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.OAuthCallbackCommand) (*usersevice.AuthenticateResult, error)
+}
+type IVerifyEmailCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.VerifyEmailCommand) error
+}
+type IResendVerificationCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.ResendVerificationCommand) error
+}
+type IForgotPasswordCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.ForgotPasswordCommand) error
+}
+type IResetPasswordCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.ResetPasswordCommand) error
+}
+type IChangePasswordCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.ChangePasswordCommand) (*usersevice.AuthenticateResult, error)
+}
+type IUnlockAccountCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.UnlockAccountCommand) error
+}
+type IEnrollTotpCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.EnrollTotpCommand) (*usersevice.EnrollTotpResult, error)
+}
+type IConfirmTotpCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.ConfirmTotpCommand) (*usersevice.ConfirmTotpResult, error)
+}
+type ITwoFactorLoginCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.TwoFactorLoginCommand) (*usersevice.AuthenticateResult, error)
+}
+type IListQueryHandler interface {
+	Execute(ctx context.Context, query *usersevice.ListQuery) ([]*usermodel.ProfileResponse, error)
+}
+type IChangeUserRoleCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.ChangeUserRoleCommand) error
+}
+type IChangeUserStatusCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.ChangeUserStatusCommand) error
+}
+type ICreateAPIKeyCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.CreateAPIKeyCommand) (*usermodel.APIKeyCreatedResponse, error)
+}
+type IListAPIKeysQueryHandler interface {
+	Execute(ctx context.Context, query *usersevice.ListAPIKeysQuery) ([]*usermodel.APIKeyResponse, error)
+}
+type IRevokeAPIKeyCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.RevokeAPIKeyCommand) error
+}
+type IListSessionsQueryHandler interface {
+	Execute(ctx context.Context, query *usersevice.ListSessionsQuery) ([]*usermodel.SessionResponse, error)
+}
+type IRevokeSessionCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.RevokeSessionCommand) error
+}
+type IExportUserDataCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.ExportUserDataCommand) (*usermodel.UserDataArchive, error)
+}
+type IEraseUserDataCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.EraseUserDataCommand) error
+}
+type IChangeEmailCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.ChangeEmailCommand) error
+}
+type IConfirmEmailChangeCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.ConfirmEmailChangeCommand) error
+}
+type IUpdateAvatarCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.UpdateAvatarCommand) (*usermodel.AvatarResponse, error)
+	MaxFileSize() int64
+}
+type IDeleteAvatarCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.DeleteAvatarCommand) error
+}
+type IListAuditEventsQueryHandler interface {
+	Execute(ctx context.Context, query *usersevice.ListAuditEventsQuery) ([]*usermodel.AuditEvent, error)
+}
+type ICreateOrganizationCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.CreateOrganizationCommand) (*usermodel.OrganizationResponse, error)
+}
+type IListOrganizationsQueryHandler interface {
+	Execute(ctx context.Context, query *usersevice.ListOrganizationsQuery) ([]*usermodel.OrganizationResponse, error)
+}
+type IListOrganizationMembersQueryHandler interface {
+	Execute(ctx context.Context, query *usersevice.ListOrganizationMembersQuery) ([]*usermodel.OrganizationMemberResponse, error)
+}
+type IInviteOrganizationMemberCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.InviteOrganizationMemberCommand) (*usermodel.OrganizationInvitationResponse, error)
+}
+type IAcceptOrganizationInvitationCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.AcceptOrganizationInvitationCommand) (*usermodel.OrganizationResponse, error)
+}
+type IChangeOrganizationMemberRoleCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.ChangeOrganizationMemberRoleCommand) error
+}
+type IRemoveOrganizationMemberCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.RemoveOrganizationMemberCommand) error
+}
+type ISwitchOrganizationCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.SwitchOrganizationCommand) (*usersevice.AuthenticateResult, error)
+}
+type IImpersonateUserCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.ImpersonateUserCommand) (*usersevice.AuthenticateResult, error)
+}
+type ISendPhoneVerificationCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.SendPhoneVerificationCommand) error
+}
+type IVerifyPhoneCommandHandler interface {
+	Execute(ctx context.Context, cmd *usersevice.VerifyPhoneCommand) error
+}
+type IIntrospectQueryHandler interface {
+	Execute(ctx context.Context, query *usersevice.IntrospectQuery) (*datatype.IntrospectionResult, error)
+}
+type IJwksProvider interface {
+	JWKS() sharecomponent.JWKSet
+}
+
+// UserHandlers gom các handler truyền vào NewUserHTTPController, khởi tạo theo tên trường để không truyền nhầm thứ tự
+type UserHandlers struct {
+	CreateCmdHdl             ICreateCommandHandler
+	AuthenticateCmdHdl       IAuthenticateCommandHandler
+	GetProfileQryHdl         IGetProfileQueryHandler
+	UpdateProfileCmdHdl      IUpdateProfileCommandHandler
+	RefreshTokenCmdHdl       IRefreshTokenCommandHandler
+	LogoutCmdHdl             ILogoutCommandHandler
+	LogoutAllCmdHdl          ILogoutAllCommandHandler
+	JwksProvider             IJwksProvider
+	OauthAuthorizeCmdHdl     IOAuthAuthorizeCommandHandler
+	OauthCallbackCmdHdl      IOAuthCallbackCommandHandler
+	VerifyEmailCmdHdl        IVerifyEmailCommandHandler
+	ResendVerificationCmdHdl IResendVerificationCommandHandler
+	ForgotPasswordCmdHdl     IForgotPasswordCommandHandler
+	ResetPasswordCmdHdl      IResetPasswordCommandHandler
+	ChangePasswordCmdHdl     IChangePasswordCommandHandler
+	UnlockAccountCmdHdl      IUnlockAccountCommandHandler
+	EnrollTotpCmdHdl         IEnrollTotpCommandHandler
+	ConfirmTotpCmdHdl        IConfirmTotpCommandHandler
+	TwoFactorLoginCmdHdl     ITwoFactorLoginCommandHandler
+	ListQryHdl               IListQueryHandler
+	ChangeRoleCmdHdl         IChangeUserRoleCommandHandler
+	ChangeStatusCmdHdl       IChangeUserStatusCommandHandler
+	CreateAPIKeyCmdHdl       ICreateAPIKeyCommandHandler
+	ListAPIKeysQryHdl        IListAPIKeysQueryHandler
+	RevokeAPIKeyCmdHdl       IRevokeAPIKeyCommandHandler
+	ListSessionsQryHdl       IListSessionsQueryHandler
+	RevokeSessionCmdHdl      IRevokeSessionCommandHandler
+	ExportUserDataCmdHdl     IExportUserDataCommandHandler
+	EraseUserDataCmdHdl      IEraseUserDataCommandHandler
+	ChangeEmailCmdHdl        IChangeEmailCommandHandler
+	ConfirmEmailChangeCmdHdl IConfirmEmailChangeCommandHandler
+	UpdateAvatarCmdHdl       IUpdateAvatarCommandHandler
+	DeleteAvatarCmdHdl       IDeleteAvatarCommandHandler
+	ListAuditEventsQryHdl    IListAuditEventsQueryHandler
+	CreateOrganizationCmdHdl ICreateOrganizationCommandHandler
+	ListOrganizationsQryHdl  IListOrganizationsQueryHandler
+	ListOrgMembersQryHdl     IListOrganizationMembersQueryHandler
+	InviteOrgMemberCmdHdl    IInviteOrganizationMemberCommandHandler
+	AcceptInvitationCmdHdl   IAcceptOrganizationInvitationCommandHandler
+	ChangeMemberRoleCmdHdl   IChangeOrganizationMemberRoleCommandHandler
+	RemoveOrgMemberCmdHdl    IRemoveOrganizationMemberCommandHandler
+	SwitchOrganizationCmdHdl ISwitchOrganizationCommandHandler
+	ImpersonateUserCmdHdl    IImpersonateUserCommandHandler
+	SendPhoneCodeCmdHdl      ISendPhoneVerificationCommandHandler
+	VerifyPhoneCmdHdl        IVerifyPhoneCommandHandler
+	IntrospectQryHdl         IIntrospectQueryHandler
+}
 
 type UserHTTPController struct {
-	createCmdHdl        ICreateCommandHandler
-	authenticateCmdHdl  IAuthenticateCommandHandler
-	getProfileQryHdl    IGetProfileQueryHandler
-	updateProfileCmdHdl IUpdateProfileCommandHandler
-	// updateCmdHdl    IUpdateByIdCommandHandler
-	// deleteCmdHdl    IDeleteByIdCommandHandler
-	// listQryHdl      IListQueryHandler
+	createCmdHdl             ICreateCommandHandler
+	authenticateCmdHdl       IAuthenticateCommandHandler
+	getProfileQryHdl         IGetProfileQueryHandler
+	updateProfileCmdHdl      IUpdateProfileCommandHandler
+	refreshTokenCmdHdl       IRefreshTokenCommandHandler
+	logoutCmdHdl             ILogoutCommandHandler
+	logoutAllCmdHdl          ILogoutAllCommandHandler
+	jwksProvider             IJwksProvider
+	oauthAuthorizeCmdHdl     IOAuthAuthorizeCommandHandler
+	oauthCallbackCmdHdl      IOAuthCallbackCommandHandler
+	verifyEmailCmdHdl        IVerifyEmailCommandHandler
+	resendVerificationCmdHdl IResendVerificationCommandHandler
+	forgotPasswordCmdHdl     IForgotPasswordCommandHandler
+	resetPasswordCmdHdl      IResetPasswordCommandHandler
+	changePasswordCmdHdl     IChangePasswordCommandHandler
+	unlockAccountCmdHdl      IUnlockAccountCommandHandler
+	enrollTotpCmdHdl         IEnrollTotpCommandHandler
+	confirmTotpCmdHdl        IConfirmTotpCommandHandler
+	twoFactorLoginCmdHdl     ITwoFactorLoginCommandHandler
+	listQryHdl               IListQueryHandler
+	changeRoleCmdHdl         IChangeUserRoleCommandHandler
+	changeStatusCmdHdl       IChangeUserStatusCommandHandler
+	createAPIKeyCmdHdl       ICreateAPIKeyCommandHandler
+	listAPIKeysQryHdl        IListAPIKeysQueryHandler
+	revokeAPIKeyCmdHdl       IRevokeAPIKeyCommandHandler
+	listSessionsQryHdl       IListSessionsQueryHandler
+	revokeSessionCmdHdl      IRevokeSessionCommandHandler
+	exportUserDataCmdHdl     IExportUserDataCommandHandler
+	eraseUserDataCmdHdl      IEraseUserDataCommandHandler
+	changeEmailCmdHdl        IChangeEmailCommandHandler
+	confirmEmailChangeCmdHdl IConfirmEmailChangeCommandHandler
+	updateAvatarCmdHdl       IUpdateAvatarCommandHandler
+	deleteAvatarCmdHdl       IDeleteAvatarCommandHandler
+	listAuditEventsQryHdl    IListAuditEventsQueryHandler
+	createOrganizationCmdHdl ICreateOrganizationCommandHandler
+	listOrganizationsQryHdl  IListOrganizationsQueryHandler
+	listOrgMembersQryHdl     IListOrganizationMembersQueryHandler
+	inviteOrgMemberCmdHdl    IInviteOrganizationMemberCommandHandler
+	acceptInvitationCmdHdl   IAcceptOrganizationInvitationCommandHandler
+	changeMemberRoleCmdHdl   IChangeOrganizationMemberRoleCommandHandler
+	removeOrgMemberCmdHdl    IRemoveOrganizationMemberCommandHandler
+	switchOrganizationCmdHdl ISwitchOrganizationCommandHandler
+	impersonateUserCmdHdl    IImpersonateUserCommandHandler
+	sendPhoneCodeCmdHdl      ISendPhoneVerificationCommandHandler
+	verifyPhoneCmdHdl        IVerifyPhoneCommandHandler
+	introspectQryHdl         IIntrospectQueryHandler
 }
 
-func NewUserHTTPController(
-	createCmdHdl ICreateCommandHandler,
-	authenticateCmdHdl IAuthenticateCommandHandler,
-	getProfileQryHdl IGetProfileQueryHandler,
-	updateProfileCmdHdl IUpdateProfileCommandHandler,
-	// updateCmdHdl IUpdateByIdCommandHandler,
-	// deleteCmdHdl IDeleteByIdCommandHandler,
-	// listQryHdl IListQueryHandler,
-	// repoRPCCategory IRepoRPCCategory,
-) *UserHTTPController {
+func NewUserHTTPController(handlers UserHandlers) *UserHTTPController {
 	return &UserHTTPController{
-		createCmdHdl:        createCmdHdl,
-		authenticateCmdHdl:  authenticateCmdHdl,
-		getProfileQryHdl:    getProfileQryHdl,
-		updateProfileCmdHdl: updateProfileCmdHdl,
-		// updateCmdHdl:    updateCmdHdl,
-		// deleteCmdHdl:    deleteCmdHdl,
-		// listQryHdl:      listQryHdl,
-		// repoRPCCategory: repoRPCCategory,
+		createCmdHdl:             handlers.CreateCmdHdl,
+		authenticateCmdHdl:       handlers.AuthenticateCmdHdl,
+		getProfileQryHdl:         handlers.GetProfileQryHdl,
+		updateProfileCmdHdl:      handlers.UpdateProfileCmdHdl,
+		refreshTokenCmdHdl:       handlers.RefreshTokenCmdHdl,
+		logoutCmdHdl:             handlers.LogoutCmdHdl,
+		logoutAllCmdHdl:          handlers.LogoutAllCmdHdl,
+		jwksProvider:             handlers.JwksProvider,
+		oauthAuthorizeCmdHdl:     handlers.OauthAuthorizeCmdHdl,
+		oauthCallbackCmdHdl:      handlers.OauthCallbackCmdHdl,
+		verifyEmailCmdHdl:        handlers.VerifyEmailCmdHdl,
+		resendVerificationCmdHdl: handlers.ResendVerificationCmdHdl,
+		forgotPasswordCmdHdl:     handlers.ForgotPasswordCmdHdl,
+		resetPasswordCmdHdl:      handlers.ResetPasswordCmdHdl,
+		changePasswordCmdHdl:     handlers.ChangePasswordCmdHdl,
+		unlockAccountCmdHdl:      handlers.UnlockAccountCmdHdl,
+		enrollTotpCmdHdl:         handlers.EnrollTotpCmdHdl,
+		confirmTotpCmdHdl:        handlers.ConfirmTotpCmdHdl,
+		twoFactorLoginCmdHdl:     handlers.TwoFactorLoginCmdHdl,
+		listQryHdl:               handlers.ListQryHdl,
+		changeRoleCmdHdl:         handlers.ChangeRoleCmdHdl,
+		changeStatusCmdHdl:       handlers.ChangeStatusCmdHdl,
+		createAPIKeyCmdHdl:       handlers.CreateAPIKeyCmdHdl,
+		listAPIKeysQryHdl:        handlers.ListAPIKeysQryHdl,
+		revokeAPIKeyCmdHdl:       handlers.RevokeAPIKeyCmdHdl,
+		listSessionsQryHdl:       handlers.ListSessionsQryHdl,
+		revokeSessionCmdHdl:      handlers.RevokeSessionCmdHdl,
+		exportUserDataCmdHdl:     handlers.ExportUserDataCmdHdl,
+		eraseUserDataCmdHdl:      handlers.EraseUserDataCmdHdl,
+		changeEmailCmdHdl:        handlers.ChangeEmailCmdHdl,
+		confirmEmailChangeCmdHdl: handlers.ConfirmEmailChangeCmdHdl,
+		updateAvatarCmdHdl:       handlers.UpdateAvatarCmdHdl,
+		deleteAvatarCmdHdl:       handlers.DeleteAvatarCmdHdl,
+		listAuditEventsQryHdl:    handlers.ListAuditEventsQryHdl,
+		createOrganizationCmdHdl: handlers.CreateOrganizationCmdHdl,
+		listOrganizationsQryHdl:  handlers.ListOrganizationsQryHdl,
+		listOrgMembersQryHdl:     handlers.ListOrgMembersQryHdl,
+		inviteOrgMemberCmdHdl:    handlers.InviteOrgMemberCmdHdl,
+		acceptInvitationCmdHdl:   handlers.AcceptInvitationCmdHdl,
+		changeMemberRoleCmdHdl:   handlers.ChangeMemberRoleCmdHdl,
+		removeOrgMemberCmdHdl:    handlers.RemoveOrgMemberCmdHdl,
+		switchOrganizationCmdHdl: handlers.SwitchOrganizationCmdHdl,
+		impersonateUserCmdHdl:    handlers.ImpersonateUserCmdHdl,
+		sendPhoneCodeCmdHdl:      handlers.SendPhoneCodeCmdHdl,
+		verifyPhoneCmdHdl:        handlers.VerifyPhoneCmdHdl,
+		introspectQryHdl:         handlers.IntrospectQryHdl,
 	}
 }
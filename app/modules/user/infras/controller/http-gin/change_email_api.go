@@ -0,0 +1,54 @@
+package userhttpgin
+
+import (
+	"net/http"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	userservice "fat2fast/ikv/modules/user/service"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActionChangeEmail xử lý POST /email/change - Gửi link xác nhận tới email mới, email chỉ đổi sau khi xác nhận
+func (uc *UserHTTPController) ActionChangeEmail(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	var requestBodyData usermodel.ChangeEmailForm
+
+	if err := c.ShouldBindJSON(&requestBodyData); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	cmd := &userservice.ChangeEmailCommand{
+		UserID:    requester.UserID(),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Dto:       requestBodyData,
+	}
+	if err := uc.changeEmailCmdHdl.Execute(c.Request.Context(), cmd); err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(true))
+}
+
+// ActionConfirmEmailChange xử lý POST /email/change/confirm - Đổi email bằng token gửi tới email mới
+func (uc *UserHTTPController) ActionConfirmEmailChange(c *gin.Context) {
+	var requestBodyData usermodel.ConfirmEmailChangeForm
+
+	if err := c.ShouldBindJSON(&requestBodyData); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	cmd := &userservice.ConfirmEmailChangeCommand{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Dto:       requestBodyData,
+	}
+	if err := uc.confirmEmailChangeCmdHdl.Execute(c.Request.Context(), cmd); err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(true))
+}
@@ -0,0 +1,35 @@
+package userhttpgin
+
+import (
+	"net/http"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	userservice "fat2fast/ikv/modules/user/service"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActionChangePassword xử lý PUT /password - Đổi mật khẩu, trả về cặp token mới cho phiên hiện tại
+func (uc *UserHTTPController) ActionChangePassword(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	var requestBodyData usermodel.ChangePasswordForm
+
+	if err := c.ShouldBindJSON(&requestBodyData); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	cmd := &userservice.ChangePasswordCommand{
+		UserID:    requester.UserID(),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Dto:       requestBodyData,
+	}
+	result, err := uc.changePasswordCmdHdl.Execute(c.Request.Context(), cmd)
+	if err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(result))
+}
@@ -0,0 +1,63 @@
+package userhttpgin
+
+import (
+	"net/http"
+
+	userservice "fat2fast/ikv/modules/user/service"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActionExportMyData xử lý GET /data-export - User tải bản trích xuất dữ liệu cá nhân của mình
+func (uc *UserHTTPController) ActionExportMyData(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	cmd := &userservice.ExportUserDataCommand{
+		UserID:    requester.UserID(),
+		ActorID:   requester.UserID(),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	result, err := uc.exportUserDataCmdHdl.Execute(c.Request.Context(), cmd)
+	if err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(result))
+}
+
+// ActionExportUserData xử lý GET /:id/data-export - Admin trích xuất dữ liệu cá nhân của user
+func (uc *UserHTTPController) ActionExportUserData(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	cmd := &userservice.ExportUserDataCommand{
+		UserID:    parseUserIDParam(c),
+		ActorID:   requester.UserID(),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	result, err := uc.exportUserDataCmdHdl.Execute(c.Request.Context(), cmd)
+	if err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(result))
+}
+
+// ActionEraseUserData xử lý POST /:id/erase - Admin xóa dữ liệu cá nhân của user trên mọi module
+func (uc *UserHTTPController) ActionEraseUserData(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	cmd := &userservice.EraseUserDataCommand{
+		UserID:    parseUserIDParam(c),
+		ActorID:   requester.UserID(),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	if err := uc.eraseUserDataCmdHdl.Execute(c.Request.Context(), cmd); err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(true))
+}
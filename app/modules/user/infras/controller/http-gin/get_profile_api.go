@@ -10,8 +10,16 @@ import (
 	"github.com/google/uuid"
 )
 
-// ActionGetProfile xử lý GET /profile/:id - Lấy thông tin profile user
+// ActionGetMyProfile xử lý GET /me - Lấy thông tin profile của user đang đăng nhập
+func (c *UserHTTPController) ActionGetMyProfile(ctx *gin.Context) {
+	requester := ctx.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	c.getProfile(ctx, requester, requester.UserID())
+}
+
+// ActionGetProfile xử lý GET /profile/:id - Lấy thông tin profile user (chủ sở hữu hoặc admin)
 func (c *UserHTTPController) ActionGetProfile(ctx *gin.Context) {
+	requester := ctx.MustGet(datatype.KeyRequester).(datatype.Requester)
 
 	// Parse user ID
 	userID, err := uuid.Parse(ctx.Param("id"))
@@ -19,9 +27,14 @@ func (c *UserHTTPController) ActionGetProfile(ctx *gin.Context) {
 		panic(datatype.ErrBadRequest.WithWrap(err).WithError("Invalid user ID format"))
 	}
 
+	c.getProfile(ctx, requester, userID)
+}
+
+func (c *UserHTTPController) getProfile(ctx *gin.Context, requester datatype.Requester, userID uuid.UUID) {
 	// Tạo query
 	query := &userservice.GetProfileQuery{
-		UserID: userID,
+		UserID:    userID,
+		Requester: requester,
 	}
 
 	// Thực thi query
@@ -0,0 +1,37 @@
+package userhttpgin
+
+import (
+	"net/http"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	userservice "fat2fast/ikv/modules/user/service"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActionImpersonateUser xử lý POST /:id/impersonate - Admin lấy token ngắn hạn để thao tác dưới danh nghĩa user
+func (uc *UserHTTPController) ActionImpersonateUser(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+	userID := parseUserIDParam(c)
+
+	var requestBodyData usermodel.ImpersonateForm
+
+	if err := c.ShouldBindJSON(&requestBodyData); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	cmd := &userservice.ImpersonateUserCommand{
+		UserID:    userID,
+		ActorID:   requester.UserID(),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Dto:       requestBodyData,
+	}
+	result, err := uc.impersonateUserCmdHdl.Execute(c.Request.Context(), cmd)
+	if err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(result))
+}
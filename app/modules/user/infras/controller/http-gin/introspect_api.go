@@ -0,0 +1,41 @@
+package userhttpgin
+
+import (
+	"net/http"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	userservice "fat2fast/ikv/modules/user/service"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActionIntrospect xử lý POST /introspect - Service khác kiểm tra access token/API key (RFC 7662).
+// Service gọi xác thực bằng HTTP Basic (client ID/secret); kết quả trả về đúng định dạng RFC 7662
+// thay vì bọc trong AppResponse.
+func (uc *UserHTTPController) ActionIntrospect(c *gin.Context) {
+	clientID, clientSecret, ok := c.Request.BasicAuth()
+	if !ok {
+		c.Header("WWW-Authenticate", `Basic realm="introspect"`)
+		panic(datatype.ErrUnauthorized.WithError(usermodel.ErrInvalidServiceClient.Error()))
+	}
+
+	var requestBodyData usermodel.IntrospectForm
+
+	if err := c.ShouldBind(&requestBodyData); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	query := &userservice.IntrospectQuery{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Dto:          requestBodyData,
+	}
+	result, err := uc.introspectQryHdl.Execute(c.Request.Context(), query)
+	if err != nil {
+		panic(err)
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, result)
+}
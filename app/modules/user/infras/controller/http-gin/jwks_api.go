@@ -0,0 +1,14 @@
+package userhttpgin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActionJWKS xử lý GET /.well-known/jwks.json - Công bố public key để service khác verify token.
+// Trả về đúng định dạng JWK Set (RFC 7517) thay vì bọc trong AppResponse để client JWT chuẩn đọc được.
+func (uc *UserHTTPController) ActionJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, uc.jwksProvider.JWKS())
+}
@@ -0,0 +1,54 @@
+package userhttpgin
+
+import (
+	"net/http"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	userservice "fat2fast/ikv/modules/user/service"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActionLogout xử lý POST /logout - Thu hồi access token hiện tại và refresh token đi kèm
+func (uc *UserHTTPController) ActionLogout(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	var requestBodyData usermodel.LogoutForm
+	// Body là tùy chọn, chỉ cần khi muốn thu hồi refresh token
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&requestBodyData); err != nil {
+			panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+		}
+	}
+
+	cmd := &userservice.LogoutCommand{
+		UserID:    requester.UserID(),
+		TokenID:   requester.TokenID(),
+		SessionID: requester.SessionID(),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Dto:       requestBodyData,
+	}
+	if err := uc.logoutCmdHdl.Execute(c.Request.Context(), cmd); err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(true))
+}
+
+// ActionLogoutAll xử lý POST /logout/all - Đăng xuất khỏi mọi thiết bị
+func (uc *UserHTTPController) ActionLogoutAll(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	cmd := &userservice.LogoutAllCommand{
+		UserID:    requester.UserID(),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	if err := uc.logoutAllCmdHdl.Execute(c.Request.Context(), cmd); err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(true))
+}
@@ -0,0 +1,75 @@
+package userhttpgin
+
+import (
+	"net/http"
+	"strings"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	userservice "fat2fast/ikv/modules/user/service"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oauthStateCookie là cookie lưu giá trị băm của state, chỉ gửi kèm request tới route callback
+const oauthStateCookie = "oauth_state"
+
+// ActionOAuthAuthorize xử lý GET /oauth/:provider/authorize - Trả về URL đăng nhập của identity provider.
+// Thêm ?redirect=true để chuyển hướng trình duyệt ngay thay vì trả về JSON.
+// Trình duyệt phải gọi route này trực tiếp (hoặc kèm credentials) để nhận cookie state dùng ở bước callback.
+func (uc *UserHTTPController) ActionOAuthAuthorize(c *gin.Context) {
+	cmd := &userservice.OAuthAuthorizeCommand{Provider: c.Param("provider")}
+	result, err := uc.oauthAuthorizeCmdHdl.Execute(c.Request.Context(), cmd)
+	if err != nil {
+		panic(err)
+	}
+
+	// SameSite=Lax vẫn gửi cookie khi provider chuyển hướng (GET top-level) về callback
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, result.StateHash, 0, oauthCallbackPath(c), "", isSecureRequest(c), true)
+
+	if c.Query("redirect") == "true" {
+		c.Redirect(http.StatusFound, result.AuthorizationURL)
+		return
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(result))
+}
+
+// ActionOAuthCallback xử lý GET /oauth/:provider/callback - Provider chuyển hướng về kèm code và state
+func (uc *UserHTTPController) ActionOAuthCallback(c *gin.Context) {
+	var queryData usermodel.OAuthCallbackForm
+
+	if err := c.ShouldBindQuery(&queryData); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	// Cookie chỉ dùng được một lần, xóa trước khi xử lý để lần thử lại phải bắt đầu từ authorize
+	browserStateHash, _ := c.Cookie(oauthStateCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, "", -1, c.Request.URL.Path, "", isSecureRequest(c), true)
+
+	cmd := &userservice.OAuthCallbackCommand{
+		Provider:         c.Param("provider"),
+		IPAddress:        c.ClientIP(),
+		UserAgent:        c.Request.UserAgent(),
+		BrowserStateHash: browserStateHash,
+		Dto:              queryData,
+	}
+	result, err := uc.oauthCallbackCmdHdl.Execute(c.Request.Context(), cmd)
+	if err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(result))
+}
+
+// oauthCallbackPath trả về đường dẫn route callback cùng provider với route authorize đang xử lý
+func oauthCallbackPath(c *gin.Context) string {
+	return strings.TrimSuffix(c.Request.URL.Path, "/authorize") + "/callback"
+}
+
+// isSecureRequest cho biết request tới qua HTTPS (trực tiếp hoặc qua reverse proxy)
+func isSecureRequest(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
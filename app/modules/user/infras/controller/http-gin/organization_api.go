@@ -0,0 +1,202 @@
+package userhttpgin
+
+import (
+	"net/http"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	userservice "fat2fast/ikv/modules/user/service"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ActionCreateOrganization xử lý POST /organizations - Tạo tổ chức mới, người tạo là owner
+func (uc *UserHTTPController) ActionCreateOrganization(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	var requestBodyData usermodel.CreateOrganizationForm
+
+	if err := c.ShouldBindJSON(&requestBodyData); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	cmd := &userservice.CreateOrganizationCommand{
+		UserID:    requester.UserID(),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Dto:       requestBodyData,
+	}
+	result, err := uc.createOrganizationCmdHdl.Execute(c.Request.Context(), cmd)
+	if err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusCreated, datatype.ResponseSuccess(result))
+}
+
+// ActionListOrganizations xử lý GET /organizations - Danh sách tổ chức của user, đánh dấu tổ chức đang hoạt động
+func (uc *UserHTTPController) ActionListOrganizations(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	query := &userservice.ListOrganizationsQuery{
+		UserID:                requester.UserID(),
+		CurrentOrganizationID: requester.OrganizationID(),
+	}
+	result, err := uc.listOrganizationsQryHdl.Execute(c.Request.Context(), query)
+	if err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(result))
+}
+
+// ActionListOrganizationMembers xử lý GET /organizations/:orgId/members - Danh sách thành viên của tổ chức
+func (uc *UserHTTPController) ActionListOrganizationMembers(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	query := &userservice.ListOrganizationMembersQuery{
+		UserID:         requester.UserID(),
+		OrganizationID: parseOrganizationID(c),
+	}
+	result, err := uc.listOrgMembersQryHdl.Execute(c.Request.Context(), query)
+	if err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(result))
+}
+
+// ActionInviteOrganizationMember xử lý POST /organizations/:orgId/invitations - Gửi lời mời tham gia tổ chức (owner/admin)
+func (uc *UserHTTPController) ActionInviteOrganizationMember(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+	organizationID := parseOrganizationID(c)
+
+	var requestBodyData usermodel.InviteOrganizationMemberForm
+
+	if err := c.ShouldBindJSON(&requestBodyData); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	cmd := &userservice.InviteOrganizationMemberCommand{
+		UserID:         requester.UserID(),
+		OrganizationID: organizationID,
+		IPAddress:      c.ClientIP(),
+		UserAgent:      c.Request.UserAgent(),
+		Dto:            requestBodyData,
+	}
+	result, err := uc.inviteOrgMemberCmdHdl.Execute(c.Request.Context(), cmd)
+	if err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusCreated, datatype.ResponseSuccess(result))
+}
+
+// ActionAcceptOrganizationInvitation xử lý POST /organizations/invitations/accept - Chấp nhận lời mời bằng token trong email
+func (uc *UserHTTPController) ActionAcceptOrganizationInvitation(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	var requestBodyData usermodel.AcceptOrganizationInvitationForm
+
+	if err := c.ShouldBindJSON(&requestBodyData); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	cmd := &userservice.AcceptOrganizationInvitationCommand{
+		UserID:    requester.UserID(),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Dto:       requestBodyData,
+	}
+	result, err := uc.acceptInvitationCmdHdl.Execute(c.Request.Context(), cmd)
+	if err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(result))
+}
+
+// ActionChangeOrganizationMemberRole xử lý PATCH /organizations/:orgId/members/:userId/role - Đổi role của thành viên (owner)
+func (uc *UserHTTPController) ActionChangeOrganizationMemberRole(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+	organizationID := parseOrganizationID(c)
+	memberID := parseOrganizationMemberID(c)
+
+	var requestBodyData usermodel.ChangeOrganizationMemberRoleForm
+
+	if err := c.ShouldBindJSON(&requestBodyData); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	cmd := &userservice.ChangeOrganizationMemberRoleCommand{
+		UserID:         requester.UserID(),
+		OrganizationID: organizationID,
+		MemberID:       memberID,
+		IPAddress:      c.ClientIP(),
+		UserAgent:      c.Request.UserAgent(),
+		Dto:            requestBodyData,
+	}
+	if err := uc.changeMemberRoleCmdHdl.Execute(c.Request.Context(), cmd); err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(true))
+}
+
+// ActionRemoveOrganizationMember xử lý DELETE /organizations/:orgId/members/:userId - Xóa thành viên hoặc tự rời tổ chức
+func (uc *UserHTTPController) ActionRemoveOrganizationMember(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	cmd := &userservice.RemoveOrganizationMemberCommand{
+		UserID:         requester.UserID(),
+		OrganizationID: parseOrganizationID(c),
+		MemberID:       parseOrganizationMemberID(c),
+		IPAddress:      c.ClientIP(),
+		UserAgent:      c.Request.UserAgent(),
+	}
+	if err := uc.removeOrgMemberCmdHdl.Execute(c.Request.Context(), cmd); err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(true))
+}
+
+// ActionSwitchOrganization xử lý POST /organizations/:orgId/switch - Đổi tổ chức đang hoạt động của phiên, trả về access token mới
+func (uc *UserHTTPController) ActionSwitchOrganization(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	cmd := &userservice.SwitchOrganizationCommand{
+		UserID:         requester.UserID(),
+		SessionID:      requester.SessionID(),
+		OrganizationID: parseOrganizationID(c),
+		IPAddress:      c.ClientIP(),
+		UserAgent:      c.Request.UserAgent(),
+	}
+	result, err := uc.switchOrganizationCmdHdl.Execute(c.Request.Context(), cmd)
+	if err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(result))
+}
+
+// parseOrganizationID đọc ID tổ chức từ URL param orgId
+func parseOrganizationID(c *gin.Context) uuid.UUID {
+	organizationID, err := uuid.Parse(c.Param("orgId"))
+	if err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithError("Invalid organization ID format"))
+	}
+
+	return organizationID
+}
+
+// parseOrganizationMemberID đọc ID user của thành viên từ URL param userId
+func parseOrganizationMemberID(c *gin.Context) uuid.UUID {
+	memberID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithError("Invalid user ID format"))
+	}
+
+	return memberID
+}
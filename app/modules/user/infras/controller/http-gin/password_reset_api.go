@@ -0,0 +1,47 @@
+package userhttpgin
+
+import (
+	"net/http"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	userservice "fat2fast/ikv/modules/user/service"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActionForgotPassword xử lý POST /password/forgot - Gửi link đặt lại mật khẩu qua email
+func (uc *UserHTTPController) ActionForgotPassword(c *gin.Context) {
+	var requestBodyData usermodel.ForgotPasswordForm
+
+	if err := c.ShouldBindJSON(&requestBodyData); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	cmd := &userservice.ForgotPasswordCommand{Dto: requestBodyData}
+	if err := uc.forgotPasswordCmdHdl.Execute(c.Request.Context(), cmd); err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(true))
+}
+
+// ActionResetPassword xử lý POST /password/reset - Đặt mật khẩu mới bằng token trong email
+func (uc *UserHTTPController) ActionResetPassword(c *gin.Context) {
+	var requestBodyData usermodel.ResetPasswordForm
+
+	if err := c.ShouldBindJSON(&requestBodyData); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	cmd := &userservice.ResetPasswordCommand{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Dto:       requestBodyData,
+	}
+	if err := uc.resetPasswordCmdHdl.Execute(c.Request.Context(), cmd); err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(true))
+}
@@ -0,0 +1,49 @@
+package userhttpgin
+
+import (
+	"net/http"
+
+	userservice "fat2fast/ikv/modules/user/service"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ActionListSessions xử lý GET /sessions - Danh sách phiên đăng nhập còn hiệu lực của user
+func (uc *UserHTTPController) ActionListSessions(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	query := &userservice.ListSessionsQuery{
+		UserID:           requester.UserID(),
+		CurrentSessionID: requester.SessionID(),
+	}
+	result, err := uc.listSessionsQryHdl.Execute(c.Request.Context(), query)
+	if err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(result))
+}
+
+// ActionRevokeSession xử lý DELETE /sessions/:sessionId - Đăng xuất một thiết bị
+func (uc *UserHTTPController) ActionRevokeSession(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	sessionID, err := uuid.Parse(c.Param("sessionId"))
+	if err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithError("Invalid session ID format"))
+	}
+
+	cmd := &userservice.RevokeSessionCommand{
+		UserID:    requester.UserID(),
+		SessionID: sessionID,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	if err := uc.revokeSessionCmdHdl.Execute(c.Request.Context(), cmd); err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(true))
+}
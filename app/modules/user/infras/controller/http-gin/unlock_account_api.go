@@ -0,0 +1,27 @@
+package userhttpgin
+
+import (
+	"net/http"
+
+	userservice "fat2fast/ikv/modules/user/service"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActionUnlockAccount xử lý POST /:id/unlock - Admin mở khóa đăng nhập của user
+func (uc *UserHTTPController) ActionUnlockAccount(c *gin.Context) {
+	requester := c.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	cmd := &userservice.UnlockAccountCommand{
+		UserID:    parseUserIDParam(c),
+		ActorID:   requester.UserID(),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	if err := uc.unlockAccountCmdHdl.Execute(c.Request.Context(), cmd); err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(true))
+}
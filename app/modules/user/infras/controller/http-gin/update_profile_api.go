@@ -11,8 +11,16 @@ import (
 	"github.com/google/uuid"
 )
 
-// ActionUpdateProfile xử lý PUT /profile/:id - Cập nhật thông tin profile user
+// ActionUpdateMyProfile xử lý PUT /me - Cập nhật thông tin profile của user đang đăng nhập
+func (c *UserHTTPController) ActionUpdateMyProfile(ctx *gin.Context) {
+	requester := ctx.MustGet(datatype.KeyRequester).(datatype.Requester)
+
+	c.updateProfile(ctx, requester, requester.UserID())
+}
+
+// ActionUpdateProfile xử lý PUT /profile/:id - Cập nhật thông tin profile user (chủ sở hữu hoặc admin)
 func (c *UserHTTPController) ActionUpdateProfile(ctx *gin.Context) {
+	requester := ctx.MustGet(datatype.KeyRequester).(datatype.Requester)
 
 	// Parse user ID
 	userID, err := uuid.Parse(ctx.Param("id"))
@@ -20,6 +28,10 @@ func (c *UserHTTPController) ActionUpdateProfile(ctx *gin.Context) {
 		panic(datatype.ErrBadRequest.WithWrap(err).WithError("Invalid user ID format"))
 	}
 
+	c.updateProfile(ctx, requester, userID)
+}
+
+func (c *UserHTTPController) updateProfile(ctx *gin.Context, requester datatype.Requester, userID uuid.UUID) {
 	// Bind JSON request body
 	var requestData usermodel.UpdateProfileRequest
 	if err := ctx.ShouldBindJSON(&requestData); err != nil {
@@ -28,13 +40,13 @@ func (c *UserHTTPController) ActionUpdateProfile(ctx *gin.Context) {
 
 	// Tạo command
 	cmd := &userservice.UpdateProfileCommand{
-		UserID: userID,
-		Dto:    requestData,
+		UserID:    userID,
+		Requester: requester,
+		Dto:       requestData,
 	}
 
 	// Thực thi command
-	err = c.updateProfileCmdHdl.Execute(ctx.Request.Context(), cmd)
-	if err != nil {
+	if err := c.updateProfileCmdHdl.Execute(ctx.Request.Context(), cmd); err != nil {
 		panic(err)
 	}
 
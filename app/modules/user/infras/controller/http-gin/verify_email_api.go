@@ -0,0 +1,43 @@
+package userhttpgin
+
+import (
+	"net/http"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	userservice "fat2fast/ikv/modules/user/service"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActionVerifyEmail xử lý GET /email/verify - Kích hoạt tài khoản từ link trong email xác minh
+func (uc *UserHTTPController) ActionVerifyEmail(c *gin.Context) {
+	var queryData usermodel.VerifyEmailForm
+
+	if err := c.ShouldBindQuery(&queryData); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	cmd := &userservice.VerifyEmailCommand{Dto: queryData}
+	if err := uc.verifyEmailCmdHdl.Execute(c.Request.Context(), cmd); err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(true))
+}
+
+// ActionResendVerification xử lý POST /email/verification/resend - Gửi lại email xác minh
+func (uc *UserHTTPController) ActionResendVerification(c *gin.Context) {
+	var requestBodyData usermodel.ResendVerificationForm
+
+	if err := c.ShouldBindJSON(&requestBodyData); err != nil {
+		panic(datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	cmd := &userservice.ResendVerificationCommand{Dto: requestBodyData}
+	if err := uc.resendVerificationCmdHdl.Execute(c.Request.Context(), cmd); err != nil {
+		panic(err)
+	}
+
+	c.JSON(http.StatusOK, datatype.ResponseSuccess(true))
+}
@@ -0,0 +1,278 @@
+package useroauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+)
+
+// ProviderConfig là cấu hình một identity provider trong config.yaml (auth.oauth.providers.<name>)
+type ProviderConfig struct {
+	Enabled          bool     `yaml:"enabled"`
+	UserType         string   `yaml:"user_type"`
+	ClientID         string   `yaml:"client_id"`
+	ClientSecret     string   `yaml:"client_secret"`
+	Issuer           string   `yaml:"issuer"`
+	AuthorizationURL string   `yaml:"authorization_url"`
+	TokenURL         string   `yaml:"token_url"`
+	UserInfoURL      string   `yaml:"userinfo_url"`
+	JwksURL          string   `yaml:"jwks_url"`
+	RedirectURL      string   `yaml:"redirect_url"`
+	Scopes           []string `yaml:"scopes"`
+	// TrustEmail coi email từ userinfo là đã xác minh, dùng cho provider không trả về email_verified (Facebook)
+	TrustEmail bool `yaml:"trust_email"`
+}
+
+// Provider là client OAuth2/OIDC (authorization code + PKCE) cho một identity provider.
+// Provider có jwks_url bắt buộc trả về id_token, chữ ký, issuer, audience và nonce được kiểm tra qua JWKS;
+// provider OAuth2 thuần (không có jwks_url) lấy thông tin user từ userinfo endpoint nên không có bảo vệ nonce,
+// chỉ dựa vào state và PKCE.
+type Provider struct {
+	name       string
+	config     ProviderConfig
+	httpClient *http.Client
+	jwks       *remoteKeySet
+}
+
+// NewProvider khởi tạo provider từ cấu hình
+func NewProvider(name string, config ProviderConfig) (*Provider, error) {
+	if config.ClientID == "" || config.AuthorizationURL == "" || config.TokenURL == "" || config.RedirectURL == "" {
+		return nil, errors.Errorf("oauth provider %s: client_id, authorization_url, token_url and redirect_url are required", name)
+	}
+	if config.JwksURL == "" && config.UserInfoURL == "" {
+		return nil, errors.Errorf("oauth provider %s: jwks_url or userinfo_url is required", name)
+	}
+	// Provider OIDC (scope openid) phải kiểm tra id_token để nonce có tác dụng, không cho lùi về userinfo
+	if config.JwksURL == "" && slices.Contains(config.Scopes, "openid") {
+		return nil, errors.Errorf("oauth provider %s: jwks_url is required for openid providers", name)
+	}
+
+	switch usermodel.UserType(config.UserType) {
+	case usermodel.TypeGmail, usermodel.TypeFacebook:
+	default:
+		return nil, errors.Errorf("oauth provider %s: user_type must be one of (gmail, facebook)", name)
+	}
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+
+	provider := &Provider{name: name, config: config, httpClient: httpClient}
+	if config.JwksURL != "" {
+		provider.jwks = newRemoteKeySet(config.JwksURL, httpClient)
+	}
+
+	return provider, nil
+}
+
+// Name trả về tên provider (key trong config)
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// UserType trả về loại user được tạo khi đăng nhập lần đầu qua provider này
+func (p *Provider) UserType() usermodel.UserType {
+	return usermodel.UserType(p.config.UserType)
+}
+
+// AuthCodeURL tạo URL chuyển hướng người dùng tới trang đăng nhập của provider
+func (p *Provider) AuthCodeURL(state string, nonce string, codeChallenge string) string {
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.config.ClientID)
+	params.Set("redirect_uri", p.config.RedirectURL)
+	params.Set("scope", strings.Join(p.config.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(p.config.AuthorizationURL, "?") {
+		separator = "&"
+	}
+
+	return p.config.AuthorizationURL + separator + params.Encode()
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+}
+
+// FetchIdentity đổi authorization code lấy token và trả về thông tin định danh của user
+func (p *Provider) FetchIdentity(ctx context.Context, code string, codeVerifier string, nonce string) (*usermodel.OAuthIdentity, error) {
+	token, err := p.exchange(ctx, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.IDToken != "" && p.jwks != nil {
+		return p.verifyIDToken(ctx, token.IDToken, nonce)
+	}
+
+	if p.jwks != nil {
+		// Provider OIDC bắt buộc phải trả về id_token, nếu không sẽ không kiểm tra được nonce
+		return nil, errors.Errorf("oauth provider %s: token response has no id_token", p.name)
+	}
+
+	return p.fetchUserInfo(ctx, token.AccessToken)
+}
+
+func (p *Provider) exchange(ctx context.Context, code string, codeVerifier string) (*tokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	if p.config.ClientSecret != "" {
+		form.Set("client_secret", p.config.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token tokenResponse
+	if err := doJSON(p.httpClient, req, &token); err != nil {
+		return nil, fmt.Errorf("oauth provider %s: token exchange failed: %w", p.name, err)
+	}
+	if token.AccessToken == "" {
+		return nil, errors.Errorf("oauth provider %s: token response has no access_token", p.name)
+	}
+
+	return &token, nil
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce         string      `json:"nonce"`
+	Email         string      `json:"email"`
+	EmailVerified interface{} `json:"email_verified"`
+	GivenName     string      `json:"given_name"`
+	FamilyName    string      `json:"family_name"`
+}
+
+func (p *Provider) verifyIDToken(ctx context.Context, rawIDToken string, nonce string) (*usermodel.OAuthIdentity, error) {
+	var claims idTokenClaims
+
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "ES256"}),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+	}
+	if p.config.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(p.config.Issuer))
+	}
+
+	_, err := jwt.ParseWithClaims(rawIDToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.jwks.Key(ctx, kid)
+	}, parserOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("oauth provider %s: invalid id_token: %w", p.name, err)
+	}
+
+	if claims.Nonce == "" || claims.Nonce != nonce {
+		return nil, errors.Errorf("oauth provider %s: id_token nonce mismatch", p.name)
+	}
+
+	return &usermodel.OAuthIdentity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: isTrue(claims.EmailVerified),
+		FirstName:     claims.GivenName,
+		LastName:      claims.FamilyName,
+	}, nil
+}
+
+func (p *Provider) fetchUserInfo(ctx context.Context, accessToken string) (*usermodel.OAuthIdentity, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.UserInfoURL, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	var info map[string]interface{}
+	if err := doJSON(p.httpClient, req, &info); err != nil {
+		return nil, fmt.Errorf("oauth provider %s: userinfo request failed: %w", p.name, err)
+	}
+
+	identity := &usermodel.OAuthIdentity{
+		Subject:       firstString(info, "sub", "id"),
+		Email:         firstString(info, "email"),
+		EmailVerified: p.config.TrustEmail || isTrue(info["email_verified"]),
+		FirstName:     firstString(info, "given_name", "first_name"),
+		LastName:      firstString(info, "family_name", "last_name"),
+	}
+	if identity.Subject == "" {
+		return nil, errors.Errorf("oauth provider %s: userinfo has no subject", p.name)
+	}
+
+	return identity, nil
+}
+
+// doJSON gửi request và decode body JSON, lỗi nếu status khác 200
+func doJSON(httpClient *http.Client, req *http.Request, out interface{}) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// firstString lấy giá trị chuỗi đầu tiên có trong map theo thứ tự các key
+func firstString(data map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		switch v := data[key].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case float64:
+			return fmt.Sprintf("%.0f", v)
+		}
+	}
+	return ""
+}
+
+// isTrue đọc claim boolean, một số provider trả về dạng chuỗi "true"
+func isTrue(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package userrepository
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// UpdateRole đổi role của user
+func (repo *UserRepository) UpdateRole(ctx context.Context, id uuid.UUID, role usermodel.UserRole, updatedBy string, updatedAt time.Time) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Model(&usermodel.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"role":       role,
+			"updated_by": updatedBy,
+			"updated_at": updatedAt,
+		})
+
+	if result.Error != nil {
+		return errors.WithStack(result.Error)
+	}
+
+	return nil
+}
+
+// UpdateStatus chuyển status của user từ from sang to.
+// Trả về false nếu status hiện tại không còn là from (bị thay đổi đồng thời).
+func (repo *UserRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from usermodel.UserStatus, to usermodel.UserStatus, updatedBy string, updatedAt time.Time) (bool, error) {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Model(&usermodel.User{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]interface{}{
+			"status":     to,
+			"updated_by": updatedBy,
+			"updated_at": updatedAt,
+		})
+
+	if result.Error != nil {
+		return false, errors.WithStack(result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,90 @@
+package userrepository
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// InsertAPIKey lưu API key mới
+func (repo *UserRepository) InsertAPIKey(ctx context.Context, key *usermodel.APIKey) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Create(key).Error; err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// FindAPIKeyByHash tìm API key theo giá trị băm
+func (repo *UserRepository) FindAPIKeyByHash(ctx context.Context, keyHash string) (*usermodel.APIKey, error) {
+	var key usermodel.APIKey
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, datatype.ErrRecordNotFound
+		}
+
+		return nil, errors.WithStack(err)
+	}
+
+	return &key, nil
+}
+
+// ListActiveAPIKeys lấy các API key chưa thu hồi và chưa hết hạn của user, mới tạo trước
+func (repo *UserRepository) ListActiveAPIKeys(ctx context.Context, userID uuid.UUID, now time.Time) ([]*usermodel.APIKey, error) {
+	var keys []*usermodel.APIKey
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", userID, now).
+		Order("created_at DESC").
+		Find(&keys).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey thu hồi API key của user, trả về false nếu khóa không tồn tại hoặc đã bị thu hồi
+func (repo *UserRepository) RevokeAPIKey(ctx context.Context, userID uuid.UUID, id uuid.UUID, now time.Time) (bool, error) {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Model(&usermodel.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", now)
+
+	if result.Error != nil {
+		return false, errors.WithStack(result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// TouchAPIKey cập nhật thời điểm dùng gần nhất, bỏ qua nếu lần cập nhật trước cách chưa tới interval
+// để không ghi database ở mọi request
+func (repo *UserRepository) TouchAPIKey(ctx context.Context, id uuid.UUID, now time.Time, interval time.Duration) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Model(&usermodel.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at <= ?)", id, now.Add(-interval)).
+		Update("last_used_at", now)
+
+	if result.Error != nil {
+		return errors.WithStack(result.Error)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package userrepository
+
+import (
+	"context"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/pkg/errors"
+)
+
+// InsertAuditEvent lưu một sự kiện audit
+func (repo *UserRepository) InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Create(event).Error; err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// ListAuditEvents lấy danh sách sự kiện audit theo filter và phân trang, mới nhất trước
+func (repo *UserRepository) ListAuditEvents(ctx context.Context, filter *usermodel.AuditEventFilter, paging *datatype.Paging) ([]*usermodel.AuditEvent, error) {
+	var events []*usermodel.AuditEvent
+
+	db := repo.dbCtx.GetMainConnection()
+	query := db.WithContext(ctx).Model(&usermodel.AuditEvent{})
+
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Event != "" {
+		query = query.Where("event = ?", filter.Event)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	if err := query.Count(&paging.Total).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if err := query.Order("created_at DESC").Order("id DESC").
+		Offset(paging.Offset()).Limit(paging.Limit).
+		Find(&events).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,30 @@
+package userrepository
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// UpdateAvatar lưu key ảnh đại diện và thumbnail của user, truyền nil để xóa ảnh đại diện
+func (repo *UserRepository) UpdateAvatar(ctx context.Context, userID uuid.UUID, avatarKey *string, thumbnailKey *string, updatedBy string, updatedAt time.Time) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).
+		Model(&usermodel.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"avatar_key":           avatarKey,
+			"avatar_thumbnail_key": thumbnailKey,
+			"updated_by":           updatedBy,
+			"updated_at":           updatedAt,
+		}).Error; err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
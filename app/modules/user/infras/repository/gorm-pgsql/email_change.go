@@ -0,0 +1,102 @@
+package userrepository
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// ReplaceEmailChangeRequest lưu yêu cầu đổi email mới và hủy các yêu cầu chưa xác nhận trước đó của user,
+// nên chỉ link trong email gửi gần nhất còn dùng được
+func (repo *UserRepository) ReplaceEmailChangeRequest(ctx context.Context, request *usermodel.EmailChangeRequest) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND confirmed_at IS NULL", request.UserID).
+			Delete(&usermodel.EmailChangeRequest{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(request).Error
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// FindEmailChangeRequestByHash tìm yêu cầu đổi email theo giá trị băm của token xác nhận
+func (repo *UserRepository) FindEmailChangeRequestByHash(ctx context.Context, tokenHash string) (*usermodel.EmailChangeRequest, error) {
+	var request usermodel.EmailChangeRequest
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, datatype.ErrRecordNotFound
+		}
+
+		return nil, errors.WithStack(err)
+	}
+
+	return &request, nil
+}
+
+// ConfirmEmailChange đánh dấu yêu cầu đã xác nhận và đổi email của user trong cùng transaction.
+// Email mới đã được chứng minh quyền sở hữu nên được coi là đã xác minh và tài khoản chờ xác minh được kích hoạt.
+// Trả về false nếu yêu cầu đã được dùng hoặc hết hạn, usermodel.ErrEmailAlreadyUsed nếu email mới đã thuộc tài khoản khác.
+func (repo *UserRepository) ConfirmEmailChange(ctx context.Context, request *usermodel.EmailChangeRequest, now time.Time) (bool, error) {
+	db := repo.dbCtx.GetMainConnection()
+
+	confirmed := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&usermodel.EmailChangeRequest{}).
+			Where("id = ? AND confirmed_at IS NULL AND expires_at > ?", request.ID, now).
+			Update("confirmed_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&usermodel.User{}).
+			Where("id = ?", request.UserID).
+			Updates(map[string]interface{}{
+				"email":             request.NewEmail,
+				"email_verified_at": now,
+				"status":            gorm.Expr("CASE WHEN status = ? THEN ? ELSE status END", usermodel.StatusPending, usermodel.StatusActive),
+				"updated_at":        now,
+			}).Error; err != nil {
+			return err
+		}
+
+		confirmed = true
+		return nil
+	})
+	if err != nil {
+		if isDuplicatedKeyError(db, err) {
+			return false, usermodel.ErrEmailAlreadyUsed
+		}
+
+		return false, errors.WithStack(err)
+	}
+
+	return confirmed, nil
+}
+
+// isDuplicatedKeyError kiểm tra lỗi vi phạm ràng buộc unique thông qua bộ dịch lỗi của dialector
+func isDuplicatedKeyError(db *gorm.DB, err error) bool {
+	translator, ok := db.Dialector.(gorm.ErrorTranslator)
+	if !ok {
+		return false
+	}
+
+	return errors.Is(translator.Translate(err), gorm.ErrDuplicatedKey)
+}
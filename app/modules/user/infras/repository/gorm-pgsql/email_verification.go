@@ -0,0 +1,72 @@
+package userrepository
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// MarkEmailVerified kích hoạt user đang chờ xác minh email
+func (repo *UserRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Model(&usermodel.User{}).
+		Where("id = ? AND status = ?", id, usermodel.StatusPending).
+		Updates(map[string]interface{}{
+			"status":            usermodel.StatusActive,
+			"email_verified_at": verifiedAt,
+			"updated_at":        verifiedAt,
+		})
+
+	if result.Error != nil {
+		return errors.WithStack(result.Error)
+	}
+
+	return nil
+}
+
+// ClaimPendingUser kích hoạt user đang chờ xác minh khi email được identity provider bên ngoài xác nhận.
+// Password đặt lúc đăng ký bị xóa vì chưa từng được chứng minh thuộc về chủ email
+// (chống chiếm tài khoản bằng cách đăng ký trước email của người khác).
+func (repo *UserRepository) ClaimPendingUser(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Model(&usermodel.User{}).
+		Where("id = ? AND status = ?", id, usermodel.StatusPending).
+		Updates(map[string]interface{}{
+			"status":            usermodel.StatusActive,
+			"email_verified_at": verifiedAt,
+			"password":          "",
+			"updated_at":        verifiedAt,
+		})
+
+	if result.Error != nil {
+		return errors.WithStack(result.Error)
+	}
+
+	return nil
+}
+
+// ReserveEmailVerificationSend ghi nhận lần gửi email xác minh nếu lần gửi trước đã cách ít nhất interval.
+// Trả về false nếu đang bị giới hạn tần suất hoặc user không còn ở trạng thái chờ xác minh.
+func (repo *UserRepository) ReserveEmailVerificationSend(ctx context.Context, id uuid.UUID, now time.Time, interval time.Duration) (bool, error) {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Model(&usermodel.User{}).
+		Where("id = ? AND status = ?", id, usermodel.StatusPending).
+		Where("email_verification_sent_at IS NULL OR email_verification_sent_at <= ?", now.Add(-interval)).
+		Update("email_verification_sent_at", now)
+
+	if result.Error != nil {
+		return false, errors.WithStack(result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
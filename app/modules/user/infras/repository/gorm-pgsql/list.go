@@ -0,0 +1,52 @@
+package userrepository
+
+import (
+	"context"
+	"strings"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/pkg/errors"
+)
+
+// List lấy danh sách user theo filter và phân trang, mới tạo trước.
+// User đã xóa chỉ được trả về khi lọc theo status deleted.
+func (repo *UserRepository) List(ctx context.Context, filter *usermodel.UserFilter, paging *datatype.Paging) ([]*usermodel.User, error) {
+	var users []*usermodel.User
+
+	db := repo.dbCtx.GetMainConnection()
+	query := db.WithContext(ctx).Model(&usermodel.User{})
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	} else {
+		query = query.Where("status <> ?", usermodel.StatusDeleted)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Email != "" {
+		query = query.Where("LOWER(email) LIKE ?", "%"+escapeLike(strings.ToLower(filter.Email))+"%")
+	}
+
+	if err := query.Count(&paging.Total).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if err := query.Order("created_at DESC").Order("id DESC").
+		Offset(paging.Offset()).Limit(paging.Limit).
+		Find(&users).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return users, nil
+}
+
+// escapeLike escape ký tự đặc biệt của LIKE để chuỗi tìm kiếm được so khớp nguyên văn
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
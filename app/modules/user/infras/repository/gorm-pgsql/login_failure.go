@@ -0,0 +1,88 @@
+package userrepository
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+
+	"github.com/pkg/errors"
+)
+
+// FindLoginFailures lấy bộ đếm đăng nhập sai của email và IP
+func (repo *UserRepository) FindLoginFailures(ctx context.Context, email string, ip string) ([]usermodel.LoginFailure, error) {
+	var failures []usermodel.LoginFailure
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).
+		Where("(scope = ? AND identifier = ?) OR (scope = ? AND identifier = ?)",
+			usermodel.LoginFailureScopeAccount, email, usermodel.LoginFailureScopeIP, ip).
+		Find(&failures).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return failures, nil
+}
+
+// RecordLoginFailure tăng bộ đếm đăng nhập sai và trả về số lần sai hiện tại.
+// Bộ đếm bắt đầu lại từ 1 nếu lần sai trước đã quá window hoặc lượt khóa trước đã hết hạn.
+func (repo *UserRepository) RecordLoginFailure(ctx context.Context, scope string, identifier string, now time.Time, window time.Duration) (int, error) {
+	var failedCount int
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Raw(`
+		INSERT INTO user_login_failures (scope, identifier, failed_count, last_failed_at)
+		VALUES (?, ?, 1, ?)
+		ON CONFLICT (scope, identifier) DO UPDATE SET
+			failed_count = CASE
+				WHEN user_login_failures.last_failed_at <= ?
+					OR (user_login_failures.locked_until IS NOT NULL AND user_login_failures.locked_until <= ?)
+				THEN 1
+				ELSE user_login_failures.failed_count + 1
+			END,
+			locked_until = CASE
+				WHEN user_login_failures.locked_until <= ? THEN NULL
+				ELSE user_login_failures.locked_until
+			END,
+			last_failed_at = EXCLUDED.last_failed_at
+		RETURNING failed_count`,
+		scope, identifier, now, now.Add(-window), now, now).
+		Scan(&failedCount).Error; err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return failedCount, nil
+}
+
+// LockLoginFailure khóa email hoặc IP tới thời điểm lockedUntil
+func (repo *UserRepository) LockLoginFailure(ctx context.Context, scope string, identifier string, lockedUntil time.Time) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Model(&usermodel.LoginFailure{}).
+		Where("scope = ? AND identifier = ?", scope, identifier).
+		Update("locked_until", lockedUntil)
+
+	if result.Error != nil {
+		return errors.WithStack(result.Error)
+	}
+
+	return nil
+}
+
+// ClearLoginFailures xóa bộ đếm (và lượt khóa) của email hoặc IP
+func (repo *UserRepository) ClearLoginFailures(ctx context.Context, scope string, identifier string) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Where("scope = ? AND identifier = ?", scope, identifier).
+		Delete(&usermodel.LoginFailure{})
+
+	if result.Error != nil {
+		return errors.WithStack(result.Error)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package userrepository
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InsertOAuthState lưu state của một lần đăng nhập OAuth
+func (repo *UserRepository) InsertOAuthState(ctx context.Context, state *usermodel.OAuthState) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Create(state).Error; err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// ConsumeOAuthState lấy và xóa state trong cùng một câu lệnh để state chỉ dùng được một lần
+func (repo *UserRepository) ConsumeOAuthState(ctx context.Context, stateHash string) (*usermodel.OAuthState, error) {
+	var states []usermodel.OAuthState
+
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&states)
+	if result.Error != nil {
+		return nil, errors.WithStack(result.Error)
+	}
+
+	if len(states) == 0 {
+		return nil, datatype.ErrRecordNotFound
+	}
+
+	return &states[0], nil
+}
+
+// DeleteExpiredOAuthStates xóa các state đã hết hạn mà không có callback
+func (repo *UserRepository) DeleteExpiredOAuthStates(ctx context.Context, now time.Time) (int64, error) {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&usermodel.OAuthState{})
+	if result.Error != nil {
+		return 0, errors.WithStack(result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// FindUserIdentity tìm liên kết theo provider và subject của tài khoản bên ngoài
+func (repo *UserRepository) FindUserIdentity(ctx context.Context, provider string, subject string) (*usermodel.UserIdentity, error) {
+	var identity usermodel.UserIdentity
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, datatype.ErrRecordNotFound
+		}
+
+		return nil, errors.WithStack(err)
+	}
+
+	return &identity, nil
+}
+
+// InsertUserIdentity liên kết tài khoản bên ngoài với user đã có
+func (repo *UserRepository) InsertUserIdentity(ctx context.Context, identity *usermodel.UserIdentity) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Create(identity).Error; err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// InsertUserWithIdentity tạo user mới cùng liên kết tài khoản bên ngoài trong một transaction
+func (repo *UserRepository) InsertUserWithIdentity(ctx context.Context, user *usermodel.User, identity *usermodel.UserIdentity) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return tx.Create(identity).Error
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
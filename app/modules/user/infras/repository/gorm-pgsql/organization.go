@@ -0,0 +1,320 @@
+package userrepository
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateOrganization lưu tổ chức mới cùng thành viên đầu tiên (owner) trong một transaction
+func (repo *UserRepository) CreateOrganization(ctx context.Context, organization *usermodel.Organization, owner *usermodel.OrganizationMember) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(organization).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(owner).Error
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// FindOrganization tìm tổ chức theo ID
+func (repo *UserRepository) FindOrganization(ctx context.Context, id uuid.UUID) (*usermodel.Organization, error) {
+	var organization usermodel.Organization
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Where("id = ?", id).First(&organization).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, datatype.ErrRecordNotFound
+		}
+
+		return nil, errors.WithStack(err)
+	}
+
+	return &organization, nil
+}
+
+// FindOrganizationMember tìm tư cách thành viên của user trong tổ chức
+func (repo *UserRepository) FindOrganizationMember(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID) (*usermodel.OrganizationMember, error) {
+	var member usermodel.OrganizationMember
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, datatype.ErrRecordNotFound
+		}
+
+		return nil, errors.WithStack(err)
+	}
+
+	return &member, nil
+}
+
+// FindDefaultOrganizationMember tìm tổ chức mặc định của user khi mở phiên mới: tổ chức user tham gia sớm nhất
+func (repo *UserRepository) FindDefaultOrganizationMember(ctx context.Context, userID uuid.UUID) (*usermodel.OrganizationMember, error) {
+	var member usermodel.OrganizationMember
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, datatype.ErrRecordNotFound
+		}
+
+		return nil, errors.WithStack(err)
+	}
+
+	return &member, nil
+}
+
+// ListUserOrganizations lấy các tổ chức mà user là thành viên, tổ chức tham gia sớm nhất trước
+func (repo *UserRepository) ListUserOrganizations(ctx context.Context, userID uuid.UUID) ([]*usermodel.UserOrganization, error) {
+	var organizations []*usermodel.UserOrganization
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).
+		Table("user_organizations AS o").
+		Select("o.*, m.role, m.created_at AS joined_at").
+		Joins("JOIN user_organization_members AS m ON m.organization_id = o.id").
+		Where("m.user_id = ?", userID).
+		Order("m.created_at ASC").
+		Scan(&organizations).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return organizations, nil
+}
+
+// ListOrganizationMembers lấy các thành viên của tổ chức kèm email và tên, thành viên tham gia sớm nhất trước
+func (repo *UserRepository) ListOrganizationMembers(ctx context.Context, organizationID uuid.UUID) ([]*usermodel.OrganizationMemberDetail, error) {
+	var members []*usermodel.OrganizationMemberDetail
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).
+		Table("user_organization_members AS m").
+		Select("m.*, u.email, u.first_name, u.last_name").
+		Joins("JOIN user_users AS u ON u.id = m.user_id").
+		Where("m.organization_id = ?", organizationID).
+		Order("m.created_at ASC").
+		Scan(&members).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return members, nil
+}
+
+// ChangeOrganizationMemberRole đổi role của thành viên. Các thay đổi thành viên của cùng tổ chức được tuần tự hóa
+// bằng khóa trên bản ghi tổ chức để hai owner không thể cùng hạ quyền nhau.
+// Trả về usermodel.ErrOrgMemberNotFound nếu user không còn là thành viên,
+// usermodel.ErrLastOrganizationOwner nếu thay đổi khiến tổ chức không còn owner.
+func (repo *UserRepository) ChangeOrganizationMemberRole(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID, role string, now time.Time) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOrganization(tx, organizationID); err != nil {
+			return err
+		}
+
+		result := tx.Model(&usermodel.OrganizationMember{}).
+			Where("organization_id = ? AND user_id = ?", organizationID, userID).
+			Updates(map[string]interface{}{
+				"role":       role,
+				"updated_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return usermodel.ErrOrgMemberNotFound
+		}
+
+		return ensureOrganizationOwner(tx, organizationID)
+	})
+	if err != nil {
+		if errors.Is(err, usermodel.ErrOrgMemberNotFound) || errors.Is(err, usermodel.ErrLastOrganizationOwner) {
+			return err
+		}
+
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// RemoveOrganizationMember xóa thành viên khỏi tổ chức và bỏ tổ chức khỏi các phiên của user đang dùng tổ chức này.
+// Lỗi trả về giống ChangeOrganizationMemberRole.
+func (repo *UserRepository) RemoveOrganizationMember(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOrganization(tx, organizationID); err != nil {
+			return err
+		}
+
+		result := tx.Where("organization_id = ? AND user_id = ?", organizationID, userID).
+			Delete(&usermodel.OrganizationMember{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return usermodel.ErrOrgMemberNotFound
+		}
+
+		if err := ensureOrganizationOwner(tx, organizationID); err != nil {
+			return err
+		}
+
+		return tx.Model(&usermodel.Session{}).
+			Where("user_id = ? AND organization_id = ?", userID, organizationID).
+			Update("organization_id", nil).Error
+	})
+	if err != nil {
+		if errors.Is(err, usermodel.ErrOrgMemberNotFound) || errors.Is(err, usermodel.ErrLastOrganizationOwner) {
+			return err
+		}
+
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// ReplaceOrganizationInvitation lưu lời mời mới và hủy các lời mời chưa được chấp nhận trước đó
+// của cùng tổ chức tới cùng email, nên chỉ link trong email gửi gần nhất còn dùng được
+func (repo *UserRepository) ReplaceOrganizationInvitation(ctx context.Context, invitation *usermodel.OrganizationInvitation) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("organization_id = ? AND LOWER(email) = LOWER(?) AND accepted_at IS NULL", invitation.OrganizationID, invitation.Email).
+			Delete(&usermodel.OrganizationInvitation{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(invitation).Error
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// FindOrganizationInvitationByHash tìm lời mời theo giá trị băm của token trong link mời
+func (repo *UserRepository) FindOrganizationInvitationByHash(ctx context.Context, tokenHash string) (*usermodel.OrganizationInvitation, error) {
+	var invitation usermodel.OrganizationInvitation
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&invitation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, datatype.ErrRecordNotFound
+		}
+
+		return nil, errors.WithStack(err)
+	}
+
+	return &invitation, nil
+}
+
+// AcceptOrganizationInvitation đánh dấu lời mời đã chấp nhận và thêm thành viên trong cùng transaction.
+// Trả về false nếu lời mời đã được dùng hoặc hết hạn, usermodel.ErrAlreadyOrgMember nếu user đã là thành viên.
+func (repo *UserRepository) AcceptOrganizationInvitation(ctx context.Context, invitation *usermodel.OrganizationInvitation, member *usermodel.OrganizationMember, now time.Time) (bool, error) {
+	db := repo.dbCtx.GetMainConnection()
+
+	accepted := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&usermodel.OrganizationInvitation{}).
+			Where("id = ? AND accepted_at IS NULL AND expires_at > ?", invitation.ID, now).
+			Update("accepted_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Create(member).Error; err != nil {
+			return err
+		}
+
+		accepted = true
+		return nil
+	})
+	if err != nil {
+		if isDuplicatedKeyError(db, err) {
+			return false, usermodel.ErrAlreadyOrgMember
+		}
+
+		return false, errors.WithStack(err)
+	}
+
+	return accepted, nil
+}
+
+// SetSessionOrganization đổi tổ chức đang hoạt động của phiên, nil để bỏ chọn tổ chức
+func (repo *UserRepository) SetSessionOrganization(ctx context.Context, id uuid.UUID, organizationID *uuid.UUID) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).
+		Model(&usermodel.Session{}).
+		Where("id = ?", id).
+		Update("organization_id", organizationID).Error; err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// lockOrganization khóa bản ghi tổ chức tới hết transaction, trả về usermodel.ErrOrgMemberNotFound nếu tổ chức không tồn tại
+func lockOrganization(tx *gorm.DB, organizationID uuid.UUID) error {
+	var organization usermodel.Organization
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", organizationID).
+		First(&organization).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return usermodel.ErrOrgMemberNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ensureOrganizationOwner trả về usermodel.ErrLastOrganizationOwner nếu tổ chức không còn owner nào
+func ensureOrganizationOwner(tx *gorm.DB, organizationID uuid.UUID) error {
+	var owners int64
+
+	if err := tx.Model(&usermodel.OrganizationMember{}).
+		Where("organization_id = ? AND role = ?", organizationID, datatype.OrgRoleOwner).
+		Count(&owners).Error; err != nil {
+		return err
+	}
+	if owners == 0 {
+		return usermodel.ErrLastOrganizationOwner
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package userrepository
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// InsertPasswordResetToken lưu token đặt lại mật khẩu mới
+func (repo *UserRepository) InsertPasswordResetToken(ctx context.Context, token *usermodel.PasswordResetToken) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Create(token).Error; err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// CountPasswordResetTokensSince đếm số token đặt lại mật khẩu đã cấp cho user từ thời điểm since
+func (repo *UserRepository) CountPasswordResetTokensSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).
+		Model(&usermodel.PasswordResetToken{}).
+		Where("user_id = ? AND created_at > ?", userID, since).
+		Count(&count).Error; err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return count, nil
+}
+
+// FindPasswordResetTokenByHash tìm token đặt lại mật khẩu theo giá trị băm
+func (repo *UserRepository) FindPasswordResetTokenByHash(ctx context.Context, tokenHash string) (*usermodel.PasswordResetToken, error) {
+	var token usermodel.PasswordResetToken
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, datatype.ErrRecordNotFound
+		}
+
+		return nil, errors.WithStack(err)
+	}
+
+	return &token, nil
+}
+
+// ConsumePasswordResetToken đánh dấu token đã dùng nếu token còn hạn và chưa dùng.
+// Trả về false nếu token đã được dùng (ví dụ: hai request đặt lại đồng thời) hoặc đã hết hạn.
+func (repo *UserRepository) ConsumePasswordResetToken(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Model(&usermodel.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, now).
+		Update("used_at", now)
+
+	if result.Error != nil {
+		return false, errors.WithStack(result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// InvalidateUserPasswordResetTokens vô hiệu hóa mọi token đặt lại mật khẩu chưa dùng của user
+func (repo *UserRepository) InvalidateUserPasswordResetTokens(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Model(&usermodel.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", now)
+
+	if result.Error != nil {
+		return errors.WithStack(result.Error)
+	}
+
+	return nil
+}
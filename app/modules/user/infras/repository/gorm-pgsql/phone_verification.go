@@ -0,0 +1,115 @@
+package userrepository
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// InsertPhoneVerificationCode lưu mã xác minh số điện thoại mới
+func (repo *UserRepository) InsertPhoneVerificationCode(ctx context.Context, code *usermodel.PhoneVerificationCode) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Create(code).Error; err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// FindLatestPhoneVerificationCode tìm mã xác minh số điện thoại gửi gần nhất của user
+func (repo *UserRepository) FindLatestPhoneVerificationCode(ctx context.Context, userID uuid.UUID) (*usermodel.PhoneVerificationCode, error) {
+	var code usermodel.PhoneVerificationCode
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		First(&code).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, datatype.ErrRecordNotFound
+		}
+
+		return nil, errors.WithStack(err)
+	}
+
+	return &code, nil
+}
+
+// CountPhoneVerificationCodesSince đếm số mã xác minh số điện thoại đã gửi cho user từ thời điểm since
+func (repo *UserRepository) CountPhoneVerificationCodesSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).
+		Model(&usermodel.PhoneVerificationCode{}).
+		Where("user_id = ? AND created_at > ?", userID, since).
+		Count(&count).Error; err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return count, nil
+}
+
+// ReservePhoneVerificationAttempt tăng số lần nhập của mã nếu mã chưa dùng, còn hạn và chưa vượt maxAttempts.
+// Trả về false nếu mã không còn được nhập nữa.
+func (repo *UserRepository) ReservePhoneVerificationAttempt(ctx context.Context, id uuid.UUID, maxAttempts int, now time.Time) (bool, error) {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Model(&usermodel.PhoneVerificationCode{}).
+		Where("id = ? AND consumed_at IS NULL AND expires_at > ? AND attempts < ?", id, now, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+
+	if result.Error != nil {
+		return false, errors.WithStack(result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// ConfirmPhoneVerification đánh dấu mã đã dùng và số điện thoại của user đã xác minh trong cùng transaction.
+// Trả về false nếu mã đã được dùng, hết hạn hoặc user đã đổi sang số điện thoại khác.
+func (repo *UserRepository) ConfirmPhoneVerification(ctx context.Context, code *usermodel.PhoneVerificationCode, now time.Time) (bool, error) {
+	db := repo.dbCtx.GetMainConnection()
+
+	confirmed := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&usermodel.PhoneVerificationCode{}).
+			Where("id = ? AND consumed_at IS NULL AND expires_at > ?", code.ID, now).
+			Update("consumed_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		result = tx.Model(&usermodel.User{}).
+			Where("id = ? AND phone = ? AND phone_verified_at IS NULL", code.UserID, code.Phone).
+			Updates(map[string]interface{}{
+				"phone_verified_at": now,
+				"updated_at":        now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// Mã gửi tới số điện thoại cũ vẫn bị đánh dấu đã dùng nhưng không xác minh số mới
+		confirmed = result.RowsAffected > 0
+		return nil
+	})
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	return confirmed, nil
+}
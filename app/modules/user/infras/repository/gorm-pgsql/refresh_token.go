@@ -0,0 +1,107 @@
+package userrepository
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// InsertRefreshToken lưu refresh token mới
+func (repo *UserRepository) InsertRefreshToken(ctx context.Context, token *usermodel.RefreshToken) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Create(token).Error; err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// FindRefreshTokenByHash tìm refresh token theo giá trị băm
+func (repo *UserRepository) FindRefreshTokenByHash(ctx context.Context, tokenHash string) (*usermodel.RefreshToken, error) {
+	var token usermodel.RefreshToken
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, datatype.ErrRecordNotFound
+		}
+
+		return nil, errors.WithStack(err)
+	}
+
+	return &token, nil
+}
+
+// MarkRefreshTokenUsed đánh dấu refresh token đã được dùng để xoay vòng.
+// Trả về false nếu token đã được dùng hoặc thu hồi trước đó (ví dụ: hai request refresh đồng thời).
+func (repo *UserRepository) MarkRefreshTokenUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Model(&usermodel.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", time.Now())
+
+	if result.Error != nil {
+		return false, errors.WithStack(result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// RevokeRefreshTokenFamily thu hồi toàn bộ refresh token thuộc cùng một family
+// và kết thúc phiên đăng nhập tương ứng (session ID trùng family ID)
+func (repo *UserRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		if err := tx.Model(&usermodel.RefreshToken{}).
+			Where("family_id = ? AND revoked_at IS NULL", familyID).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&usermodel.Session{}).
+			Where("id = ? AND revoked_at IS NULL", familyID).
+			Update("revoked_at", now).Error
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// RevokeUserRefreshTokens thu hồi toàn bộ refresh token và kết thúc mọi phiên đăng nhập của user
+func (repo *UserRepository) RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		if err := tx.Model(&usermodel.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&usermodel.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", now).Error
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
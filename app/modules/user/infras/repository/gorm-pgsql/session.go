@@ -0,0 +1,121 @@
+package userrepository
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// InsertSession lưu phiên đăng nhập mới
+func (repo *UserRepository) InsertSession(ctx context.Context, session *usermodel.Session) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Create(session).Error; err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// FindSession tìm phiên đăng nhập theo ID
+func (repo *UserRepository) FindSession(ctx context.Context, id uuid.UUID) (*usermodel.Session, error) {
+	var session usermodel.Session
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Where("id = ?", id).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, datatype.ErrRecordNotFound
+		}
+
+		return nil, errors.WithStack(err)
+	}
+
+	return &session, nil
+}
+
+// ListActiveSessions lấy các phiên chưa thu hồi và chưa hết hạn của user, phiên dùng gần nhất trước
+func (repo *UserRepository) ListActiveSessions(ctx context.Context, userID uuid.UUID, now time.Time) ([]*usermodel.Session, error) {
+	var sessions []*usermodel.Session
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return sessions, nil
+}
+
+// ExtendSession gia hạn phiên khi refresh token được xoay vòng
+func (repo *UserRepository) ExtendSession(ctx context.Context, id uuid.UUID, now time.Time, expiresAt time.Time) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Model(&usermodel.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"last_seen_at": now,
+			"expires_at":   expiresAt,
+		})
+
+	if result.Error != nil {
+		return errors.WithStack(result.Error)
+	}
+
+	return nil
+}
+
+// TouchSession cập nhật thời điểm hoạt động gần nhất, bỏ qua nếu lần cập nhật trước cách chưa tới interval
+// để không ghi database ở mọi request
+func (repo *UserRepository) TouchSession(ctx context.Context, id uuid.UUID, now time.Time, interval time.Duration) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Model(&usermodel.Session{}).
+		Where("id = ? AND last_seen_at <= ?", id, now.Add(-interval)).
+		Update("last_seen_at", now)
+
+	if result.Error != nil {
+		return errors.WithStack(result.Error)
+	}
+
+	return nil
+}
+
+// RevokeSession thu hồi phiên của user cùng các refresh token của phiên,
+// trả về false nếu phiên không tồn tại hoặc đã bị thu hồi
+func (repo *UserRepository) RevokeSession(ctx context.Context, userID uuid.UUID, id uuid.UUID) (bool, error) {
+	db := repo.dbCtx.GetMainConnection()
+
+	var revoked bool
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&usermodel.Session{}).
+			Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+			Update("revoked_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		revoked = result.RowsAffected > 0
+
+		return tx.Model(&usermodel.RefreshToken{}).
+			Where("family_id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+			Update("revoked_at", now).Error
+	})
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	return revoked, nil
+}
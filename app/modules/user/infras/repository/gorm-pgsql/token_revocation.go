@@ -0,0 +1,46 @@
+package userrepository
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+
+	"github.com/pkg/errors"
+)
+
+// InsertTokenRevocation lưu bản ghi thu hồi access token
+func (repo *UserRepository) InsertTokenRevocation(ctx context.Context, revocation *usermodel.TokenRevocation) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Create(revocation).Error; err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// ListActiveTokenRevocations lấy các bản ghi thu hồi chưa hết hạn
+func (repo *UserRepository) ListActiveTokenRevocations(ctx context.Context, now time.Time) ([]usermodel.TokenRevocation, error) {
+	var revocations []usermodel.TokenRevocation
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Where("expires_at > ?", now).Find(&revocations).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return revocations, nil
+}
+
+// DeleteExpiredTokenRevocations xóa các bản ghi thu hồi đã hết hạn
+func (repo *UserRepository) DeleteExpiredTokenRevocations(ctx context.Context, now time.Time) (int64, error) {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&usermodel.TokenRevocation{})
+	if result.Error != nil {
+		return 0, errors.WithStack(result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,111 @@
+package userrepository
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UpsertPendingTotpCredential lưu secret TOTP mới cho lần đăng ký, ghi đè lần đăng ký chưa xác nhận trước đó.
+// Secret đã xác nhận không bị ghi đè.
+func (repo *UserRepository) UpsertPendingTotpCredential(ctx context.Context, credential *usermodel.TotpCredential) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "last_used_step", "created_at", "updated_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "user_totp_credentials.confirmed_at IS NULL"},
+		}},
+	}).Create(credential).Error; err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// FindTotpCredential tìm secret TOTP của user
+func (repo *UserRepository) FindTotpCredential(ctx context.Context, userID uuid.UUID) (*usermodel.TotpCredential, error) {
+	var credential usermodel.TotpCredential
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Where("user_id = ?", userID).First(&credential).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, datatype.ErrRecordNotFound
+		}
+
+		return nil, errors.WithStack(err)
+	}
+
+	return &credential, nil
+}
+
+// UseTotpStep ghi nhận chu kỳ TOTP vừa dùng, trả về false nếu mã của chu kỳ này (hoặc mới hơn) đã được dùng
+func (repo *UserRepository) UseTotpStep(ctx context.Context, userID uuid.UUID, step int64, now time.Time) (bool, error) {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Model(&usermodel.TotpCredential{}).
+		Where("user_id = ? AND (last_used_step IS NULL OR last_used_step < ?)", userID, step).
+		Updates(map[string]interface{}{
+			"last_used_step": step,
+			"updated_at":     now,
+		})
+
+	if result.Error != nil {
+		return false, errors.WithStack(result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// EnableTwoFactor xác nhận secret TOTP, bật 2FA cho user và thay toàn bộ mã khôi phục cũ bằng bộ mã mới
+func (repo *UserRepository) EnableTwoFactor(ctx context.Context, userID uuid.UUID, recoveryCodes []usermodel.RecoveryCode, now time.Time) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&usermodel.TotpCredential{}).
+			Where("user_id = ?", userID).
+			Updates(map[string]interface{}{"confirmed_at": now, "updated_at": now}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&usermodel.User{}).
+			Where("id = ?", userID).
+			Updates(map[string]interface{}{"two_factor_enabled_at": now, "updated_at": now}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&usermodel.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&recoveryCodes).Error
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// ConsumeRecoveryCode đánh dấu mã khôi phục đã dùng, trả về false nếu mã không tồn tại hoặc đã dùng
+func (repo *UserRepository) ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string, now time.Time) (bool, error) {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Model(&usermodel.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", now)
+
+	if result.Error != nil {
+		return false, errors.WithStack(result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
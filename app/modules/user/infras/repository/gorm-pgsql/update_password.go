@@ -0,0 +1,54 @@
+package userrepository
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// UpdatePassword cập nhật password đã băm của user (salt nằm trong chuỗi băm nên cột salt được xóa),
+// đồng thời tăng credential version để vô hiệu hóa mọi access token đã cấp
+func (repo *UserRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, hashedPassword string, updatedAt time.Time) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Model(&usermodel.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"password":           hashedPassword,
+			"salt":               "",
+			"credential_version": gorm.Expr("credential_version + 1"),
+			"updated_at":         updatedAt,
+		})
+
+	if result.Error != nil {
+		return errors.WithStack(result.Error)
+	}
+
+	return nil
+}
+
+// RehashPassword thay giá trị băm cũ bằng giá trị băm mới của cùng một password mà không đổi credential version.
+// Chỉ cập nhật khi password trong database vẫn là oldHash để không ghi đè lần đổi mật khẩu xảy ra đồng thời.
+func (repo *UserRepository) RehashPassword(ctx context.Context, userID uuid.UUID, oldHash string, newHash string) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	result := db.WithContext(ctx).
+		Model(&usermodel.User{}).
+		Where("id = ? AND password = ?", userID, oldHash).
+		Updates(map[string]interface{}{
+			"password": newHash,
+			"salt":     "",
+		})
+
+	if result.Error != nil {
+		return errors.WithStack(result.Error)
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package userrepository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// ListUserIdentities lấy các tài khoản bên ngoài đã liên kết với user
+func (repo *UserRepository) ListUserIdentities(ctx context.Context, userID uuid.UUID) ([]*usermodel.UserIdentity, error) {
+	var identities []*usermodel.UserIdentity
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC").Find(&identities).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return identities, nil
+}
+
+// ListUserSessions lấy mọi phiên đăng nhập của user, kể cả phiên đã thu hồi hoặc hết hạn
+func (repo *UserRepository) ListUserSessions(ctx context.Context, userID uuid.UUID) ([]*usermodel.Session, error) {
+	var sessions []*usermodel.Session
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC").Find(&sessions).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return sessions, nil
+}
+
+// ListUserAPIKeys lấy mọi API key của user, kể cả khóa đã thu hồi hoặc hết hạn
+func (repo *UserRepository) ListUserAPIKeys(ctx context.Context, userID uuid.UUID) ([]*usermodel.APIKey, error) {
+	var keys []*usermodel.APIKey
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC").Find(&keys).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return keys, nil
+}
+
+// ListUserAuditEvents lấy các sự kiện audit mà user là đối tượng hoặc người thực hiện
+func (repo *UserRepository) ListUserAuditEvents(ctx context.Context, userID uuid.UUID) ([]*usermodel.AuditEvent, error) {
+	var events []*usermodel.AuditEvent
+
+	db := repo.dbCtx.GetMainConnection()
+
+	if err := db.WithContext(ctx).
+		Where("user_id = ? OR actor_id = ?", userID, userID).
+		Order("created_at ASC").
+		Find(&events).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return events, nil
+}
+
+// AnonymizeUser xóa dữ liệu cá nhân của user trong một transaction: thông tin cá nhân và mật khẩu bị thay thế,
+// user chuyển sang deleted, các bảng phụ (liên kết OAuth, phiên, token, mã xác minh số điện thoại, 2FA, API key, thành viên tổ chức) bị xóa.
+// Tổ chức mà user là owner duy nhất được giữ lại nhưng không còn owner.
+// Sự kiện audit được giữ lại để truy vết nhưng IP, user agent và email trong metadata bị xóa.
+func (repo *UserRepository) AnonymizeUser(ctx context.Context, userID uuid.UUID, anonymizedEmail string, loginIdentifier string, now time.Time) error {
+	db := repo.dbCtx.GetMainConnection()
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&usermodel.User{}).
+			Where("id = ?", userID).
+			Updates(map[string]interface{}{
+				"email":                      anonymizedEmail,
+				"first_name":                 "",
+				"last_name":                  "",
+				"phone":                      nil,
+				"phone_verified_at":          nil,
+				"password":                   "",
+				"salt":                       "",
+				"status":                     usermodel.StatusDeleted,
+				"email_verified_at":          nil,
+				"email_verification_sent_at": nil,
+				"two_factor_enabled_at":      nil,
+				"credential_version":         gorm.Expr("credential_version + 1"),
+				"erased_at":                  now,
+				"avatar_key":                 nil,
+				"avatar_thumbnail_key":       nil,
+				"updated_at":                 now,
+			}).Error; err != nil {
+			return err
+		}
+
+		for _, model := range []interface{}{
+			&usermodel.UserIdentity{},
+			&usermodel.RefreshToken{},
+			&usermodel.Session{},
+			&usermodel.PasswordResetToken{},
+			&usermodel.EmailChangeRequest{},
+			&usermodel.PhoneVerificationCode{},
+			&usermodel.TotpCredential{},
+			&usermodel.RecoveryCode{},
+			&usermodel.APIKey{},
+			&usermodel.OrganizationMember{},
+		} {
+			if err := tx.Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		// Lời mời tham gia tổ chức gửi tới email của user
+		if err := tx.Where("LOWER(email) = ?", loginIdentifier).Delete(&usermodel.OrganizationInvitation{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("scope = ? AND identifier = ?", usermodel.LoginFailureScopeAccount, loginIdentifier).
+			Delete(&usermodel.LoginFailure{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&usermodel.AuditEvent{}).
+			Where("user_id = ? OR actor_id = ?", userID, userID).
+			Updates(map[string]interface{}{"ip_address": "", "user_agent": ""}).Error; err != nil {
+			return err
+		}
+
+		// Lần đăng nhập sai chưa xác định được user lưu email đã nhập (đã chuẩn hóa) trong metadata
+		emailMetadata, _ := json.Marshal(loginIdentifier)
+		if err := tx.Model(&usermodel.AuditEvent{}).
+			Where("user_id IS NULL AND event = ? AND metadata LIKE ?", usermodel.AuditEventLoginFailed, "%\"email\":"+escapeLike(string(emailMetadata))+"%").
+			Updates(map[string]interface{}{"ip_address": "", "user_agent": "", "metadata": ""}).Error; err != nil {
+			return err
+		}
+
+		// Metadata của sự kiện đổi email chứa các địa chỉ email cũ/mới, của sự kiện xác minh số điện thoại chứa số điện thoại
+		return tx.Model(&usermodel.AuditEvent{}).
+			Where("user_id = ? AND event IN ?", userID, []string{usermodel.AuditEventEmailChangeRequested, usermodel.AuditEventEmailChanged, usermodel.AuditEventPhoneVerified}).
+			Update("metadata", "").Error
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
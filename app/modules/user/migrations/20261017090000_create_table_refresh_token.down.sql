@@ -0,0 +1,5 @@
+-- Rollback: create_table_refresh_token
+-- Created at: 2026-10-17 09:00:00
+
+-- Write your down migration here
+DROP TABLE IF EXISTS user_refresh_tokens;
@@ -0,0 +1,5 @@
+-- Rollback: create_table_token_revocation
+-- Created at: 2026-10-17 10:00:00
+
+-- Write your down migration here
+DROP TABLE IF EXISTS user_token_revocations;
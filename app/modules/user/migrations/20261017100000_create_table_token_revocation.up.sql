@@ -0,0 +1,14 @@
+-- Migration: create_table_token_revocation
+-- Created at: 2026-10-17 10:00:00
+
+-- Write your up migration here
+-- jti NULL nghĩa là thu hồi mọi token của user được cấp trước revoked_at
+CREATE TABLE IF NOT EXISTS user_token_revocations (
+    id varchar(36) PRIMARY KEY,
+    jti varchar(36),
+    user_id varchar(36) NOT NULL,
+    revoked_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at timestamp(6) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_token_revocations_expires_at ON user_token_revocations (expires_at);
@@ -0,0 +1,6 @@
+-- Rollback: create_table_oauth
+-- Created at: 2026-10-17 11:00:00
+
+-- Write your down migration here
+DROP TABLE IF EXISTS user_oauth_states;
+DROP TABLE IF EXISTS user_identities;
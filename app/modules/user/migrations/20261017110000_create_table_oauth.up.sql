@@ -0,0 +1,28 @@
+-- Migration: create_table_oauth
+-- Created at: 2026-10-17 11:00:00
+
+-- Write your up migration here
+CREATE TABLE IF NOT EXISTS user_identities (
+    id varchar(36) PRIMARY KEY,
+    user_id varchar(36) NOT NULL REFERENCES user_users (id) ON DELETE CASCADE,
+    provider varchar(50) NOT NULL,
+    subject varchar(255) NOT NULL,
+    email varchar(50),
+    created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities (user_id);
+
+CREATE TABLE IF NOT EXISTS user_oauth_states (
+    id varchar(36) PRIMARY KEY,
+    provider varchar(50) NOT NULL,
+    state_hash varchar(64) NOT NULL,
+    nonce varchar(100) NOT NULL,
+    code_verifier varchar(128) NOT NULL,
+    expires_at timestamp(6) NOT NULL,
+    created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (state_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_oauth_states_expires_at ON user_oauth_states (expires_at);
@@ -0,0 +1,6 @@
+-- Rollback: add_email_verification_to_user
+-- Created at: 2026-10-17 12:00:00
+
+-- Write your down migration here
+ALTER TABLE user_users DROP COLUMN IF EXISTS email_verification_sent_at;
+ALTER TABLE user_users DROP COLUMN IF EXISTS email_verified_at;
@@ -0,0 +1,9 @@
+-- Migration: add_email_verification_to_user
+-- Created at: 2026-10-17 12:00:00
+
+-- Write your up migration here
+ALTER TABLE user_users ADD COLUMN IF NOT EXISTS email_verified_at timestamp(6);
+ALTER TABLE user_users ADD COLUMN IF NOT EXISTS email_verification_sent_at timestamp(6);
+
+-- User đã active trước khi có luồng xác minh email được coi là đã xác minh
+UPDATE user_users SET email_verified_at = created_at WHERE status = 'active' AND email_verified_at IS NULL;
@@ -0,0 +1,5 @@
+-- Rollback: create_table_password_reset_token
+-- Created at: 2026-10-17 13:00:00
+
+-- Write your down migration here
+DROP TABLE IF EXISTS user_password_reset_tokens;
@@ -0,0 +1,15 @@
+-- Migration: create_table_password_reset_token
+-- Created at: 2026-10-17 13:00:00
+
+-- Write your up migration here
+CREATE TABLE IF NOT EXISTS user_password_reset_tokens (
+    id varchar(36) PRIMARY KEY,
+    user_id varchar(36) NOT NULL REFERENCES user_users (id) ON DELETE CASCADE,
+    token_hash varchar(64) NOT NULL,
+    expires_at timestamp(6) NOT NULL,
+    used_at timestamp(6),
+    created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_password_reset_tokens_user_id ON user_password_reset_tokens (user_id, created_at);
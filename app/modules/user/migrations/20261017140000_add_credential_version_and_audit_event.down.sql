@@ -0,0 +1,6 @@
+-- Rollback: add_credential_version_and_audit_event
+-- Created at: 2026-10-17 14:00:00
+
+-- Write your down migration here
+DROP TABLE IF EXISTS user_audit_events;
+ALTER TABLE user_users DROP COLUMN IF EXISTS credential_version;
@@ -0,0 +1,18 @@
+-- Migration: add_credential_version_and_audit_event
+-- Created at: 2026-10-17 14:00:00
+
+-- Write your up migration here
+ALTER TABLE user_users ADD COLUMN IF NOT EXISTS credential_version integer NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS user_audit_events (
+    id varchar(36) PRIMARY KEY,
+    user_id varchar(36),
+    actor_id varchar(36),
+    event varchar(50) NOT NULL,
+    ip_address varchar(45),
+    user_agent varchar(255),
+    metadata text,
+    created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_audit_events_user_id ON user_audit_events (user_id, created_at);
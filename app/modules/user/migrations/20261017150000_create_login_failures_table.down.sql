@@ -0,0 +1,5 @@
+-- Rollback: create_login_failures_table
+-- Created at: 2026-10-17 15:00:00
+
+-- Write your down migration here
+DROP TABLE IF EXISTS user_login_failures;
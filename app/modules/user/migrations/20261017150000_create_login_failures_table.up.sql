@@ -0,0 +1,14 @@
+-- Migration: create_login_failures_table
+-- Created at: 2026-10-17 15:00:00
+
+-- Write your up migration here
+CREATE TABLE IF NOT EXISTS user_login_failures (
+    scope varchar(20) NOT NULL,
+    identifier varchar(255) NOT NULL,
+    failed_count integer NOT NULL DEFAULT 0,
+    last_failed_at timestamp(6) NOT NULL,
+    locked_until timestamp(6),
+    PRIMARY KEY (scope, identifier)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_login_failures_last_failed_at ON user_login_failures (last_failed_at);
@@ -0,0 +1,7 @@
+-- Rollback: create_two_factor_tables
+-- Created at: 2026-10-17 16:00:00
+
+-- Write your down migration here
+DROP TABLE IF EXISTS user_recovery_codes;
+DROP TABLE IF EXISTS user_totp_credentials;
+ALTER TABLE user_users DROP COLUMN IF EXISTS two_factor_enabled_at;
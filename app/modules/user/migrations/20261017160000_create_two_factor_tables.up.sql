@@ -0,0 +1,24 @@
+-- Migration: create_two_factor_tables
+-- Created at: 2026-10-17 16:00:00
+
+-- Write your up migration here
+ALTER TABLE user_users ADD COLUMN IF NOT EXISTS two_factor_enabled_at timestamp(6);
+
+CREATE TABLE IF NOT EXISTS user_totp_credentials (
+    user_id varchar(36) PRIMARY KEY,
+    secret varchar(64) NOT NULL,
+    confirmed_at timestamp(6),
+    last_used_step bigint,
+    created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id varchar(36) PRIMARY KEY,
+    user_id varchar(36) NOT NULL,
+    code_hash varchar(64) NOT NULL,
+    used_at timestamp(6),
+    created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_recovery_codes_user_code ON user_recovery_codes (user_id, code_hash);
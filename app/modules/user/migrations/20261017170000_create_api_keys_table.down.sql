@@ -0,0 +1,5 @@
+-- Rollback: create_api_keys_table
+-- Created at: 2026-10-17 17:00:00
+
+-- Write your down migration here
+DROP TABLE IF EXISTS user_api_keys;
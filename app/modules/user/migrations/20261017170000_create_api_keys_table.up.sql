@@ -0,0 +1,19 @@
+-- Migration: create_api_keys_table
+-- Created at: 2026-10-17 17:00:00
+
+-- Write your up migration here
+CREATE TABLE IF NOT EXISTS user_api_keys (
+    id varchar(36) PRIMARY KEY,
+    user_id varchar(36) NOT NULL,
+    name varchar(100) NOT NULL,
+    prefix varchar(20) NOT NULL,
+    key_hash varchar(64) NOT NULL,
+    scopes varchar(255) NOT NULL DEFAULT '',
+    expires_at timestamp(6),
+    last_used_at timestamp(6),
+    revoked_at timestamp(6),
+    created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_api_keys_key_hash ON user_api_keys (key_hash);
+CREATE INDEX IF NOT EXISTS idx_user_api_keys_user_id ON user_api_keys (user_id);
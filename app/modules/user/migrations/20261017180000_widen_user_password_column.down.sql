@@ -0,0 +1,6 @@
+-- Rollback: widen_user_password_column
+-- Created at: 2026-10-17 18:00:00
+
+-- Write your down migration here
+ALTER TABLE user_users ALTER COLUMN salt DROP DEFAULT;
+ALTER TABLE user_users ALTER COLUMN password TYPE varchar(100);
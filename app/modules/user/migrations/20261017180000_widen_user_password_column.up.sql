@@ -0,0 +1,7 @@
+-- Migration: widen_user_password_column
+-- Created at: 2026-10-17 18:00:00
+
+-- Write your up migration here
+-- Chuỗi băm argon2id dạng PHC chứa thuật toán, tham số và salt nên dài hơn bcrypt
+ALTER TABLE user_users ALTER COLUMN password TYPE varchar(255);
+ALTER TABLE user_users ALTER COLUMN salt SET DEFAULT '';
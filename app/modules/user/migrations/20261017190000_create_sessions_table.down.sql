@@ -0,0 +1,5 @@
+-- Rollback: create_sessions_table
+-- Created at: 2026-10-17 19:00:00
+
+-- Write your down migration here
+DROP TABLE IF EXISTS user_sessions;
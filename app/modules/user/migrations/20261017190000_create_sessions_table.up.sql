@@ -0,0 +1,24 @@
+-- Migration: create_sessions_table
+-- Created at: 2026-10-17 19:00:00
+
+-- Write your up migration here
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id varchar(36) PRIMARY KEY,
+    user_id varchar(36) NOT NULL REFERENCES user_users (id) ON DELETE CASCADE,
+    ip_address varchar(45) NOT NULL DEFAULT '',
+    user_agent varchar(255) NOT NULL DEFAULT '',
+    created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at timestamp(6) NOT NULL,
+    revoked_at timestamp(6)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user_id ON user_sessions (user_id);
+
+-- Mỗi refresh token family còn hiệu lực là một phiên đăng nhập đã có trước khi tạo bảng
+INSERT INTO user_sessions (id, user_id, created_at, last_seen_at, expires_at)
+SELECT family_id, user_id, MIN(created_at), MAX(created_at), MAX(expires_at)
+FROM user_refresh_tokens
+WHERE revoked_at IS NULL
+GROUP BY family_id, user_id
+ON CONFLICT (id) DO NOTHING;
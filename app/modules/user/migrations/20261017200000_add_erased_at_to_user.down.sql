@@ -0,0 +1,5 @@
+-- Rollback: add_erased_at_to_user
+-- Created at: 2026-10-17 20:00:00
+
+-- Write your down migration here
+ALTER TABLE user_users DROP COLUMN IF EXISTS erased_at;
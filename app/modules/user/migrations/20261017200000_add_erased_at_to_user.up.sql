@@ -0,0 +1,6 @@
+-- Migration: add_erased_at_to_user
+-- Created at: 2026-10-17 20:00:00
+
+-- Write your up migration here
+-- Thời điểm dữ liệu cá nhân của user bị xóa theo yêu cầu, user đã xóa dữ liệu không thể khôi phục
+ALTER TABLE user_users ADD COLUMN IF NOT EXISTS erased_at timestamp(6);
@@ -0,0 +1,5 @@
+-- Rollback: create_table_email_change_request
+-- Created at: 2026-10-17 21:00:00
+
+-- Write your down migration here
+DROP TABLE IF EXISTS user_email_change_requests;
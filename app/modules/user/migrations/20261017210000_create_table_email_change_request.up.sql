@@ -0,0 +1,16 @@
+-- Migration: create_table_email_change_request
+-- Created at: 2026-10-17 21:00:00
+
+-- Write your up migration here
+CREATE TABLE IF NOT EXISTS user_email_change_requests (
+    id varchar(36) PRIMARY KEY,
+    user_id varchar(36) NOT NULL REFERENCES user_users (id) ON DELETE CASCADE,
+    new_email varchar(50) NOT NULL,
+    token_hash varchar(64) NOT NULL,
+    expires_at timestamp(6) NOT NULL,
+    confirmed_at timestamp(6),
+    created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_email_change_requests_user_id ON user_email_change_requests (user_id, created_at);
@@ -0,0 +1,6 @@
+-- Rollback: add_avatar_to_user
+-- Created at: 2026-10-17 22:00:00
+
+-- Write your down migration here
+ALTER TABLE user_users DROP COLUMN IF EXISTS avatar_thumbnail_key;
+ALTER TABLE user_users DROP COLUMN IF EXISTS avatar_key;
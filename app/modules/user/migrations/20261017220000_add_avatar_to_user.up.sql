@@ -0,0 +1,7 @@
+-- Migration: add_avatar_to_user
+-- Created at: 2026-10-17 22:00:00
+
+-- Write your up migration here
+-- Key của ảnh đại diện và thumbnail trong kho lưu trữ file, URL được tạo từ key theo cấu hình storage
+ALTER TABLE user_users ADD COLUMN IF NOT EXISTS avatar_key varchar(255);
+ALTER TABLE user_users ADD COLUMN IF NOT EXISTS avatar_thumbnail_key varchar(255);
@@ -0,0 +1,10 @@
+-- Rollback: add_request_id_and_append_only_to_audit_event
+-- Created at: 2026-10-17 23:00:00
+
+-- Write your down migration here
+DROP TRIGGER IF EXISTS trg_user_audit_events_no_truncate ON user_audit_events;
+DROP TRIGGER IF EXISTS trg_user_audit_events_append_only ON user_audit_events;
+DROP FUNCTION IF EXISTS user_audit_events_append_only();
+DROP INDEX IF EXISTS idx_user_audit_events_created_at;
+DROP INDEX IF EXISTS idx_user_audit_events_event;
+ALTER TABLE user_audit_events DROP COLUMN IF EXISTS request_id;
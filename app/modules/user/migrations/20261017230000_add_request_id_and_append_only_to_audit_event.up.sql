@@ -0,0 +1,39 @@
+-- Migration: add_request_id_and_append_only_to_audit_event
+-- Created at: 2026-10-17 23:00:00
+
+-- Write your up migration here
+ALTER TABLE user_audit_events ADD COLUMN IF NOT EXISTS request_id varchar(64);
+
+CREATE INDEX IF NOT EXISTS idx_user_audit_events_event ON user_audit_events (event, created_at);
+CREATE INDEX IF NOT EXISTS idx_user_audit_events_created_at ON user_audit_events (created_at);
+
+-- Bảng audit chỉ cho phép thêm mới. Ngoại lệ duy nhất là xóa trắng ip_address, user_agent, metadata
+-- khi xóa dữ liệu cá nhân của user, các cột còn lại và việc xóa/truncate đều bị từ chối.
+CREATE OR REPLACE FUNCTION user_audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND NEW.id IS NOT DISTINCT FROM OLD.id
+        AND NEW.user_id IS NOT DISTINCT FROM OLD.user_id
+        AND NEW.actor_id IS NOT DISTINCT FROM OLD.actor_id
+        AND NEW.event IS NOT DISTINCT FROM OLD.event
+        AND NEW.request_id IS NOT DISTINCT FROM OLD.request_id
+        AND NEW.created_at IS NOT DISTINCT FROM OLD.created_at
+        AND (NEW.ip_address IS NOT DISTINCT FROM OLD.ip_address OR COALESCE(NEW.ip_address, '') = '')
+        AND (NEW.user_agent IS NOT DISTINCT FROM OLD.user_agent OR COALESCE(NEW.user_agent, '') = '')
+        AND (NEW.metadata IS NOT DISTINCT FROM OLD.metadata OR COALESCE(NEW.metadata, '') = '') THEN
+        RETURN NEW;
+    END IF;
+
+    RAISE EXCEPTION 'user_audit_events is append-only (% rejected)', TG_OP;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_user_audit_events_append_only ON user_audit_events;
+CREATE TRIGGER trg_user_audit_events_append_only
+    BEFORE UPDATE OR DELETE ON user_audit_events
+    FOR EACH ROW EXECUTE PROCEDURE user_audit_events_append_only();
+
+DROP TRIGGER IF EXISTS trg_user_audit_events_no_truncate ON user_audit_events;
+CREATE TRIGGER trg_user_audit_events_no_truncate
+    BEFORE TRUNCATE ON user_audit_events
+    FOR EACH STATEMENT EXECUTE PROCEDURE user_audit_events_append_only();
@@ -0,0 +1,8 @@
+-- Rollback: create_organization_tables
+-- Created at: 2026-10-17 23:30:00
+
+-- Write your down migration here
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS user_organization_invitations;
+DROP TABLE IF EXISTS user_organization_members;
+DROP TABLE IF EXISTS user_organizations;
@@ -0,0 +1,64 @@
+-- Migration: create_organization_tables
+-- Created at: 2026-10-17 23:30:00
+
+-- Write your up migration here
+CREATE TABLE IF NOT EXISTS user_organizations (
+    id varchar(36) PRIMARY KEY,
+    name varchar(100) NOT NULL,
+    created_by varchar(36) NOT NULL,
+    created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS user_organization_members (
+    organization_id varchar(36) NOT NULL REFERENCES user_organizations (id) ON DELETE CASCADE,
+    user_id varchar(36) NOT NULL REFERENCES user_users (id) ON DELETE CASCADE,
+    role varchar(20) NOT NULL CHECK (role IN ('owner', 'admin', 'member')),
+    created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_organization_members_user_id ON user_organization_members (user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS user_organization_invitations (
+    id varchar(36) PRIMARY KEY,
+    organization_id varchar(36) NOT NULL REFERENCES user_organizations (id) ON DELETE CASCADE,
+    email varchar(50) NOT NULL,
+    role varchar(20) NOT NULL CHECK (role IN ('admin', 'member')),
+    token_hash varchar(64) NOT NULL,
+    invited_by varchar(36) NOT NULL,
+    expires_at timestamp(6) NOT NULL,
+    accepted_at timestamp(6),
+    created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_organization_invitations_email ON user_organization_invitations (organization_id, LOWER(email));
+
+-- Tổ chức đang hoạt động của phiên, access token cấp trong phiên mang tổ chức này ở claim "org"
+ALTER TABLE user_sessions
+    ADD COLUMN IF NOT EXISTS organization_id varchar(36) REFERENCES user_organizations (id) ON DELETE SET NULL;
+
+-- Tổ chức mặc định cho dữ liệu có từ trước khi có tổ chức (ID cố định, module Book gán book cũ vào tổ chức này).
+-- Chỉ tạo khi đã có user: admin hệ thống là owner (không có admin thì user tạo sớm nhất là owner),
+-- các user còn lại là member để vẫn thấy được book như trước khi nâng cấp.
+INSERT INTO user_organizations (id, name, created_by)
+SELECT '00000000-0000-0000-0000-000000000001', 'Default organization', u.id
+FROM user_users u
+WHERE u.status <> 'deleted'
+ORDER BY (u.role = 'admin') DESC, u.created_at
+LIMIT 1
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO user_organization_members (organization_id, user_id, role)
+SELECT o.id, u.id, CASE WHEN u.role = 'admin' OR u.id = o.created_by THEN 'owner' ELSE 'member' END
+FROM user_organizations o
+JOIN user_users u ON u.status <> 'deleted'
+WHERE o.id = '00000000-0000-0000-0000-000000000001'
+ON CONFLICT (organization_id, user_id) DO NOTHING;
+
+-- Phiên đang mở chuyển sang tổ chức mặc định để không phải đăng nhập lại mới dùng được route yêu cầu tổ chức
+UPDATE user_sessions SET organization_id = '00000000-0000-0000-0000-000000000001'
+WHERE organization_id IS NULL
+  AND user_id IN (SELECT user_id FROM user_organization_members WHERE organization_id = '00000000-0000-0000-0000-000000000001');
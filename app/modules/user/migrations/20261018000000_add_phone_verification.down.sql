@@ -0,0 +1,6 @@
+-- Rollback: add_phone_verification
+-- Created at: 2026-10-18 00:00:00
+
+-- Write your down migration here
+DROP TABLE IF EXISTS user_phone_verification_codes;
+ALTER TABLE user_users DROP COLUMN IF EXISTS phone_verified_at;
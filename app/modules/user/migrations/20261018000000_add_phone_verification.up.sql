@@ -0,0 +1,20 @@
+-- Migration: add_phone_verification
+-- Created at: 2026-10-18 00:00:00
+
+-- Write your up migration here
+-- Thời điểm số điện thoại hiện tại của user được xác minh bằng mã OTP, bị xóa khi user đổi số
+ALTER TABLE user_users ADD COLUMN IF NOT EXISTS phone_verified_at timestamp(6);
+
+-- Mã OTP gửi qua SMS, chỉ lưu giá trị băm; mã gửi gần nhất của user là mã duy nhất còn dùng được
+CREATE TABLE IF NOT EXISTS user_phone_verification_codes (
+    id varchar(36) PRIMARY KEY,
+    user_id varchar(36) NOT NULL REFERENCES user_users (id) ON DELETE CASCADE,
+    phone varchar(20) NOT NULL,
+    code_hash varchar(64) NOT NULL,
+    attempts int NOT NULL DEFAULT 0,
+    expires_at timestamp(6) NOT NULL,
+    consumed_at timestamp(6),
+    created_at timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_phone_verification_codes_user_id ON user_phone_verification_codes (user_id, created_at);
@@ -0,0 +1,58 @@
+package usermodel
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scope có thể cấp cho API key. Scope users:* chỉ cấp được cho admin.
+const (
+	ScopeProfileRead  = "profile:read"
+	ScopeProfileWrite = "profile:write"
+	ScopeUsersRead    = "users:read"
+	ScopeUsersWrite   = "users:write"
+)
+
+// APIKey là khóa truy cập dài hạn của user cho script/service, chỉ lưu giá trị băm.
+// Prefix là phần đầu của khóa, lưu dạng rõ để user nhận diện khóa trong danh sách.
+type APIKey struct {
+	ID         uuid.UUID  `json:"id" gorm:"column:id;"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"column:user_id;"`
+	Name       string     `json:"name" gorm:"column:name;"`
+	Prefix     string     `json:"prefix" gorm:"column:prefix;"`
+	KeyHash    string     `json:"-" gorm:"column:key_hash;"`
+	Scopes     string     `json:"scopes" gorm:"column:scopes;"`
+	ExpiresAt  *time.Time `json:"expires_at" gorm:"column:expires_at;"`
+	LastUsedAt *time.Time `json:"last_used_at" gorm:"column:last_used_at;"`
+	RevokedAt  *time.Time `json:"revoked_at" gorm:"column:revoked_at;"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"column:created_at;"`
+}
+
+func (APIKey) TableName() string {
+	return "user_api_keys"
+}
+
+// ScopeList trả về danh sách scope (lưu trong database dạng chuỗi cách nhau bởi dấu cách)
+func (k *APIKey) ScopeList() []string {
+	return strings.Fields(k.Scopes)
+}
+
+// IsActive kiểm tra khóa chưa bị thu hồi và chưa hết hạn tại thời điểm now
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// ToResponse chuyển đổi APIKey sang APIKeyResponse DTO
+func (k *APIKey) ToResponse() *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.ScopeList(),
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
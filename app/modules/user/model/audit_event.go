@@ -0,0 +1,87 @@
+package usermodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditEventPasswordChanged      = "password_changed"
+	AuditEventAccountLocked        = "account_locked"
+	AuditEventAccountUnlocked      = "account_unlocked"
+	AuditEventTwoFactorEnabled     = "two_factor_enabled"
+	AuditEventRecoveryCodeUsed     = "recovery_code_used"
+	AuditEventRoleChanged          = "role_changed"
+	AuditEventUserBanned           = "user_banned"
+	AuditEventUserUnbanned         = "user_unbanned"
+	AuditEventUserDeleted          = "user_deleted"
+	AuditEventUserRestored         = "user_restored"
+	AuditEventAPIKeyCreated        = "api_key_created"
+	AuditEventAPIKeyRevoked        = "api_key_revoked"
+	AuditEventSessionRevoked       = "session_revoked"
+	AuditEventUserDataExported     = "user_data_exported"
+	AuditEventUserErased           = "user_erased"
+	AuditEventEmailChangeRequested = "email_change_requested"
+	AuditEventEmailChanged         = "email_changed"
+	AuditEventLoginSucceeded       = "login_succeeded"
+	AuditEventLoginFailed          = "login_failed"
+	AuditEventLogout               = "logout"
+	AuditEventLogoutAll            = "logout_all"
+	AuditEventTokenRefreshed       = "token_refreshed"
+	AuditEventRefreshTokenReused   = "refresh_token_reused"
+	AuditEventPasswordReset        = "password_reset"
+	AuditEventOrganizationCreated  = "organization_created"
+	AuditEventOrgMemberInvited     = "organization_member_invited"
+	AuditEventOrgMemberJoined      = "organization_member_joined"
+	AuditEventOrgMemberRoleChanged = "organization_member_role_changed"
+	AuditEventOrgMemberRemoved     = "organization_member_removed"
+	AuditEventOrganizationSwitched = "organization_switched"
+	AuditEventImpersonationStarted = "impersonation_started"
+	AuditEventPhoneVerified        = "phone_verified"
+	// AuditEventPasswordConfirmFailed ghi lần nhập sai mật khẩu hiện tại khi đã đăng nhập (đổi mật khẩu, đổi email)
+	AuditEventPasswordConfirmFailed = "password_confirmation_failed"
+)
+
+// Phương thức đăng nhập ghi trong metadata của sự kiện login_succeeded/login_failed
+const (
+	LoginMethodPassword  = "password"
+	LoginMethodTwoFactor = "two_factor"
+	LoginMethodOAuth     = "oauth"
+)
+
+// Lý do đăng nhập thất bại ghi trong metadata của sự kiện login_failed
+const (
+	LoginFailureReasonUnknownEmail         = "unknown_email"
+	LoginFailureReasonInvalidPassword      = "invalid_password"
+	LoginFailureReasonThrottled            = "throttled"
+	LoginFailureReasonBannedOrDeleted      = "banned_or_deleted"
+	LoginFailureReasonEmailNotVerified     = "email_not_verified"
+	LoginFailureReasonInvalidTwoFactorCode = "invalid_two_factor_code"
+	LoginFailureReasonOAuthFailed          = "oauth_failed"
+)
+
+// Thao tác yêu cầu nhập lại mật khẩu hiện tại, ghi trong metadata của sự kiện password_confirmation_failed
+const (
+	PasswordConfirmActionChangePassword = "change_password"
+	PasswordConfirmActionChangeEmail    = "change_email"
+)
+
+// AuditEvent ghi lại một sự kiện bảo mật của tài khoản.
+// UserID là tài khoản bị tác động, ActorID là người thực hiện (trùng UserID nếu user tự thao tác).
+// Bảng chỉ cho phép thêm mới, sự kiện đã ghi không thể sửa hoặc xóa (trừ khi xóa dữ liệu cá nhân của user).
+type AuditEvent struct {
+	ID        uuid.UUID  `json:"id" gorm:"column:id;"`
+	UserID    *uuid.UUID `json:"user_id" gorm:"column:user_id;"`
+	ActorID   *uuid.UUID `json:"actor_id" gorm:"column:actor_id;"`
+	Event     string     `json:"event" gorm:"column:event;"`
+	IPAddress string     `json:"ip_address" gorm:"column:ip_address;"`
+	UserAgent string     `json:"user_agent" gorm:"column:user_agent;"`
+	Metadata  string     `json:"metadata" gorm:"column:metadata;"`
+	RequestID string     `json:"request_id" gorm:"column:request_id;"`
+	CreatedAt time.Time  `json:"created_at" gorm:"column:created_at;"`
+}
+
+func (AuditEvent) TableName() string {
+	return "user_audit_events"
+}
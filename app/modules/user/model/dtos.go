@@ -14,18 +14,19 @@ type LoginForm struct {
 type RegisterForm struct {
 	ID        string `json:"-"`
 	Email     string `json:"email" binding:"required,email"`
-	Password  string `json:"password" binding:"required,min=8,max=32"`
+	Password  string `json:"password" binding:"required,max=256"`
 	FirstName string `json:"first_name" binding:"required"`
 	LastName  string `json:"last_name" binding:"required"`
-	Phone     string `json:"phone" binding:"required"`
+	Phone     string `json:"phone" binding:"required,max=20"`
 }
 
 type RegisterResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Email     string    `json:"email"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Phone     string    `json:"phone"`
+	ID        uuid.UUID  `json:"id"`
+	Email     string     `json:"email"`
+	FirstName string     `json:"first_name"`
+	LastName  string     `json:"last_name"`
+	Phone     string     `json:"phone"`
+	Status    UserStatus `json:"status"`
 }
 
 // ProfileResponse đại diện cho dữ liệu profile trả về
@@ -41,13 +42,83 @@ type ProfileResponse struct {
 	Type      UserType   `json:"type"`
 	CreatedAt *time.Time `json:"created_at"`
 	UpdatedAt *time.Time `json:"updated_at"`
+
+	PhoneVerifiedAt    *time.Time `json:"phone_verified_at"`
+	AvatarURL          string     `json:"avatar_url"`
+	AvatarThumbnailURL string     `json:"avatar_thumbnail_url"`
+}
+
+// AvatarResponse đại diện cho URL ảnh đại diện sau khi tải lên
+type AvatarResponse struct {
+	AvatarURL          string `json:"avatar_url"`
+	AvatarThumbnailURL string `json:"avatar_thumbnail_url"`
+}
+
+// UserData là dữ liệu module User lưu về một user trong bản trích xuất dữ liệu cá nhân.
+// Không bao gồm giá trị băm của mật khẩu, token và khóa.
+type UserData struct {
+	Profile          *ProfileResponse    `json:"profile"`
+	EmailVerifiedAt  *time.Time          `json:"email_verified_at"`
+	TwoFactorEnabled bool                `json:"two_factor_enabled"`
+	Identities       []*UserIdentity     `json:"identities"`
+	Sessions         []*Session          `json:"sessions"`
+	APIKeys          []*APIKey           `json:"api_keys"`
+	AuditEvents      []*AuditEvent       `json:"audit_events"`
+	Organizations    []*UserOrganization `json:"organizations"`
+}
+
+// UserDataArchive là bản trích xuất dữ liệu cá nhân của user, Modules chứa dữ liệu của từng module theo tên module
+type UserDataArchive struct {
+	UserID     uuid.UUID      `json:"user_id"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Modules    map[string]any `json:"modules"`
+}
+
+// UserFilter là bộ lọc danh sách user cho admin
+type UserFilter struct {
+	Status string `json:"status,omitempty" form:"status" binding:"omitempty,oneof=pending active inactive banned deleted"`
+	Role   string `json:"role,omitempty" form:"role" binding:"omitempty,oneof=user admin"`
+	Type   string `json:"type,omitempty" form:"type" binding:"omitempty,oneof=email_password facebook gmail"`
+	Email  string `json:"email,omitempty" form:"email" binding:"omitempty,max=100"`
+}
+
+// AuditEventFilter là bộ lọc danh sách sự kiện audit cho admin, From/To giới hạn created_at trong [From, To)
+type AuditEventFilter struct {
+	UserID string     `json:"user_id,omitempty" form:"user_id" binding:"omitempty,uuid"`
+	Event  string     `json:"event,omitempty" form:"event" binding:"omitempty,max=50"`
+	From   *time.Time `json:"from,omitempty" form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To     *time.Time `json:"to,omitempty" form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// ChangeRoleForm đại diện cho dữ liệu admin đổi role của user
+type ChangeRoleForm struct {
+	Role UserRole `json:"role" binding:"required,oneof=user admin"`
+}
+
+// ImpersonateForm đại diện cho dữ liệu admin đăng nhập dưới danh nghĩa user.
+// Reason được ghi vào audit, OrganizationID chọn tổ chức của user (mặc định là tổ chức tham gia sớm nhất).
+type ImpersonateForm struct {
+	Reason         string     `json:"reason" binding:"required,min=3,max=500"`
+	OrganizationID *uuid.UUID `json:"organization_id"`
+}
+
+// VerifyPhoneForm đại diện cho mã OTP user nhận qua SMS khi xác minh số điện thoại
+type VerifyPhoneForm struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// IntrospectForm đại diện cho yêu cầu introspection của service khác (RFC 7662),
+// nhận dạng application/x-www-form-urlencoded hoặc JSON
+type IntrospectForm struct {
+	Token         string `json:"token" form:"token" binding:"required"`
+	TokenTypeHint string `json:"token_type_hint" form:"token_type_hint" binding:"omitempty,oneof=access_token api_key"`
 }
 
 // UpdateProfileRequest đại diện cho dữ liệu cập nhật profile
 type UpdateProfileRequest struct {
 	FirstName string `json:"first_name" binding:"omitempty,min=1,max=50"`
 	LastName  string `json:"last_name" binding:"omitempty,min=1,max=50"`
-	Phone     string `json:"phone" binding:"omitempty,min=10,max=15"`
+	Phone     string `json:"phone" binding:"omitempty,max=20"`
 }
 
 // CreateBookRequest đại diện cho dữ liệu đầu vào khi tạo sách mới
@@ -75,3 +146,156 @@ type UpdateUserRequest struct {
 }
 
 // BookResponse đại diện cho dữ liệu trả về khi lấy thông tin sách
+
+// RefreshTokenForm đại diện cho dữ liệu đầu vào khi làm mới access token
+type RefreshTokenForm struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutForm đại diện cho dữ liệu đầu vào khi đăng xuất
+type LogoutForm struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// OAuthCallbackForm đại diện cho query string provider gửi về redirect URL
+type OAuthCallbackForm struct {
+	Code             string `form:"code"`
+	State            string `form:"state" binding:"required"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
+
+// VerifyEmailForm đại diện cho query string của link xác minh email
+type VerifyEmailForm struct {
+	Token string `form:"token" binding:"required"`
+}
+
+// ResendVerificationForm đại diện cho dữ liệu đầu vào khi gửi lại email xác minh
+type ResendVerificationForm struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ForgotPasswordForm đại diện cho dữ liệu đầu vào khi yêu cầu đặt lại mật khẩu
+type ForgotPasswordForm struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordForm đại diện cho dữ liệu đầu vào khi đặt lại mật khẩu bằng token trong email
+type ResetPasswordForm struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,max=256"`
+}
+
+// ChangePasswordForm đại diện cho dữ liệu đầu vào khi user đổi mật khẩu
+type ChangePasswordForm struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,max=256"`
+}
+
+// ChangeEmailForm đại diện cho dữ liệu đầu vào khi user yêu cầu đổi email
+type ChangeEmailForm struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewEmail        string `json:"new_email" binding:"required,email,max=50"`
+}
+
+// ConfirmEmailChangeForm đại diện cho token xác nhận gửi tới email mới
+type ConfirmEmailChangeForm struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ConfirmTotpForm đại diện cho mã TOTP đầu tiên từ app authenticator để xác nhận đăng ký
+type ConfirmTotpForm struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// TwoFactorLoginForm đại diện cho bước thứ hai khi đăng nhập: challenge token và mã TOTP hoặc mã khôi phục
+type TwoFactorLoginForm struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required,max=32"`
+}
+
+// CreateAPIKeyForm đại diện cho dữ liệu tạo API key. ExpiresInDays bỏ trống thì dùng thời hạn mặc định.
+type CreateAPIKeyForm struct {
+	Name          string   `json:"name" binding:"required,min=1,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,dive,oneof=profile:read profile:write users:read users:write"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1"`
+}
+
+// APIKeyResponse đại diện cho thông tin API key trả về (không bao gồm khóa)
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// APIKeyCreatedResponse trả về khóa đầy đủ, chỉ hiển thị một lần khi tạo
+type APIKeyCreatedResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// SessionResponse đại diện cho thông tin phiên đăng nhập trả về
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// OrganizationID là tổ chức đang hoạt động của phiên, null nếu chưa chọn tổ chức
+	OrganizationID *uuid.UUID `json:"organization_id"`
+	Current        bool       `json:"current"`
+}
+
+// CreateOrganizationForm đại diện cho dữ liệu tạo tổ chức mới
+type CreateOrganizationForm struct {
+	Name string `json:"name" binding:"required,min=2,max=100"`
+}
+
+// InviteOrganizationMemberForm đại diện cho dữ liệu mời thành viên vào tổ chức
+type InviteOrganizationMemberForm struct {
+	Email string `json:"email" binding:"required,email,max=50"`
+	Role  string `json:"role" binding:"required,oneof=admin member"`
+}
+
+// AcceptOrganizationInvitationForm đại diện cho token trong link mời tham gia tổ chức
+type AcceptOrganizationInvitationForm struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ChangeOrganizationMemberRoleForm đại diện cho role mới của thành viên trong tổ chức
+type ChangeOrganizationMemberRoleForm struct {
+	Role string `json:"role" binding:"required,oneof=owner admin member"`
+}
+
+// OrganizationResponse đại diện cho tổ chức của user kèm role của user trong tổ chức
+type OrganizationResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	JoinedAt  time.Time `json:"joined_at"`
+	Current   bool      `json:"current"`
+}
+
+// OrganizationMemberResponse đại diện cho thông tin thành viên của tổ chức trả về
+type OrganizationMemberResponse struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Role      string    `json:"role"`
+	JoinedAt  time.Time `json:"joined_at"`
+}
+
+// OrganizationInvitationResponse đại diện cho lời mời vừa gửi (không bao gồm token)
+type OrganizationInvitationResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,23 @@
+package usermodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailChangeRequest đại diện cho yêu cầu đổi email đang chờ xác nhận từ địa chỉ mới.
+// Token xác nhận là opaque, chỉ lưu giá trị băm và dùng một lần.
+type EmailChangeRequest struct {
+	ID          uuid.UUID  `json:"id" gorm:"column:id;"`
+	UserID      uuid.UUID  `json:"user_id" gorm:"column:user_id;"`
+	NewEmail    string     `json:"new_email" gorm:"column:new_email;"`
+	TokenHash   string     `json:"-" gorm:"column:token_hash;"`
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"column:expires_at;"`
+	ConfirmedAt *time.Time `json:"confirmed_at" gorm:"column:confirmed_at;"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"column:created_at;"`
+}
+
+func (EmailChangeRequest) TableName() string {
+	return "user_email_change_requests"
+}
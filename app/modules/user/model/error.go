@@ -10,4 +10,60 @@ var (
 	ErrUserIsDeleted           = errors.New("user is deleted")
 	ErrUserBannedOrDeleted     = errors.New("user banned or deleted")
 	ErrInvalidEmailAndPassword = errors.New("invalid email or password")
+	ErrInvalidRefreshToken     = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused      = errors.New("refresh token reuse detected, please log in again")
+	ErrOAuthProviderNotFound   = errors.New("oauth provider not found or disabled")
+	ErrOAuthStateInvalid       = errors.New("invalid or expired oauth state")
+	ErrOAuthLoginFailed        = errors.New("oauth login failed")
+	ErrOAuthEmailNotVerified   = errors.New("email is not verified by the oauth provider")
+	ErrEmailNotVerified        = errors.New("email is not verified, please check your inbox for the verification link")
+	ErrInvalidVerifyToken      = errors.New("invalid or expired email verification link")
+	ErrVerifyEmailThrottled    = errors.New("verification email was sent recently, please try again later")
+	ErrInvalidResetToken       = errors.New("invalid or expired password reset token")
+	ErrCredentialChanged       = errors.New("credentials have changed, please log in again")
+	ErrCurrentPasswordInvalid  = errors.New("current password is incorrect")
+	ErrNewPasswordSameAsOld    = errors.New("new password must be different from the current password")
+	ErrLoginThrottled          = errors.New("too many failed login attempts, please try again later")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("two-factor enrollment not found, please start enrollment again")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor authentication code")
+	ErrInvalidLoginChallenge   = errors.New("invalid or expired login challenge, please log in again")
+	ErrCannotManageSelf        = errors.New("admins cannot change their own role or status")
+	ErrUserStatusTransition    = errors.New("user status does not allow this action")
+	ErrInvalidAPIKey           = errors.New("invalid, expired or revoked API key")
+	ErrAPIKeyNotFound          = errors.New("API key not found")
+	ErrAPIKeyScopeNotAllowed   = errors.New("requested scope is not allowed for this user")
+	ErrAPIKeyLimitReached      = errors.New("maximum number of active API keys reached")
+	ErrAPIKeyLifetimeTooLong   = errors.New("API key lifetime exceeds the allowed maximum")
+	ErrPasswordPolicy          = errors.New("password does not meet the password policy")
+	ErrLoginLocked             = errors.New("too many failed login attempts, login is temporarily locked")
+	ErrSessionRevoked          = errors.New("session has been revoked, please log in again")
+	ErrSessionNotFound         = errors.New("session not found")
+	ErrUserAlreadyErased       = errors.New("user data has already been erased")
+	ErrProfileAccessDenied     = errors.New("you can only access your own profile")
+	ErrEmailAlreadyUsed        = errors.New("email is already used by another account")
+	ErrNewEmailSameAsOld       = errors.New("new email must be different from the current email")
+	ErrInvalidEmailChangeToken = errors.New("invalid or expired email change link")
+	ErrAvatarFileRequired      = errors.New("avatar file is required in multipart field \"avatar\"")
+	ErrAvatarTooLarge          = errors.New("avatar file exceeds the maximum allowed size")
+	ErrAvatarUnsupportedType   = errors.New("avatar must be a JPEG, PNG or GIF image")
+	ErrAvatarInvalidImage      = errors.New("avatar image is corrupted or its dimensions are too large")
+	ErrInvalidTimeRange        = errors.New("invalid time range, \"from\" must be before \"to\"")
+	ErrOrganizationNotFound    = errors.New("organization not found")
+	ErrNotOrganizationMember   = errors.New("you are not a member of this organization")
+	ErrOrgPermissionDenied     = errors.New("your organization role does not allow this action")
+	ErrOrgMemberNotFound       = errors.New("organization member not found")
+	ErrAlreadyOrgMember        = errors.New("user is already a member of this organization")
+	ErrLastOrganizationOwner   = errors.New("organization must keep at least one owner")
+	ErrInvalidInvitationToken  = errors.New("invalid or expired organization invitation")
+	ErrInvitationEmailMismatch = errors.New("invitation was sent to a different email address")
+	ErrSessionRequired         = errors.New("this action requires a login session")
+	ErrCannotImpersonate       = errors.New("admins cannot impersonate themselves or other admins")
+	ErrImpersonationInvalid    = errors.New("impersonation is no longer valid, the impersonating admin lost access")
+	ErrInvalidPhone            = errors.New("phone must be a valid number in E.164 format, e.g. +84912345678")
+	ErrPhoneRequired           = errors.New("add a phone number to your profile before verifying it")
+	ErrPhoneAlreadyVerified    = errors.New("phone number is already verified")
+	ErrPhoneCodeThrottled      = errors.New("too many verification codes requested, please try again later")
+	ErrInvalidPhoneCode        = errors.New("invalid or expired phone verification code")
+	ErrInvalidServiceClient    = errors.New("invalid introspection client credentials")
 )
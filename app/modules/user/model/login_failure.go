@@ -0,0 +1,28 @@
+package usermodel
+
+import "time"
+
+// Phạm vi đếm số lần đăng nhập sai
+const (
+	LoginFailureScopeAccount = "account"
+	LoginFailureScopeIP      = "ip"
+)
+
+// LoginFailure đếm số lần đăng nhập sai liên tiếp của một tài khoản (theo email) hoặc một IP.
+// Đếm theo email kể cả khi email không tồn tại để phản hồi không tiết lộ email nào đã đăng ký.
+type LoginFailure struct {
+	Scope        string     `json:"scope" gorm:"column:scope;primaryKey"`
+	Identifier   string     `json:"identifier" gorm:"column:identifier;primaryKey"`
+	FailedCount  int        `json:"failed_count" gorm:"column:failed_count;"`
+	LastFailedAt time.Time  `json:"last_failed_at" gorm:"column:last_failed_at;"`
+	LockedUntil  *time.Time `json:"locked_until" gorm:"column:locked_until;"`
+}
+
+func (LoginFailure) TableName() string {
+	return "user_login_failures"
+}
+
+// IsLocked kiểm tra bản ghi có đang bị khóa tại thời điểm now không
+func (f *LoginFailure) IsLocked(now time.Time) bool {
+	return f.LockedUntil != nil && now.Before(*f.LockedUntil)
+}
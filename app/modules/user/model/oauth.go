@@ -0,0 +1,52 @@
+package usermodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity liên kết user với một tài khoản ở identity provider bên ngoài (Gmail, Facebook...).
+// Một user có thể liên kết nhiều provider, mỗi cặp (Provider, Subject) chỉ thuộc về một user.
+type UserIdentity struct {
+	ID        uuid.UUID `json:"id" gorm:"column:id;"`
+	UserID    uuid.UUID `json:"user_id" gorm:"column:user_id;"`
+	Provider  string    `json:"provider" gorm:"column:provider;"`
+	Subject   string    `json:"subject" gorm:"column:subject;"`
+	Email     string    `json:"email" gorm:"column:email;"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at;"`
+}
+
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
+
+// OAuthState lưu state, nonce và PKCE code verifier của một lần đăng nhập OAuth đang chờ callback.
+// State chỉ lưu giá trị băm và bị xóa ngay khi callback sử dụng (single-use).
+type OAuthState struct {
+	ID           uuid.UUID `json:"id" gorm:"column:id;"`
+	Provider     string    `json:"provider" gorm:"column:provider;"`
+	StateHash    string    `json:"-" gorm:"column:state_hash;"`
+	Nonce        string    `json:"-" gorm:"column:nonce;"`
+	CodeVerifier string    `json:"-" gorm:"column:code_verifier;"`
+	ExpiresAt    time.Time `json:"expires_at" gorm:"column:expires_at;"`
+	CreatedAt    time.Time `json:"created_at" gorm:"column:created_at;"`
+}
+
+func (OAuthState) TableName() string {
+	return "user_oauth_states"
+}
+
+// IsExpired kiểm tra state đã hết hạn chưa
+func (s *OAuthState) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// OAuthIdentity là thông tin định danh lấy được từ provider sau khi đổi authorization code
+type OAuthIdentity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+}
@@ -0,0 +1,90 @@
+package usermodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Organization là một tổ chức (tenant), dữ liệu của các module khác được phân tách theo tổ chức
+type Organization struct {
+	ID        uuid.UUID `json:"id" gorm:"column:id;"`
+	Name      string    `json:"name" gorm:"column:name;"`
+	CreatedBy uuid.UUID `json:"created_by" gorm:"column:created_by;"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at;"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at;"`
+}
+
+func (Organization) TableName() string {
+	return "user_organizations"
+}
+
+// OrganizationMember là tư cách thành viên của user trong tổ chức, Role là một trong datatype.OrgRole*
+type OrganizationMember struct {
+	OrganizationID uuid.UUID `json:"organization_id" gorm:"column:organization_id;"`
+	UserID         uuid.UUID `json:"user_id" gorm:"column:user_id;"`
+	Role           string    `json:"role" gorm:"column:role;"`
+	CreatedAt      time.Time `json:"created_at" gorm:"column:created_at;"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"column:updated_at;"`
+}
+
+func (OrganizationMember) TableName() string {
+	return "user_organization_members"
+}
+
+// OrganizationInvitation là lời mời tham gia tổ chức gửi tới một email.
+// Token trong link mời là opaque, chỉ lưu giá trị băm và dùng một lần.
+type OrganizationInvitation struct {
+	ID             uuid.UUID  `json:"id" gorm:"column:id;"`
+	OrganizationID uuid.UUID  `json:"organization_id" gorm:"column:organization_id;"`
+	Email          string     `json:"email" gorm:"column:email;"`
+	Role           string     `json:"role" gorm:"column:role;"`
+	TokenHash      string     `json:"-" gorm:"column:token_hash;"`
+	InvitedBy      uuid.UUID  `json:"invited_by" gorm:"column:invited_by;"`
+	ExpiresAt      time.Time  `json:"expires_at" gorm:"column:expires_at;"`
+	AcceptedAt     *time.Time `json:"accepted_at" gorm:"column:accepted_at;"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"column:created_at;"`
+}
+
+func (OrganizationInvitation) TableName() string {
+	return "user_organization_invitations"
+}
+
+// UserOrganization là tổ chức mà user là thành viên kèm role của user trong tổ chức
+type UserOrganization struct {
+	Organization
+	Role     string    `json:"role" gorm:"column:role;"`
+	JoinedAt time.Time `json:"joined_at" gorm:"column:joined_at;"`
+}
+
+// ToResponse chuyển đổi UserOrganization sang OrganizationResponse DTO, current đánh dấu tổ chức đang hoạt động của phiên
+func (o *UserOrganization) ToResponse(current bool) *OrganizationResponse {
+	return &OrganizationResponse{
+		ID:        o.ID,
+		Name:      o.Name,
+		Role:      o.Role,
+		CreatedAt: o.CreatedAt,
+		JoinedAt:  o.JoinedAt,
+		Current:   current,
+	}
+}
+
+// OrganizationMemberDetail là thành viên của tổ chức kèm thông tin cơ bản của user
+type OrganizationMemberDetail struct {
+	OrganizationMember
+	Email     string `gorm:"column:email;"`
+	FirstName string `gorm:"column:first_name;"`
+	LastName  string `gorm:"column:last_name;"`
+}
+
+// ToResponse chuyển đổi OrganizationMemberDetail sang OrganizationMemberResponse DTO
+func (m *OrganizationMemberDetail) ToResponse() *OrganizationMemberResponse {
+	return &OrganizationMemberResponse{
+		UserID:    m.UserID,
+		Email:     m.Email,
+		FirstName: m.FirstName,
+		LastName:  m.LastName,
+		Role:      m.Role,
+		JoinedAt:  m.CreatedAt,
+	}
+}
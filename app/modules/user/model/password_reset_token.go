@@ -0,0 +1,21 @@
+package usermodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordResetToken đại diện cho token đặt lại mật khẩu (opaque, chỉ lưu giá trị băm, dùng một lần)
+type PasswordResetToken struct {
+	ID        uuid.UUID  `json:"id" gorm:"column:id;"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"column:user_id;"`
+	TokenHash string     `json:"-" gorm:"column:token_hash;"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"column:expires_at;"`
+	UsedAt    *time.Time `json:"used_at" gorm:"column:used_at;"`
+	CreatedAt time.Time  `json:"created_at" gorm:"column:created_at;"`
+}
+
+func (PasswordResetToken) TableName() string {
+	return "user_password_reset_tokens"
+}
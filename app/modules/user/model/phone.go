@@ -0,0 +1,58 @@
+package usermodel
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultPhoneCountryCode là mã quốc gia mặc định khi số điện thoại nhập theo định dạng trong nước (bắt đầu bằng 0)
+const DefaultPhoneCountryCode = "84"
+
+var e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// NormalizePhone chuẩn hóa số điện thoại về định dạng E.164 (ví dụ: +84912345678).
+// Bỏ khoảng trắng, dấu gạch, dấu chấm và ngoặc; tiền tố quốc tế "00" được đổi thành "+",
+// số trong nước bắt đầu bằng 0 được gắn mã quốc gia defaultCountryCode.
+func NormalizePhone(raw string, defaultCountryCode string) (string, error) {
+	phone := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(raw))
+
+	switch {
+	case strings.HasPrefix(phone, "+"):
+	case strings.HasPrefix(phone, "00"):
+		phone = "+" + phone[2:]
+	case strings.HasPrefix(phone, "0") && defaultCountryCode != "":
+		phone = "+" + strings.TrimPrefix(defaultCountryCode, "+") + phone[1:]
+	}
+
+	if !e164Pattern.MatchString(phone) {
+		return "", ErrInvalidPhone
+	}
+
+	return phone, nil
+}
+
+// PhoneVerificationCode là mã OTP gửi qua SMS để xác minh số điện thoại của user.
+// Chỉ lưu giá trị băm của mã, mã gắn với số điện thoại tại thời điểm gửi và bị vô hiệu khi nhập sai quá số lần cho phép.
+type PhoneVerificationCode struct {
+	ID         uuid.UUID  `json:"id" gorm:"column:id;"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"column:user_id;"`
+	Phone      string     `json:"phone" gorm:"column:phone;"`
+	CodeHash   string     `json:"-" gorm:"column:code_hash;"`
+	Attempts   int        `json:"attempts" gorm:"column:attempts;"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"column:expires_at;"`
+	ConsumedAt *time.Time `json:"consumed_at" gorm:"column:consumed_at;"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"column:created_at;"`
+}
+
+func (PhoneVerificationCode) TableName() string {
+	return "user_phone_verification_codes"
+}
@@ -0,0 +1,42 @@
+package usermodel
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		name               string
+		raw                string
+		defaultCountryCode string
+		want               string
+		wantErr            error
+	}{
+		{name: "e164", raw: "+84912345678", defaultCountryCode: DefaultPhoneCountryCode, want: "+84912345678"},
+		{name: "domestic", raw: "0912345678", defaultCountryCode: DefaultPhoneCountryCode, want: "+84912345678"},
+		{name: "domestic with separators", raw: " 091-234.5678 ", defaultCountryCode: DefaultPhoneCountryCode, want: "+84912345678"},
+		{name: "international prefix 00", raw: "0084 912 345 678", defaultCountryCode: DefaultPhoneCountryCode, want: "+84912345678"},
+		{name: "parentheses", raw: "+1 (415) 555-2671", defaultCountryCode: DefaultPhoneCountryCode, want: "+14155552671"},
+		{name: "default code with plus", raw: "0912345678", defaultCountryCode: "+84", want: "+84912345678"},
+		{name: "domestic without default code", raw: "0912345678", defaultCountryCode: "", wantErr: ErrInvalidPhone},
+		{name: "no prefix", raw: "912345678", defaultCountryCode: DefaultPhoneCountryCode, wantErr: ErrInvalidPhone},
+		{name: "too short", raw: "+8412345", defaultCountryCode: DefaultPhoneCountryCode, wantErr: ErrInvalidPhone},
+		{name: "too long", raw: "+8491234567890123", defaultCountryCode: DefaultPhoneCountryCode, wantErr: ErrInvalidPhone},
+		{name: "country code starting with 0", raw: "+0912345678", defaultCountryCode: DefaultPhoneCountryCode, wantErr: ErrInvalidPhone},
+		{name: "letters", raw: "+84 91234abcd", defaultCountryCode: DefaultPhoneCountryCode, wantErr: ErrInvalidPhone},
+		{name: "empty", raw: "", defaultCountryCode: DefaultPhoneCountryCode, wantErr: ErrInvalidPhone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizePhone(tt.raw, tt.defaultCountryCode)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NormalizePhone(%q) error = %v, want %v", tt.raw, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizePhone(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
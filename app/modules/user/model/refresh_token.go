@@ -0,0 +1,29 @@
+package usermodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken đại diện cho refresh token (opaque) đã cấp cho user.
+// Các token được xoay vòng từ cùng một lần đăng nhập thuộc cùng một FamilyID.
+type RefreshToken struct {
+	ID        uuid.UUID  `json:"id" gorm:"column:id;"`
+	FamilyID  uuid.UUID  `json:"family_id" gorm:"column:family_id;"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"column:user_id;"`
+	TokenHash string     `json:"-" gorm:"column:token_hash;"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"column:expires_at;"`
+	UsedAt    *time.Time `json:"used_at" gorm:"column:used_at;"`
+	RevokedAt *time.Time `json:"revoked_at" gorm:"column:revoked_at;"`
+	CreatedAt time.Time  `json:"created_at" gorm:"column:created_at;"`
+}
+
+func (RefreshToken) TableName() string {
+	return "user_refresh_tokens"
+}
+
+// IsExpired kiểm tra refresh token đã hết hạn chưa
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
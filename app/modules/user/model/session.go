@@ -0,0 +1,45 @@
+package usermodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session là một phiên đăng nhập trên một thiết bị. ID của session cũng là FamilyID
+// của các refresh token được xoay vòng trong phiên, access token mang ID này ở claim "sid".
+type Session struct {
+	ID         uuid.UUID  `json:"id" gorm:"column:id;"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"column:user_id;"`
+	IPAddress  string     `json:"ip_address" gorm:"column:ip_address;"`
+	UserAgent  string     `json:"user_agent" gorm:"column:user_agent;"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"column:created_at;"`
+	LastSeenAt time.Time  `json:"last_seen_at" gorm:"column:last_seen_at;"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"column:expires_at;"`
+	RevokedAt  *time.Time `json:"revoked_at" gorm:"column:revoked_at;"`
+	// OrganizationID là tổ chức đang hoạt động của phiên, access token cấp trong phiên mang tổ chức này ở claim "org"
+	OrganizationID *uuid.UUID `json:"organization_id" gorm:"column:organization_id;"`
+}
+
+func (Session) TableName() string {
+	return "user_sessions"
+}
+
+// IsActive kiểm tra session chưa bị thu hồi và chưa hết hạn tại thời điểm now
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// ToResponse chuyển đổi Session sang SessionResponse DTO, current đánh dấu phiên của request hiện tại
+func (s *Session) ToResponse(current bool) *SessionResponse {
+	return &SessionResponse{
+		ID:             s.ID,
+		IPAddress:      s.IPAddress,
+		UserAgent:      s.UserAgent,
+		CreatedAt:      s.CreatedAt,
+		LastSeenAt:     s.LastSeenAt,
+		ExpiresAt:      s.ExpiresAt,
+		Current:        current,
+		OrganizationID: s.OrganizationID,
+	}
+}
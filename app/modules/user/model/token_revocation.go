@@ -0,0 +1,22 @@
+package usermodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenRevocation đại diện cho một bản ghi trong denylist access token.
+// Nếu JTI rỗng, mọi token của user được cấp trước thời điểm RevokedAt đều bị thu hồi (đăng xuất mọi thiết bị).
+// Bản ghi có thể xóa sau ExpiresAt vì khi đó các token liên quan đã hết hạn.
+type TokenRevocation struct {
+	ID        uuid.UUID `json:"id" gorm:"column:id;"`
+	JTI       *string   `json:"jti" gorm:"column:jti;"`
+	UserID    uuid.UUID `json:"user_id" gorm:"column:user_id;"`
+	RevokedAt time.Time `json:"revoked_at" gorm:"column:revoked_at;"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"column:expires_at;"`
+}
+
+func (TokenRevocation) TableName() string {
+	return "user_token_revocations"
+}
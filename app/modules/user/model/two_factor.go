@@ -0,0 +1,35 @@
+package usermodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TotpCredential lưu secret TOTP của user. ConfirmedAt nil nghĩa là đang đăng ký, chưa xác nhận bằng mã từ app.
+// LastUsedStep là chu kỳ TOTP đã dùng gần nhất, chặn dùng lại cùng một mã.
+type TotpCredential struct {
+	UserID       uuid.UUID  `json:"user_id" gorm:"column:user_id;primaryKey"`
+	Secret       string     `json:"-" gorm:"column:secret;"`
+	ConfirmedAt  *time.Time `json:"confirmed_at" gorm:"column:confirmed_at;"`
+	LastUsedStep *int64     `json:"-" gorm:"column:last_used_step;"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"column:created_at;"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"column:updated_at;"`
+}
+
+func (TotpCredential) TableName() string {
+	return "user_totp_credentials"
+}
+
+// RecoveryCode là mã khôi phục dùng một lần khi mất thiết bị TOTP (chỉ lưu giá trị băm)
+type RecoveryCode struct {
+	ID        uuid.UUID  `json:"id" gorm:"column:id;"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"column:user_id;"`
+	CodeHash  string     `json:"-" gorm:"column:code_hash;"`
+	UsedAt    *time.Time `json:"used_at" gorm:"column:used_at;"`
+	CreatedAt time.Time  `json:"created_at" gorm:"column:created_at;"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
@@ -39,6 +39,15 @@ type User struct {
 	Email     string     `json:"email" gorm:"column:email;"`
 	Password  string     `json:"password" gorm:"column:password;"`
 	Salt      string     `json:"salt" gorm:"column:salt;"`
+
+	EmailVerifiedAt         *time.Time `json:"email_verified_at" gorm:"column:email_verified_at;"`
+	EmailVerificationSentAt *time.Time `json:"-" gorm:"column:email_verification_sent_at;"`
+	CredentialVersion       int        `json:"-" gorm:"column:credential_version;"`
+	TwoFactorEnabledAt      *time.Time `json:"two_factor_enabled_at" gorm:"column:two_factor_enabled_at;"`
+	ErasedAt                *time.Time `json:"-" gorm:"column:erased_at;"`
+	AvatarKey               *string    `json:"-" gorm:"column:avatar_key;"`
+	AvatarThumbnailKey      *string    `json:"-" gorm:"column:avatar_thumbnail_key;"`
+	PhoneVerifiedAt         *time.Time `json:"phone_verified_at" gorm:"column:phone_verified_at;"`
 }
 
 func (User) TableName() string {
@@ -49,7 +58,30 @@ func (u *User) GetFullName() string {
 	return fmt.Sprintf("%s %s", u.FirstName, u.LastName)
 }
 
-// ToProfileResponse chuyển đổi User entity sang ProfileResponse DTO
+// IsErased kiểm tra dữ liệu cá nhân của user đã bị xóa theo yêu cầu chưa
+func (u *User) IsErased() bool {
+	return u.ErasedAt != nil
+}
+
+// IsTwoFactorEnabled kiểm tra user đã bật xác thực hai lớp chưa
+func (u *User) IsTwoFactorEnabled() bool {
+	return u.TwoFactorEnabledAt != nil
+}
+
+// CheckActive trả về lỗi theo trạng thái khi user không được cấp token, chỉ user active mới được đăng nhập và refresh
+func (u *User) CheckActive() error {
+	switch u.Status {
+	case StatusActive:
+		return nil
+	case StatusPending:
+		return ErrEmailNotVerified
+	default:
+		return ErrUserBannedOrDeleted
+	}
+}
+
+// ToProfileResponse chuyển đổi User entity sang ProfileResponse DTO.
+// URL ảnh đại diện phụ thuộc cấu hình lưu trữ file nên được điền ở tầng service.
 func (u *User) ToProfileResponse() *ProfileResponse {
 	return &ProfileResponse{
 		ID:        u.ID,
@@ -63,5 +95,7 @@ func (u *User) ToProfileResponse() *ProfileResponse {
 		Type:      u.Type,
 		CreatedAt: u.CreatedAt,
 		UpdatedAt: u.UpdatedAt,
+
+		PhoneVerifiedAt: u.PhoneVerifiedAt,
 	}
 }
@@ -1,6 +1,8 @@
 package user
 
 import (
+	"cmp"
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -9,7 +11,9 @@ import (
 	"time"
 
 	userhttpgin "fat2fast/ikv/modules/user/infras/controller/http-gin"
+	useroauth "fat2fast/ikv/modules/user/infras/oauth"
 	userrepository "fat2fast/ikv/modules/user/infras/repository/gorm-pgsql"
+	usermodel "fat2fast/ikv/modules/user/model"
 	userservice "fat2fast/ikv/modules/user/service"
 	userurlv1 "fat2fast/ikv/modules/user/urls/v1"
 	"fat2fast/ikv/shared"
@@ -18,6 +22,7 @@ import (
 	"fat2fast/ikv/shared/middleware"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -57,12 +62,134 @@ type Config struct {
 			ConnMaxLifetime string `yaml:"conn_max_lifetime"`
 		} `yaml:"performance"`
 	} `yaml:"database"`
+
+	Auth struct {
+		AccessTokenExpIn       int    `yaml:"access_token_exp_in"`
+		RefreshTokenExpIn      int    `yaml:"refresh_token_exp_in"`
+		RevocationSyncInterval string `yaml:"revocation_sync_interval"`
+
+		Jwt struct {
+			KeyDir            string `yaml:"key_dir"`
+			ActiveKid         string `yaml:"active_kid"`
+			LegacySecretUntil string `yaml:"legacy_secret_until"`
+		} `yaml:"jwt"`
+
+		EmailVerification struct {
+			Secret         string `yaml:"secret"`
+			LinkURL        string `yaml:"link_url"`
+			TokenExpIn     int    `yaml:"token_exp_in"`
+			ResendInterval string `yaml:"resend_interval"`
+		} `yaml:"email_verification"`
+
+		PasswordReset struct {
+			LinkURL         string `yaml:"link_url"`
+			TokenExpIn      int    `yaml:"token_exp_in"`
+			RateLimitWindow string `yaml:"rate_limit_window"`
+			RateLimitMax    int    `yaml:"rate_limit_max"`
+		} `yaml:"password_reset"`
+
+		EmailChange struct {
+			LinkURL    string `yaml:"link_url"`
+			TokenExpIn int    `yaml:"token_exp_in"`
+		} `yaml:"email_change"`
+
+		OrganizationInvitation struct {
+			LinkURL    string `yaml:"link_url"`
+			TokenExpIn int    `yaml:"token_exp_in"`
+		} `yaml:"organization_invitation"`
+
+		Impersonation struct {
+			TokenExpIn int `yaml:"token_exp_in"`
+		} `yaml:"impersonation"`
+
+		PhoneVerification struct {
+			DefaultCountryCode string `yaml:"default_country_code"`
+			CodeExpIn          int    `yaml:"code_exp_in"`
+			ResendInterval     string `yaml:"resend_interval"`
+			RateLimitWindow    string `yaml:"rate_limit_window"`
+			RateLimitMax       int    `yaml:"rate_limit_max"`
+			MaxAttempts        int    `yaml:"max_attempts"`
+		} `yaml:"phone_verification"`
+
+		Introspection struct {
+			Clients []struct {
+				ID     string `yaml:"id"`
+				Secret string `yaml:"secret"`
+			} `yaml:"clients"`
+		} `yaml:"introspection"`
+
+		LoginProtection struct {
+			MaxAccountFailures int    `yaml:"max_account_failures"`
+			MaxIPFailures      int    `yaml:"max_ip_failures"`
+			FailureWindow      string `yaml:"failure_window"`
+			LockoutDuration    string `yaml:"lockout_duration"`
+			FreeAttempts       int    `yaml:"free_attempts"`
+			BaseDelay          string `yaml:"base_delay"`
+			MaxDelay           string `yaml:"max_delay"`
+		} `yaml:"login_protection"`
+
+		TwoFactor struct {
+			Issuer            string `yaml:"issuer"`
+			ChallengeExpIn    int    `yaml:"challenge_exp_in"`
+			RecoveryCodeCount int    `yaml:"recovery_code_count"`
+		} `yaml:"two_factor"`
+
+		PasswordPolicy struct {
+			MinLength            int    `yaml:"min_length"`
+			MaxLength            int    `yaml:"max_length"`
+			RequireUppercase     bool   `yaml:"require_uppercase"`
+			RequireLowercase     bool   `yaml:"require_lowercase"`
+			RequireDigit         bool   `yaml:"require_digit"`
+			RequireSymbol        bool   `yaml:"require_symbol"`
+			DisallowUserInfo     bool   `yaml:"disallow_user_info"`
+			BreachedPasswordFile string `yaml:"breached_password_file"`
+		} `yaml:"password_policy"`
+
+		PasswordHashing struct {
+			Argon2Memory      uint32 `yaml:"argon2_memory"`
+			Argon2Iterations  uint32 `yaml:"argon2_iterations"`
+			Argon2Parallelism uint8  `yaml:"argon2_parallelism"`
+		} `yaml:"password_hashing"`
+
+		APIKeys struct {
+			DefaultLifetimeDays int `yaml:"default_lifetime_days"`
+			MaxLifetimeDays     int `yaml:"max_lifetime_days"`
+			MaxPerUser          int `yaml:"max_per_user"`
+		} `yaml:"api_keys"`
+
+		OAuth struct {
+			StateTTL  string                              `yaml:"state_ttl"`
+			Providers map[string]useroauth.ProviderConfig `yaml:"providers"`
+		} `yaml:"oauth"`
+	} `yaml:"auth"`
+
+	Mailer  sharecomponent.MailerConfig   `yaml:"mailer"`
+	SMS     sharecomponent.SMSConfig      `yaml:"sms"`
+	Storage sharecomponent.UploaderConfig `yaml:"storage"`
+
+	Avatar struct {
+		MaxSize       int64 `yaml:"max_size"`
+		MaxPixels     int64 `yaml:"max_pixels"`
+		Size          int   `yaml:"size"`
+		ThumbnailSize int   `yaml:"thumbnail_size"`
+	} `yaml:"avatar"`
 }
 
 // Module đại diện cho module User
 type Module struct {
-	config Config
-	DB     *gorm.DB
+	config        Config
+	DB            *gorm.DB
+	mldProvider   sharedinfras.IMiddlewareProvider
+	keyRing       *sharecomponent.KeyRing
+	jwtComp       *sharecomponent.JwtComp
+	tokenDenylist *userservice.TokenDenylist
+	oauthProvs    map[string]userservice.IOAuthProvider
+	mailer        sharecomponent.IMailer
+	smsSender     sharecomponent.ISMSSender
+	uploader      sharecomponent.IUploader
+	tokenSigner   *sharecomponent.TokenSigner
+	pwdPolicy     *sharecomponent.PasswordPolicy
+	userDataReg   userservice.IUserDataRegistry
 }
 
 // NewModule tạo một instance mới của module User
@@ -83,6 +210,47 @@ func NewModule() (*Module, error) {
 		config: config,
 	}
 
+	// Nạp key ring ký JWT nếu có cấu hình thư mục khóa, nếu không thì ký HS256 bằng JWT_SECRET_KEY
+	if err := module.loadKeyRing(); err != nil {
+		return nil, fmt.Errorf("error loading jwt signing keys: %v", err)
+	}
+	if err := module.loadJwtComp(); err != nil {
+		return nil, fmt.Errorf("error initializing jwt component: %v", err)
+	}
+
+	// Khởi tạo các identity provider cho social login
+	if err := module.loadOAuthProviders(); err != nil {
+		return nil, fmt.Errorf("error loading oauth providers: %v", err)
+	}
+
+	// Khởi tạo mailer và khóa ký link gửi qua email
+	module.mailer, err = sharecomponent.NewMailer(config.Mailer)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing mailer: %v", err)
+	}
+	emailSecret, err := module.emailSigningSecret()
+	if err != nil {
+		return nil, fmt.Errorf("error loading email signing secret: %v", err)
+	}
+	module.tokenSigner = sharecomponent.NewTokenSigner(emailSecret)
+
+	// Khởi tạo SMS sender gửi mã xác minh số điện thoại
+	module.smsSender, err = sharecomponent.NewSMSSender(config.SMS)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing sms sender: %v", err)
+	}
+
+	// Khởi tạo kho lưu trữ file tải lên (ảnh đại diện)
+	module.uploader, err = sharecomponent.NewUploader(module.config.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing uploader: %v", err)
+	}
+
+	// Khởi tạo chính sách mật khẩu và danh sách mật khẩu bị lộ
+	if err := module.loadPasswordPolicy(); err != nil {
+		return nil, fmt.Errorf("error loading password policy: %v", err)
+	}
+
 	// Kết nối database nếu module được kích hoạt
 	if module.IsEnabled() {
 		// retry 5 times
@@ -135,10 +303,7 @@ func (m *Module) connectDatabase() (*gorm.DB, error) {
 	}
 
 	// Parse connection max lifetime
-	connMaxLifetime, err := time.ParseDuration(m.config.Database.Performance.ConnMaxLifetime)
-	if err != nil {
-		connMaxLifetime = 5 * time.Minute // Default: 5 minutes
-	}
+	connMaxLifetime := shared.DurationOrDefault(m.config.Database.Performance.ConnMaxLifetime, 5*time.Minute)
 
 	sqlDB.SetMaxOpenConns(m.config.Database.Performance.MaxOpenConns)
 	sqlDB.SetMaxIdleConns(m.config.Database.Performance.MaxIdleConns)
@@ -172,19 +337,25 @@ func (m *Module) Register(router *gin.Engine) error {
 
 	log.Printf("Registering module: %s (v%s)", m.GetName(), m.config.Module.Version)
 	db := m.GetDB()
-	appCtx := sharedinfras.NewAppContext(db)
-	// Đăng ký routes và middleware sẽ được thêm vào ở giai đoạn sau
-	controller := Initialize(appCtx)
-	routes := userurlv1.GetRoutes(controller)
+	appCtx := sharedinfras.NewAppContext(db, m.MiddlewareProvider(), m.uploader)
+	controller := m.Initialize(appCtx)
+	routes := userurlv1.GetRoutes(controller, appCtx.MiddlewareProvider())
 	log.Printf("Registering module routes")
 	router.Use(middleware.RecoverMiddleware())
 	log.Printf("Registering RecoverMiddleware")
+
+	for _, route := range userurlv1.GetWellKnownRoutes(controller) {
+		router.Handle(route.Method, route.Path, route.Handlers()...)
+	}
+	// File tải lên được lưu trên máy chủ thì phục vụ trực tiếp (không liệt kê thư mục)
+	if servePath := m.config.Storage.Local.ServePath; servePath != "" && (m.config.Storage.Driver == "local" || m.config.Storage.Driver == "") {
+		router.Static(servePath, m.config.Storage.Local.Dir)
+	}
 	v1 := router.Group("/v1")
 	userV1 := v1.Group("/users")
 
 	for _, route := range routes {
-		userV1.Handle(route.Method, route.Path, route.HandlerFunc)
-
+		userV1.Handle(route.Method, route.Path, route.Handlers()...)
 	}
 	// Hiện tại chỉ đăng ký module cơ bản
 
@@ -211,33 +382,403 @@ func (m *Module) GetDB() *gorm.DB {
 	return m.DB
 }
 
-func Initialize(appCtx sharedinfras.IAppContext) *userhttpgin.UserHTTPController {
+// MiddlewareProvider trả về provider xác thực dựa trên token và dữ liệu user của module,
+// dùng chung cho các module khác cần bảo vệ route
+func (m *Module) MiddlewareProvider() sharedinfras.IMiddlewareProvider {
+	if m.mldProvider == nil {
+		dbCtx := sharedinfras.NewDbContext(m.GetDB())
+		userRepository := userrepository.NewUserRepository(dbCtx)
+		introspectQryHdl := userservice.NewIntrospectTokenQueryHandler(userRepository, m.getJwtComp())
+		mldProvider := middleware.NewMiddlewareProvider(introspectQryHdl)
+		mldProvider.SetAPIKeyIntrospector(userservice.NewIntrospectAPIKeyQueryHandler(userRepository))
+		m.mldProvider = mldProvider
+	}
+	return m.mldProvider
+}
+
+// SetUserDataRegistry thiết lập registry dùng để trích xuất/xóa dữ liệu của user trên mọi module.
+// Nếu không thiết lập, yêu cầu dữ liệu cá nhân chỉ xử lý dữ liệu của module User.
+func (m *Module) SetUserDataRegistry(registry userservice.IUserDataRegistry) {
+	m.userDataReg = registry
+}
+
+// ExportUserData trả về dữ liệu module User lưu về user (profile, liên kết OAuth, phiên, API key, audit)
+func (m *Module) ExportUserData(ctx context.Context, userID uuid.UUID) (any, error) {
+	userRepository := userrepository.NewUserRepository(sharedinfras.NewDbContext(m.GetDB()))
+	data, err := userservice.NewUserDataQueryHandler(userRepository, m.uploader).Execute(ctx, &userservice.UserDataQuery{UserID: userID})
+	if err != nil || data == nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// EraseUserData ẩn danh bản ghi user và xóa dữ liệu phụ của user
+func (m *Module) EraseUserData(ctx context.Context, userID uuid.UUID) error {
+	userRepository := userrepository.NewUserRepository(sharedinfras.NewDbContext(m.GetDB()))
+	return userservice.NewAnonymizeUserCommandHandler(userRepository, m.uploader).Execute(ctx, &userservice.AnonymizeUserCommand{UserID: userID})
+}
+
+// ExportUserDataArchive trích xuất dữ liệu của user từ mọi module, dùng cho CLI
+func (m *Module) ExportUserDataArchive(ctx context.Context, userID uuid.UUID) (*usermodel.UserDataArchive, error) {
+	userRepository := userrepository.NewUserRepository(sharedinfras.NewDbContext(m.GetDB()))
+	cmd := &userservice.ExportUserDataCommand{UserID: userID}
+	return userservice.NewExportUserDataCommandHandler(userRepository, m.userDataRegistry()).Execute(ctx, cmd)
+}
+
+// EraseUser xóa dữ liệu của user trên mọi module và ghi audit, dùng cho CLI
+func (m *Module) EraseUser(ctx context.Context, userID uuid.UUID) error {
+	userRepository := userrepository.NewUserRepository(sharedinfras.NewDbContext(m.GetDB()))
+	cmd := &userservice.EraseUserDataCommand{UserID: userID}
+	return userservice.NewEraseUserDataCommandHandler(userRepository, m.userDataRegistry()).Execute(ctx, cmd)
+}
+
+// userDataRegistry trả về registry đã thiết lập, hoặc registry chỉ gồm module User
+func (m *Module) userDataRegistry() userservice.IUserDataRegistry {
+	if m.userDataReg == nil {
+		registry := shared.NewModuleRegistry()
+		registry.RegisterModule(m)
+		m.userDataReg = registry
+	}
+	return m.userDataReg
+}
+
+// loadKeyRing nạp các khóa PEM từ auth.jwt.key_dir, khóa auth.jwt.active_kid dùng để ký.
+// Khóa HS256 cũ (JWT_SECRET_KEY) chỉ được giữ để verify token cấp trước khi chuyển sang khóa bất đối xứng
+// khi cấu hình auth.jwt.legacy_secret_until, và bị từ chối sau thời điểm đó
+// (nên đặt bằng thời điểm chuyển khóa cộng thời hạn access token).
+func (m *Module) loadKeyRing() error {
+	jwtConfig := m.config.Auth.Jwt
+	if jwtConfig.KeyDir == "" {
+		return nil
+	}
+
+	var legacyKeys []*sharecomponent.SigningKey
+	if jwtConfig.LegacySecretUntil != "" {
+		until, err := time.Parse(time.RFC3339, jwtConfig.LegacySecretUntil)
+		if err != nil {
+			return fmt.Errorf("invalid auth.jwt.legacy_secret_until: %v", err)
+		}
+
+		secretKey := os.Getenv("JWT_SECRET_KEY")
+		if time.Now().Before(until) {
+			if len(secretKey) < sharecomponent.MinHMACSecretLength {
+				return fmt.Errorf("JWT_SECRET_KEY is required until auth.jwt.legacy_secret_until: %v", sharecomponent.ErrHMACSecretTooShort)
+			}
+			legacyKey := sharecomponent.NewHMACSigningKey("", []byte(secretKey))
+			legacyKey.NotAfter = until
+			legacyKeys = append(legacyKeys, legacyKey)
+			// Ai giữ JWT_SECRET_KEY vẫn ký được token hợp lệ tới thời điểm này
+			log.Printf("WARNING: module %s accepts legacy HS256 tokens signed with JWT_SECRET_KEY until %s", m.GetName(), until.Format(time.RFC3339))
+		}
+	}
+
+	keyRing, err := sharecomponent.LoadKeyRingFromDir(jwtConfig.KeyDir, jwtConfig.ActiveKid, legacyKeys...)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Module %s signing tokens with key %s (%s)", m.GetName(), keyRing.Active().Kid, keyRing.Active().Method.Alg())
+	m.keyRing = keyRing
+	return nil
+}
+
+// loadOAuthProviders khởi tạo client cho các provider được bật trong auth.oauth.providers
+func (m *Module) loadOAuthProviders() error {
+	m.oauthProvs = make(map[string]userservice.IOAuthProvider)
+
+	for name, providerConfig := range m.config.Auth.OAuth.Providers {
+		if !providerConfig.Enabled {
+			continue
+		}
+
+		provider, err := useroauth.NewProvider(name, providerConfig)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Module %s enabled oauth provider %s", m.GetName(), name)
+		m.oauthProvs[name] = provider
+	}
+
+	return nil
+}
+
+// emailSigningSecret trả về khóa ký link gửi qua email và challenge 2FA từ auth.email_verification.secret.
+// Khóa phải cố định giữa các lần khởi động và giữa các instance, và không được dùng lại JWT_SECRET_KEY.
+func (m *Module) emailSigningSecret() ([]byte, error) {
+	secret := m.config.Auth.EmailVerification.Secret
+	if len(secret) < sharecomponent.MinHMACSecretLength {
+		return nil, fmt.Errorf("auth.email_verification.secret is required: %v", sharecomponent.ErrHMACSecretTooShort)
+	}
+	if secret == os.Getenv("JWT_SECRET_KEY") {
+		return nil, fmt.Errorf("auth.email_verification.secret must differ from JWT_SECRET_KEY")
+	}
+
+	return []byte(secret), nil
+}
+
+// loginThrottleConfig đọc cấu hình auth.login_protection, giá trị thiếu hoặc không hợp lệ dùng mặc định
+func (m *Module) loginThrottleConfig() userservice.LoginThrottleConfig {
+	protection := m.config.Auth.LoginProtection
+
+	config := userservice.LoginThrottleConfig{
+		MaxAccountFailures: shared.IntOrDefault(protection.MaxAccountFailures, 5),
+		MaxIPFailures:      shared.IntOrDefault(protection.MaxIPFailures, 50),
+		FailureWindow:      shared.DurationOrDefault(protection.FailureWindow, 15*time.Minute),
+		LockoutDuration:    shared.DurationOrDefault(protection.LockoutDuration, 15*time.Minute),
+		FreeAttempts:       max(protection.FreeAttempts, 0),
+		BaseDelay:          shared.DurationOrDefault(protection.BaseDelay, time.Second),
+		MaxDelay:           shared.DurationOrDefault(protection.MaxDelay, 30*time.Second),
+	}
+	// base_delay "0s" tắt thời gian chờ tăng dần, chỉ giữ khóa tạm thời
+	if d, err := time.ParseDuration(protection.BaseDelay); err == nil && d == 0 {
+		config.BaseDelay = 0
+	}
+
+	return config
+}
+
+// loadPasswordPolicy khởi tạo chính sách mật khẩu từ auth.password_policy,
+// file danh sách mật khẩu bị lộ chỉ được mở khi có cấu hình breached_password_file
+func (m *Module) loadPasswordPolicy() error {
+	policy := m.config.Auth.PasswordPolicy
+
+	config := sharecomponent.PasswordPolicyConfig{
+		MinLength:        policy.MinLength,
+		MaxLength:        policy.MaxLength,
+		RequireUppercase: policy.RequireUppercase,
+		RequireLowercase: policy.RequireLowercase,
+		RequireDigit:     policy.RequireDigit,
+		RequireSymbol:    policy.RequireSymbol,
+		DisallowUserInfo: policy.DisallowUserInfo,
+	}
+	config.MinLength = shared.IntOrDefault(config.MinLength, 8)
+	config.MaxLength = shared.IntOrDefault(config.MaxLength, 128)
+
+	if policy.BreachedPasswordFile != "" {
+		breachedList, err := sharecomponent.OpenBreachedPasswordList(policy.BreachedPasswordFile)
+		if err != nil {
+			return err
+		}
+		log.Printf("Module %s checking passwords against breached list %s", m.GetName(), policy.BreachedPasswordFile)
+		config.BreachedList = breachedList
+	}
+
+	m.pwdPolicy = sharecomponent.NewPasswordPolicy(config)
+	return nil
+}
+
+// passwordHasher khởi tạo hasher argon2id theo auth.password_hashing (Memory tính bằng KiB),
+// bcrypt được giữ lại để verify password cũ và nâng cấp khi user đăng nhập
+func (m *Module) passwordHasher() *sharecomponent.PasswordHasher {
+	hashing := m.config.Auth.PasswordHashing
+
+	params := sharecomponent.DefaultArgon2Params() // Default: 64 MiB, 3 iterations, 2 threads
+	params.Memory = shared.IntOrDefault(hashing.Argon2Memory, params.Memory)
+	params.Iterations = shared.IntOrDefault(hashing.Argon2Iterations, params.Iterations)
+	params.Parallelism = shared.IntOrDefault(hashing.Argon2Parallelism, params.Parallelism)
+
+	return sharecomponent.NewPasswordHasher(sharecomponent.NewArgon2idScheme(params), sharecomponent.NewLegacyBcryptScheme())
+}
+
+// apiKeyConfig đọc cấu hình auth.api_keys, giá trị thiếu hoặc không hợp lệ dùng mặc định
+func (m *Module) apiKeyConfig() userservice.APIKeyConfig {
+	apiKeys := m.config.Auth.APIKeys
+
+	defaultDays := shared.IntOrDefault(apiKeys.DefaultLifetimeDays, 90)
+	maxDays := shared.IntOrDefault(apiKeys.MaxLifetimeDays, 365)
+
+	return userservice.APIKeyConfig{
+		DefaultLifetime: time.Duration(min(defaultDays, maxDays)) * 24 * time.Hour,
+		MaxLifetime:     time.Duration(maxDays) * 24 * time.Hour,
+		MaxPerUser:      shared.IntOrDefault(apiKeys.MaxPerUser, 20),
+	}
+}
+
+// avatarConfig trả về cấu hình ảnh đại diện với giá trị mặc định cho các trường không cấu hình
+func (m *Module) avatarConfig() userservice.AvatarConfig {
+	return userservice.AvatarConfig{
+		MaxSize:       shared.IntOrDefault(m.config.Avatar.MaxSize, 5<<20),
+		MaxPixels:     shared.IntOrDefault(m.config.Avatar.MaxPixels, 25_000_000),
+		Size:          shared.IntOrDefault(m.config.Avatar.Size, 512),
+		ThumbnailSize: shared.IntOrDefault(m.config.Avatar.ThumbnailSize, 128),
+	}
+}
+
+// phoneVerificationConfig đọc cấu hình auth.phone_verification, giá trị thiếu hoặc không hợp lệ dùng mặc định
+func (m *Module) phoneVerificationConfig() userservice.PhoneVerificationConfig {
+	phone := m.config.Auth.PhoneVerification
+
+	return userservice.PhoneVerificationConfig{
+		CodeExpIn:       shared.SecondsOrDefault(phone.CodeExpIn, 5*time.Minute),
+		ResendInterval:  shared.DurationOrDefault(phone.ResendInterval, time.Minute),
+		RateLimitWindow: shared.DurationOrDefault(phone.RateLimitWindow, time.Hour),
+		RateLimitMax:    shared.IntOrDefault(phone.RateLimitMax, 5),
+		MaxAttempts:     shared.IntOrDefault(phone.MaxAttempts, 5),
+	}
+}
+
+// loadJwtComp khởi tạo JWT component với key ring, hoặc HS256 bằng JWT_SECRET_KEY khi không cấu hình key dir.
+// Thiếu JWT_SECRET_KEY hoặc secret quá ngắn thì module không khởi động để không ký token bằng secret đoán được.
+func (m *Module) loadJwtComp() error {
+	expIn := m.accessTokenExpIn()
+	if m.keyRing != nil {
+		m.jwtComp = sharecomponent.NewJwtCompWithKeyRing(m.keyRing, expIn)
+		return nil
+	}
+
+	jwtComp, err := sharecomponent.NewJwtComp(os.Getenv("JWT_SECRET_KEY"), expIn)
+	if err != nil {
+		return fmt.Errorf("JWT_SECRET_KEY is required when auth.jwt.key_dir is not set: %v", err)
+	}
+	m.jwtComp = jwtComp
+	return nil
+}
+
+// accessTokenExpIn trả về thời hạn access token (giây) theo auth.access_token_exp_in
+func (m *Module) accessTokenExpIn() int {
+	return shared.IntOrDefault(m.config.Auth.AccessTokenExpIn, 15*60) // Default: 15 minutes
+}
+
+// getJwtComp gắn (một lần) denylist vào JWT component để thu hồi token trước hạn
+func (m *Module) getJwtComp() *sharecomponent.JwtComp {
+	if m.tokenDenylist != nil {
+		return m.jwtComp
+	}
+
+	expIn := m.accessTokenExpIn()
+	dbCtx := sharedinfras.NewDbContext(m.GetDB())
+	m.tokenDenylist = userservice.NewTokenDenylist(userrepository.NewUserRepository(dbCtx), expIn)
+	m.jwtComp.SetDenylist(m.tokenDenylist)
+
+	syncInterval := shared.DurationOrDefault(m.config.Auth.RevocationSyncInterval, 10*time.Second)
+	if m.GetDB() != nil {
+		go m.tokenDenylist.Run(context.Background(), syncInterval)
+	}
+
+	return m.jwtComp
+}
+
+// Initialize khởi tạo và dependency injection cho module
+func (m *Module) Initialize(appCtx sharedinfras.IAppContext) *userhttpgin.UserHTTPController {
 	log.Printf("Initializing user module")
 	dbCtx := appCtx.DbContext()
+	auth := m.config.Auth
 
 	userRepository := userrepository.NewUserRepository(dbCtx)
-	// set jwt private key from env and exp in 7 days
-	jwtComp := sharecomponent.NewJwtComp(os.Getenv("JWT_SECRET_KEY"), 60*60*24*7)
+	jwtComp := m.getJwtComp()
+
+	refreshExpIn := shared.IntOrDefault(auth.RefreshTokenExpIn, 60*60*24*14) // Default: 14 days
+	tokenPairIssuer := userservice.NewTokenPairIssuer(jwtComp, userRepository, refreshExpIn)
+
+	oauthStateTTL := shared.DurationOrDefault(auth.OAuth.StateTTL, 10*time.Minute)
+
+	verificationSender := userservice.NewEmailVerificationSender(
+		m.mailer, m.tokenSigner, auth.EmailVerification.LinkURL, shared.SecondsOrDefault(auth.EmailVerification.TokenExpIn, 24*time.Hour))
+	resendInterval := shared.DurationOrDefault(auth.EmailVerification.ResendInterval, time.Minute)
+
+	passwordResetConfig := userservice.PasswordResetConfig{
+		LinkURL:         auth.PasswordReset.LinkURL,
+		TokenExpIn:      shared.SecondsOrDefault(auth.PasswordReset.TokenExpIn, time.Hour),
+		RateLimitMax:    shared.IntOrDefault(auth.PasswordReset.RateLimitMax, 3),
+		RateLimitWindow: shared.DurationOrDefault(auth.PasswordReset.RateLimitWindow, time.Hour),
+	}
+
+	emailChangeConfig := userservice.EmailChangeConfig{
+		LinkURL:    auth.EmailChange.LinkURL,
+		TokenExpIn: shared.SecondsOrDefault(auth.EmailChange.TokenExpIn, 24*time.Hour),
+	}
+
+	invitationConfig := userservice.OrganizationInvitationConfig{
+		LinkURL:    auth.OrganizationInvitation.LinkURL,
+		TokenExpIn: shared.SecondsOrDefault(auth.OrganizationInvitation.TokenExpIn, 7*24*time.Hour),
+	}
+
+	impersonationConfig := userservice.ImpersonationConfig{
+		TokenExpIn: shared.SecondsOrDefault(auth.Impersonation.TokenExpIn, 10*time.Minute),
+	}
+
+	phoneCountryCode := cmp.Or(auth.PhoneVerification.DefaultCountryCode, usermodel.DefaultPhoneCountryCode)
+	phoneVerificationConfig := m.phoneVerificationConfig()
+
+	// Client không cấu hình secret bị bỏ qua, không có client nào thì endpoint introspect luôn trả về 401
+	introspectionConfig := userservice.IntrospectionConfig{Clients: map[string]string{}}
+	for _, client := range auth.Introspection.Clients {
+		if client.ID != "" && client.Secret != "" {
+			introspectionConfig.Clients[client.ID] = client.Secret
+		}
+	}
 
-	// Command handlers
-	authenticateCmdHdl := userservice.NewAuthenticateCommandHandler(userRepository, jwtComp)
-	createCommandHandler := userservice.NewCreateCommandHandler(userRepository)
-	updateProfileCmdHdl := userservice.NewUpdateProfileCommandHandler(userRepository)
+	passwordHasher := m.passwordHasher()
+	loginThrottle := userservice.NewLoginThrottle(userRepository, m.loginThrottleConfig())
 
-	// Query handlers
-	getProfileQryHdl := userservice.NewGetProfileQueryHandler(userRepository)
+	twoFactorIssuer := cmp.Or(auth.TwoFactor.Issuer, "IKV")
+	recoveryCodeCount := shared.IntOrDefault(auth.TwoFactor.RecoveryCodeCount, 10)
+	loginChallenge := userservice.NewLoginChallenge(m.tokenSigner, shared.SecondsOrDefault(auth.TwoFactor.ChallengeExpIn, 5*time.Minute))
 
-	// listQueryHandler := userservice.NewListQueryHandler(userRepository)
-	// updateCommandHandler := userservice.NewUpdateCommandHandler(userRepository)
-	// deleteCommandHandler := userservice.NewDeleteCommandHandler(userRepository)
 	// categoryRPCClient := rpcclient.NewCategoryRPCClient(appCtx.GetConfig().CategoryServiceURL)
 	// categoryGRPCClient := categorygrpcclient.NewCategoryRPCClient("0.0.0.0:6000")
 
-	userHTTPController := userhttpgin.NewUserHTTPController(
-		createCommandHandler,
-		authenticateCmdHdl,
-		getProfileQryHdl,
-		updateProfileCmdHdl,
-		/* updateCommandHandler, deleteCommandHandler, listQueryHandler */)
-	return userHTTPController
+	return userhttpgin.NewUserHTTPController(userhttpgin.UserHandlers{
+		JwksProvider: jwtComp,
+
+		// Đăng ký, đăng nhập và token
+		CreateCmdHdl:         userservice.NewCreateCommandHandler(userRepository, verificationSender, passwordHasher, m.pwdPolicy, phoneCountryCode),
+		AuthenticateCmdHdl:   userservice.NewAuthenticateCommandHandler(userRepository, tokenPairIssuer, loginThrottle, loginChallenge, passwordHasher),
+		RefreshTokenCmdHdl:   userservice.NewRefreshTokenCommandHandler(userRepository, tokenPairIssuer),
+		LogoutCmdHdl:         userservice.NewLogoutCommandHandler(userRepository, m.tokenDenylist),
+		LogoutAllCmdHdl:      userservice.NewLogoutAllCommandHandler(userRepository, m.tokenDenylist),
+		OauthAuthorizeCmdHdl: userservice.NewOAuthAuthorizeCommandHandler(userRepository, m.oauthProvs, oauthStateTTL),
+		OauthCallbackCmdHdl:  userservice.NewOAuthCallbackCommandHandler(userRepository, m.oauthProvs, tokenPairIssuer, loginChallenge),
+		TwoFactorLoginCmdHdl: userservice.NewTwoFactorLoginCommandHandler(userRepository, loginChallenge, loginThrottle, tokenPairIssuer),
+		IntrospectQryHdl: userservice.NewIntrospectQueryHandler(
+			userservice.NewIntrospectTokenQueryHandler(userRepository, jwtComp),
+			userservice.NewIntrospectAPIKeyQueryHandler(userRepository),
+			introspectionConfig,
+		),
+
+		// Profile, email, mật khẩu và số điện thoại
+		GetProfileQryHdl:         userservice.NewGetProfileQueryHandler(userRepository, appCtx.Uploader()),
+		UpdateProfileCmdHdl:      userservice.NewUpdateProfileCommandHandler(userRepository, phoneCountryCode),
+		VerifyEmailCmdHdl:        userservice.NewVerifyEmailCommandHandler(userRepository, m.tokenSigner),
+		ResendVerificationCmdHdl: userservice.NewResendVerificationCommandHandler(userRepository, verificationSender, resendInterval),
+		ForgotPasswordCmdHdl:     userservice.NewForgotPasswordCommandHandler(userRepository, m.mailer, passwordResetConfig),
+		ResetPasswordCmdHdl:      userservice.NewResetPasswordCommandHandler(userRepository, m.tokenDenylist, passwordHasher, m.pwdPolicy),
+		ChangePasswordCmdHdl:     userservice.NewChangePasswordCommandHandler(userRepository, tokenPairIssuer, loginThrottle, passwordHasher, m.pwdPolicy),
+		ChangeEmailCmdHdl:        userservice.NewChangeEmailCommandHandler(userRepository, m.mailer, loginThrottle, passwordHasher, emailChangeConfig),
+		ConfirmEmailChangeCmdHdl: userservice.NewConfirmEmailChangeCommandHandler(userRepository),
+		UpdateAvatarCmdHdl:       userservice.NewUpdateAvatarCommandHandler(userRepository, appCtx.Uploader(), m.avatarConfig()),
+		DeleteAvatarCmdHdl:       userservice.NewDeleteAvatarCommandHandler(userRepository, appCtx.Uploader()),
+		SendPhoneCodeCmdHdl:      userservice.NewSendPhoneVerificationCommandHandler(userRepository, m.smsSender, phoneVerificationConfig),
+		VerifyPhoneCmdHdl:        userservice.NewVerifyPhoneCommandHandler(userRepository, phoneVerificationConfig),
+
+		// 2FA, API key và phiên đăng nhập
+		EnrollTotpCmdHdl:    userservice.NewEnrollTotpCommandHandler(userRepository, twoFactorIssuer),
+		ConfirmTotpCmdHdl:   userservice.NewConfirmTotpCommandHandler(userRepository, recoveryCodeCount),
+		CreateAPIKeyCmdHdl:  userservice.NewCreateAPIKeyCommandHandler(userRepository, m.apiKeyConfig()),
+		ListAPIKeysQryHdl:   userservice.NewListAPIKeysQueryHandler(userRepository),
+		RevokeAPIKeyCmdHdl:  userservice.NewRevokeAPIKeyCommandHandler(userRepository),
+		ListSessionsQryHdl:  userservice.NewListSessionsQueryHandler(userRepository),
+		RevokeSessionCmdHdl: userservice.NewRevokeSessionCommandHandler(userRepository),
+
+		// Quản trị
+		ListQryHdl:            userservice.NewListQueryHandler(userRepository, appCtx.Uploader()),
+		ChangeRoleCmdHdl:      userservice.NewChangeUserRoleCommandHandler(userRepository),
+		ChangeStatusCmdHdl:    userservice.NewChangeUserStatusCommandHandler(userRepository),
+		UnlockAccountCmdHdl:   userservice.NewUnlockAccountCommandHandler(userRepository, loginThrottle),
+		ExportUserDataCmdHdl:  userservice.NewExportUserDataCommandHandler(userRepository, m.userDataRegistry()),
+		EraseUserDataCmdHdl:   userservice.NewEraseUserDataCommandHandler(userRepository, m.userDataRegistry()),
+		ListAuditEventsQryHdl: userservice.NewListAuditEventsQueryHandler(userRepository),
+		ImpersonateUserCmdHdl: userservice.NewImpersonateUserCommandHandler(userRepository, jwtComp, impersonationConfig),
+
+		// Tổ chức
+		CreateOrganizationCmdHdl: userservice.NewCreateOrganizationCommandHandler(userRepository),
+		ListOrganizationsQryHdl:  userservice.NewListOrganizationsQueryHandler(userRepository),
+		ListOrgMembersQryHdl:     userservice.NewListOrganizationMembersQueryHandler(userRepository),
+		InviteOrgMemberCmdHdl:    userservice.NewInviteOrganizationMemberCommandHandler(userRepository, m.mailer, invitationConfig),
+		AcceptInvitationCmdHdl:   userservice.NewAcceptOrganizationInvitationCommandHandler(userRepository),
+		ChangeMemberRoleCmdHdl:   userservice.NewChangeOrganizationMemberRoleCommandHandler(userRepository),
+		RemoveOrgMemberCmdHdl:    userservice.NewRemoveOrganizationMemberCommandHandler(userRepository),
+		SwitchOrganizationCmdHdl: userservice.NewSwitchOrganizationCommandHandler(userRepository, tokenPairIssuer),
+	})
 }
@@ -0,0 +1,191 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+)
+
+// Các thao tác admin đổi trạng thái user
+const (
+	UserStatusActionBan     = "ban"
+	UserStatusActionUnban   = "unban"
+	UserStatusActionDelete  = "delete"
+	UserStatusActionRestore = "restore"
+)
+
+// IAdminUserRepo interface cho repository operations cần thiết khi admin quản lý user
+type IAdminUserRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	UpdateRole(ctx context.Context, id uuid.UUID, role usermodel.UserRole, updatedBy string, updatedAt time.Time) error
+	UpdateStatus(ctx context.Context, id uuid.UUID, from usermodel.UserStatus, to usermodel.UserStatus, updatedBy string, updatedAt time.Time) (bool, error)
+	RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// ChangeUserRoleCommand đại diện cho command admin đổi role của user
+type ChangeUserRoleCommand struct {
+	UserID    uuid.UUID
+	ActorID   uuid.UUID
+	IPAddress string
+	UserAgent string
+	Dto       usermodel.ChangeRoleForm
+}
+
+// ChangeUserRoleCommandHandler xử lý đổi role. Role được nạp lại từ database ở mỗi request
+// nên thay đổi có hiệu lực ngay, không cần thu hồi token.
+type ChangeUserRoleCommandHandler struct {
+	repo IAdminUserRepo
+}
+
+// NewChangeUserRoleCommandHandler khởi tạo handler mới
+func NewChangeUserRoleCommandHandler(repo IAdminUserRepo) *ChangeUserRoleCommandHandler {
+	return &ChangeUserRoleCommandHandler{repo: repo}
+}
+
+// Execute đổi role, admin không được tự đổi role của chính mình
+func (hdl *ChangeUserRoleCommandHandler) Execute(ctx context.Context, cmd *ChangeUserRoleCommand) error {
+	if cmd.UserID == cmd.ActorID {
+		return datatype.ErrForbidden.WithError(usermodel.ErrCannotManageSelf.Error())
+	}
+
+	user, err := findManagedUser(ctx, hdl.repo, cmd.UserID)
+	if err != nil {
+		return err
+	}
+	if user.Role == cmd.Dto.Role {
+		return nil
+	}
+
+	now := time.Now()
+	if err := hdl.repo.UpdateRole(ctx, user.ID, cmd.Dto.Role, cmd.ActorID.String(), now); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	metadata, _ := json.Marshal(map[string]string{"from": string(user.Role), "to": string(cmd.Dto.Role)})
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &user.ID,
+		ActorID:   &cmd.ActorID,
+		Event:     usermodel.AuditEventRoleChanged,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: now,
+	})
+
+	return nil
+}
+
+// ChangeUserStatusCommand đại diện cho command admin cấm, bỏ cấm, xóa mềm hoặc khôi phục user
+type ChangeUserStatusCommand struct {
+	UserID    uuid.UUID
+	ActorID   uuid.UUID
+	Action    string
+	IPAddress string
+	UserAgent string
+}
+
+// ChangeUserStatusCommandHandler xử lý các thao tác đổi trạng thái user của admin
+type ChangeUserStatusCommandHandler struct {
+	repo IAdminUserRepo
+}
+
+// NewChangeUserStatusCommandHandler khởi tạo handler mới
+func NewChangeUserStatusCommandHandler(repo IAdminUserRepo) *ChangeUserStatusCommandHandler {
+	return &ChangeUserStatusCommandHandler{repo: repo}
+}
+
+// Execute chuyển trạng thái theo Action. User bị cấm hoặc xóa mất mọi refresh token;
+// access token còn hạn bị middleware từ chối vì trạng thái user được kiểm tra ở mỗi request.
+func (hdl *ChangeUserStatusCommandHandler) Execute(ctx context.Context, cmd *ChangeUserStatusCommand) error {
+	if cmd.UserID == cmd.ActorID {
+		return datatype.ErrForbidden.WithError(usermodel.ErrCannotManageSelf.Error())
+	}
+
+	user, err := findManagedUser(ctx, hdl.repo, cmd.UserID)
+	if err != nil {
+		return err
+	}
+
+	var to usermodel.UserStatus
+	var event string
+	allowed := false
+
+	switch cmd.Action {
+	case UserStatusActionBan:
+		to, event = usermodel.StatusBanned, usermodel.AuditEventUserBanned
+		allowed = user.Status != usermodel.StatusBanned && user.Status != usermodel.StatusDeleted
+	case UserStatusActionUnban:
+		to, event = reactivatedStatus(user), usermodel.AuditEventUserUnbanned
+		allowed = user.Status == usermodel.StatusBanned
+	case UserStatusActionDelete:
+		to, event = usermodel.StatusDeleted, usermodel.AuditEventUserDeleted
+		allowed = user.Status != usermodel.StatusDeleted
+	case UserStatusActionRestore:
+		to, event = reactivatedStatus(user), usermodel.AuditEventUserRestored
+		// User đã bị xóa dữ liệu cá nhân không thể khôi phục
+		allowed = user.Status == usermodel.StatusDeleted && !user.IsErased()
+	default:
+		return datatype.ErrBadRequest.WithErrorf("unknown user status action %q", cmd.Action)
+	}
+
+	if !allowed {
+		return datatype.ErrConflict.WithError(usermodel.ErrUserStatusTransition.Error())
+	}
+
+	now := time.Now()
+	updated, err := hdl.repo.UpdateStatus(ctx, user.ID, user.Status, to, cmd.ActorID.String(), now)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if !updated {
+		return datatype.ErrConflict.WithError(usermodel.ErrUserStatusTransition.Error())
+	}
+
+	if to == usermodel.StatusBanned || to == usermodel.StatusDeleted {
+		if err := hdl.repo.RevokeUserRefreshTokens(ctx, user.ID); err != nil {
+			return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+		}
+	}
+
+	metadata, _ := json.Marshal(map[string]string{"from": string(user.Status), "to": string(to)})
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &user.ID,
+		ActorID:   &cmd.ActorID,
+		Event:     event,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: now,
+	})
+
+	return nil
+}
+
+// findManagedUser tìm user mà admin đang thao tác, trả về 404 nếu không tồn tại
+func findManagedUser(ctx context.Context, repo IAdminUserRepo, id uuid.UUID) (*usermodel.User, error) {
+	user, err := repo.FindById(ctx, id)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, datatype.ErrNotFound.WithError(usermodel.ErrUserNotFound.Error())
+		}
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	return user, nil
+}
+
+// reactivatedStatus là trạng thái của user sau khi bỏ cấm hoặc khôi phục:
+// user chưa xác minh email quay về pending
+func reactivatedStatus(user *usermodel.User) usermodel.UserStatus {
+	if user.EmailVerifiedAt == nil {
+		return usermodel.StatusPending
+	}
+	return usermodel.StatusActive
+}
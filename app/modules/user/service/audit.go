@@ -0,0 +1,109 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+)
+
+// maxAuditUserAgentLength là độ dài cột user_agent, user agent dài hơn bị cắt để sự kiện vẫn được ghi
+const maxAuditUserAgentLength = 255
+
+// IAuditEventRepo interface lưu sự kiện audit
+type IAuditEventRepo interface {
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// recordAuditEvent lưu sự kiện audit sau khi thao tác chính đã thành công nên lỗi chỉ được log lại.
+// Request ID được lấy từ context nếu sự kiện chưa có. Thao tác bằng token impersonation luôn ghi
+// admin đang impersonate vào ActorID.
+func recordAuditEvent(ctx context.Context, repo IAuditEventRepo, event *usermodel.AuditEvent) {
+	if event.ID == uuid.Nil {
+		event.ID, _ = uuid.NewV7()
+	}
+	if requester := datatype.GetRequester(ctx); datatype.IsImpersonated(requester) {
+		impersonatorID := requester.ImpersonatorID()
+		event.ActorID = &impersonatorID
+	}
+	if event.RequestID == "" {
+		event.RequestID = datatype.GetRequestID(ctx)
+	}
+	event.UserAgent = truncateRunes(event.UserAgent, maxAuditUserAgentLength)
+
+	if err := repo.InsertAuditEvent(ctx, event); err != nil {
+		log.Printf("Error recording audit event %s: %v", event.Event, err)
+	}
+}
+
+// actorFromContext trả về người thực sự thao tác để ghi created_by/updated_by:
+// admin nếu request dùng token impersonation, ngược lại là userID
+func actorFromContext(ctx context.Context, userID uuid.UUID) uuid.UUID {
+	if requester := datatype.GetRequester(ctx); datatype.IsImpersonated(requester) {
+		return requester.ImpersonatorID()
+	}
+	return userID
+}
+
+// loginAttempt mô tả một lần đăng nhập để ghi sự kiện audit: phương thức (password, two_factor, oauth),
+// provider (chỉ với oauth), thiết bị của client và thời điểm đăng nhập
+type loginAttempt struct {
+	Method   string
+	Provider string
+	Client   SessionClient
+	Now      time.Time
+}
+
+// metadata trả về metadata của sự kiện đăng nhập, fields được ghi thêm
+func (a loginAttempt) metadata(fields map[string]string) string {
+	if fields == nil {
+		fields = map[string]string{}
+	}
+	fields["method"] = a.Method
+	if a.Provider != "" {
+		fields["provider"] = a.Provider
+	}
+
+	metadata, _ := json.Marshal(fields)
+	return string(metadata)
+}
+
+// recordLoginSucceeded ghi sự kiện đăng nhập thành công
+func recordLoginSucceeded(ctx context.Context, repo IAuditEventRepo, user *usermodel.User, attempt loginAttempt) {
+	recordAuditEvent(ctx, repo, &usermodel.AuditEvent{
+		UserID:    &user.ID,
+		ActorID:   &user.ID,
+		Event:     usermodel.AuditEventLoginSucceeded,
+		IPAddress: attempt.Client.IPAddress,
+		UserAgent: attempt.Client.UserAgent,
+		Metadata:  attempt.metadata(nil),
+		CreatedAt: attempt.Now,
+	})
+}
+
+// recordLoginFailed ghi sự kiện đăng nhập thất bại kèm lý do.
+// Khi chưa xác định được user (email không tồn tại, bị chặn trước khi tra cứu) email đã nhập được lưu trong metadata.
+func recordLoginFailed(ctx context.Context, repo IAuditEventRepo, user *usermodel.User, email string, reason string, attempt loginAttempt) {
+	fields := map[string]string{"reason": reason}
+
+	var userID *uuid.UUID
+	if user != nil {
+		userID = &user.ID
+	} else if email != "" {
+		fields["email"] = truncateRunes(normalizeLoginEmail(email), 100)
+	}
+
+	recordAuditEvent(ctx, repo, &usermodel.AuditEvent{
+		UserID:    userID,
+		Event:     usermodel.AuditEventLoginFailed,
+		IPAddress: attempt.Client.IPAddress,
+		UserAgent: attempt.Client.UserAgent,
+		Metadata:  attempt.metadata(fields),
+		CreatedAt: attempt.Now,
+	})
+}
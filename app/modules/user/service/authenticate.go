@@ -3,59 +3,176 @@ package userservice
 import (
 	"context"
 	"errors"
+	"log"
+	"sync"
+	"time"
 
 	usermodel "fat2fast/ikv/modules/user/model"
-	"fat2fast/ikv/shared"
+	sharecomponent "fat2fast/ikv/shared/component"
 	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
 )
 
 type AuthenticateCommand struct {
-	Dto usermodel.LoginForm
+	Dto       usermodel.LoginForm
+	IPAddress string
+	UserAgent string
 }
 
 type IAuthenticateRepo interface {
 	FindByEmail(ctx context.Context, email string) (*usermodel.User, error)
+	RehashPassword(ctx context.Context, userID uuid.UUID, oldHash string, newHash string) error
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// IPasswordHasher interface băm và kiểm tra password, needsRehash báo giá trị băm đã lưu cần nâng cấp
+type IPasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password string, legacySalt string, encoded string) (ok bool, needsRehash bool, err error)
 }
 type ITokenIssuer interface {
-	IssueToken(ctx context.Context, userID string) (string, error)
+	IssueTokenWithClaims(ctx context.Context, claims sharecomponent.TokenClaims) (string, error)
 	ExpIn() int
 }
 
 type AuthenticateCommandHandler struct {
-	repo        IAuthenticateRepo
-	tokenIssuer ITokenIssuer
+	repo            IAuthenticateRepo
+	tokenPairIssuer ITokenPairIssuer
+	loginThrottle   ILoginThrottle
+	loginChallenge  ILoginChallenge
+	passwordHasher  IPasswordHasher
 }
+
+// AuthenticateResult là kết quả đăng nhập. Với user đã bật 2FA, bước password chỉ trả về
+// MfaRequired và ChallengeToken để đổi lấy token thật qua /authenticate/2fa.
 type AuthenticateResult struct {
-	Token string `json:"token"`
-	ExpIn int    `json:"expIn"`
+	Token          string `json:"token,omitempty"`
+	ExpIn          int    `json:"expIn,omitempty"`
+	RefreshToken   string `json:"refreshToken,omitempty"`
+	RefreshExpIn   int    `json:"refreshExpIn,omitempty"`
+	MfaRequired    bool   `json:"mfaRequired,omitempty"`
+	ChallengeToken string `json:"challengeToken,omitempty"`
+	ChallengeExpIn int    `json:"challengeExpIn,omitempty"`
 }
 
-func NewAuthenticateCommandHandler(repo IAuthenticateRepo, tokenIssuer ITokenIssuer) *AuthenticateCommandHandler {
-	return &AuthenticateCommandHandler{repo: repo, tokenIssuer: tokenIssuer}
+// dummyPasswordHash dùng để so khớp password khi email không tồn tại,
+// giữ thời gian phản hồi tương đương với trường hợp sai password
+var (
+	dummyPasswordHash     string
+	dummyPasswordHashOnce sync.Once
+)
+
+func NewAuthenticateCommandHandler(repo IAuthenticateRepo, tokenPairIssuer ITokenPairIssuer, loginThrottle ILoginThrottle, loginChallenge ILoginChallenge, passwordHasher IPasswordHasher) *AuthenticateCommandHandler {
+	return &AuthenticateCommandHandler{
+		repo:            repo,
+		tokenPairIssuer: tokenPairIssuer,
+		loginThrottle:   loginThrottle,
+		loginChallenge:  loginChallenge,
+		passwordHasher:  passwordHasher,
+	}
 }
 func (hdl *AuthenticateCommandHandler) Execute(ctx context.Context, cmd *AuthenticateCommand) (*AuthenticateResult, error) {
+	now := time.Now()
+	client := SessionClient{IPAddress: cmd.IPAddress, UserAgent: cmd.UserAgent}
+	attempt := loginAttempt{Method: usermodel.LoginMethodPassword, Client: client, Now: now}
+	if err := hdl.loginThrottle.Check(ctx, cmd.Dto.Username, cmd.IPAddress, now); err != nil {
+		if isLoginThrottled(err) {
+			recordLoginFailed(ctx, hdl.repo, nil, cmd.Dto.Username, usermodel.LoginFailureReasonThrottled, attempt)
+		}
+		return nil, err
+	}
 
 	user, err := hdl.repo.FindByEmail(ctx, cmd.Dto.Username)
 	if err != nil {
 		if errors.Is(err, datatype.ErrRecordNotFound) {
-			return nil, datatype.ErrBadRequest.WithError(usermodel.ErrInvalidEmailAndPassword.Error())
+			dummyPasswordHashOnce.Do(func() {
+				dummyPasswordHash, _ = hdl.passwordHasher.Hash("")
+			})
+			_, _, _ = hdl.passwordHasher.Verify(cmd.Dto.Password, "", dummyPasswordHash)
+			return nil, hdl.loginFailed(ctx, nil, cmd, attempt)
 		}
 
 		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
 	}
 
+	ok, needsRehash, err := hdl.passwordHasher.Verify(cmd.Dto.Password, user.Salt, user.Password)
+	// User chỉ đăng nhập bằng social login không có password
+	if err != nil && user.Password != "" {
+		log.Printf("Error verifying password of user %s: %v", user.ID, err)
+	}
+	if !ok {
+		return nil, hdl.loginFailed(ctx, user, cmd, attempt)
+	}
+	// Giá trị băm cũ (bcrypt hoặc tham số argon2id lỗi thời) được băm lại khi biết password đúng
+	if needsRehash {
+		hdl.rehashPassword(ctx, user, cmd.Dto.Password)
+	}
+	// Chỉ báo trạng thái tài khoản sau khi password đúng để không lộ email nào đã đăng ký
 	if user.Status == usermodel.StatusDeleted || user.Status == usermodel.StatusBanned {
+		recordLoginFailed(ctx, hdl.repo, user, "", usermodel.LoginFailureReasonBannedOrDeleted, attempt)
 		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrUserBannedOrDeleted.Error())
 	}
-	// Kiểm tra password bằng bcrypt.CompareHashAndPassword
-	err = shared.VerifyPassword(cmd.Dto.Password, user.Salt, user.Password)
+	if user.Status == usermodel.StatusPending {
+		recordLoginFailed(ctx, hdl.repo, user, "", usermodel.LoginFailureReasonEmailNotVerified, attempt)
+		return nil, datatype.ErrForbidden.WithError(usermodel.ErrEmailNotVerified.Error())
+	}
+
+	// Bộ đếm sai chỉ được xóa sau khi qua bước 2FA để không thể dò mã TOTP bằng cách nhập lại password
+	if user.IsTwoFactorEnabled() {
+		return hdl.loginChallenge.Issue(user), nil
+	}
+
+	if err := hdl.loginThrottle.RecordSuccess(ctx, cmd.Dto.Username); err != nil {
+		log.Printf("Error clearing login failures for user %s: %v", user.ID, err)
+	}
+
+	// Mỗi lần đăng nhập bắt đầu một refresh token family mới
+	result, err := hdl.tokenPairIssuer.StartSession(ctx, user, client)
 	if err != nil {
-		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrInvalidEmailAndPassword.Error())
+		return nil, err
 	}
-	token, err := hdl.tokenIssuer.IssueToken(ctx, user.ID.String())
+
+	recordLoginSucceeded(ctx, hdl.repo, user, attempt)
+
+	return result, nil
+}
+
+// rehashPassword nâng cấp giá trị băm của password, lỗi chỉ được log vì không ảnh hưởng tới việc đăng nhập
+func (hdl *AuthenticateCommandHandler) rehashPassword(ctx context.Context, user *usermodel.User, password string) {
+	newHash, err := hdl.passwordHasher.Hash(password)
 	if err != nil {
-		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+		log.Printf("Error rehashing password of user %s: %v", user.ID, err)
+		return
+	}
+
+	if err := hdl.repo.RehashPassword(ctx, user.ID, user.Password, newHash); err != nil {
+		log.Printf("Error rehashing password of user %s: %v", user.ID, err)
+	}
+}
+
+// loginFailed ghi nhận lần đăng nhập sai và trả về cùng một lỗi cho email không tồn tại và sai password
+func (hdl *AuthenticateCommandHandler) loginFailed(ctx context.Context, user *usermodel.User, cmd *AuthenticateCommand, attempt loginAttempt) error {
+	reason := usermodel.LoginFailureReasonInvalidPassword
+	if user == nil {
+		reason = usermodel.LoginFailureReasonUnknownEmail
+	}
+	recordLoginFailed(ctx, hdl.repo, user, cmd.Dto.Username, reason, attempt)
+
+	locked, err := hdl.loginThrottle.RecordFailure(ctx, cmd.Dto.Username, cmd.IPAddress, attempt.Now)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if locked && user != nil {
+		recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+			UserID:    &user.ID,
+			Event:     usermodel.AuditEventAccountLocked,
+			IPAddress: cmd.IPAddress,
+			UserAgent: cmd.UserAgent,
+			CreatedAt: attempt.Now,
+		})
 	}
-	return &AuthenticateResult{Token: token, ExpIn: hdl.tokenIssuer.ExpIn()}, nil
 
+	return datatype.ErrBadRequest.WithError(usermodel.ErrInvalidEmailAndPassword.Error())
 }
@@ -0,0 +1,207 @@
+package userservice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	sharecomponent "fat2fast/ikv/shared/component"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+)
+
+// avatarJPEGQuality là chất lượng JPEG của ảnh đại diện sau khi xử lý
+const avatarJPEGQuality = 85
+
+// avatarContentTypes là các định dạng ảnh đại diện được chấp nhận, xác định từ nội dung file thay vì header của client
+var avatarContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// IFileURLResolver interface tạo URL công khai từ key của file
+type IFileURLResolver interface {
+	URL(key string) string
+}
+
+// IFileStorage interface lưu trữ file (xem sharecomponent.IUploader)
+type IFileStorage interface {
+	IFileURLResolver
+	Upload(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Delete(ctx context.Context, key string) error
+}
+
+// AvatarConfig là cấu hình ảnh đại diện: dung lượng file tối đa (byte), số điểm ảnh tối đa của ảnh gốc,
+// cạnh của ảnh đại diện và thumbnail (ảnh vuông, tính bằng pixel)
+type AvatarConfig struct {
+	MaxSize       int64
+	MaxPixels     int64
+	Size          int
+	ThumbnailSize int
+}
+
+// UpdateAvatarCommand đại diện cho command user tải lên ảnh đại diện mới
+type UpdateAvatarCommand struct {
+	UserID uuid.UUID
+	File   io.Reader
+}
+
+// IAvatarRepo interface cho repository operations cần thiết
+type IAvatarRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	UpdateAvatar(ctx context.Context, userID uuid.UUID, avatarKey *string, thumbnailKey *string, updatedBy string, updatedAt time.Time) error
+}
+
+// UpdateAvatarCommandHandler kiểm tra, cắt vuông và thu nhỏ ảnh rồi lưu ảnh đại diện cùng thumbnail
+type UpdateAvatarCommandHandler struct {
+	repo    IAvatarRepo
+	storage IFileStorage
+	config  AvatarConfig
+}
+
+// NewUpdateAvatarCommandHandler khởi tạo handler mới
+func NewUpdateAvatarCommandHandler(repo IAvatarRepo, storage IFileStorage, config AvatarConfig) *UpdateAvatarCommandHandler {
+	return &UpdateAvatarCommandHandler{repo: repo, storage: storage, config: config}
+}
+
+// MaxFileSize trả về dung lượng file tối đa (byte) để controller giới hạn body của request
+func (hdl *UpdateAvatarCommandHandler) MaxFileSize() int64 {
+	return hdl.config.MaxSize
+}
+
+// Execute đọc tối đa MaxSize byte, nhận diện định dạng từ nội dung file và mã hóa lại ảnh thành JPEG
+// (loại bỏ metadata và nội dung lạ nhúng trong file gốc). Ảnh đại diện cũ bị xóa sau khi lưu ảnh mới.
+func (hdl *UpdateAvatarCommandHandler) Execute(ctx context.Context, cmd *UpdateAvatarCommand) (*usermodel.AvatarResponse, error) {
+	data, err := io.ReadAll(io.LimitReader(cmd.File, hdl.config.MaxSize+1))
+	if err != nil {
+		// Body của request bị cắt bởi giới hạn của controller trước khi đọc hết file
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, datatype.ErrRequestEntityTooLarge.WithError(usermodel.ErrAvatarTooLarge.Error())
+		}
+		return nil, datatype.ErrBadRequest.WithWrap(err).WithDebug(err.Error())
+	}
+	if int64(len(data)) > hdl.config.MaxSize {
+		return nil, datatype.ErrRequestEntityTooLarge.WithError(usermodel.ErrAvatarTooLarge.Error())
+	}
+	if len(data) == 0 {
+		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrAvatarFileRequired.Error())
+	}
+
+	if !avatarContentTypes[http.DetectContentType(data)] {
+		return nil, datatype.ErrUnsupportedMediaType.WithError(usermodel.ErrAvatarUnsupportedType.Error())
+	}
+
+	img, err := sharecomponent.DecodeImage(data, hdl.config.MaxPixels)
+	if err != nil {
+		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrAvatarInvalidImage.Error()).WithDebug(err.Error())
+	}
+
+	avatar, err := sharecomponent.EncodeJPEG(sharecomponent.SquareThumbnail(img, hdl.config.Size), avatarJPEGQuality)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	thumbnail, err := sharecomponent.EncodeJPEG(sharecomponent.SquareThumbnail(img, hdl.config.ThumbnailSize), avatarJPEGQuality)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	user, err := hdl.repo.FindById(ctx, cmd.UserID)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	// Mỗi lần tải lên dùng key mới để CDN/trình duyệt không trả về ảnh cũ đã cache
+	version, _ := uuid.NewV7()
+	avatarKey := fmt.Sprintf("avatars/%s/%s.jpg", user.ID, version)
+	thumbnailKey := fmt.Sprintf("avatars/%s/%s_thumb.jpg", user.ID, version)
+
+	if err := hdl.storage.Upload(ctx, avatarKey, bytes.NewReader(avatar), int64(len(avatar)), "image/jpeg"); err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if err := hdl.storage.Upload(ctx, thumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), "image/jpeg"); err != nil {
+		deleteAvatarFiles(ctx, hdl.storage, &avatarKey, nil)
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if err := hdl.repo.UpdateAvatar(ctx, user.ID, &avatarKey, &thumbnailKey, actorFromContext(ctx, user.ID).String(), time.Now()); err != nil {
+		deleteAvatarFiles(ctx, hdl.storage, &avatarKey, &thumbnailKey)
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	deleteAvatarFiles(ctx, hdl.storage, user.AvatarKey, user.AvatarThumbnailKey)
+
+	return &usermodel.AvatarResponse{
+		AvatarURL:          hdl.storage.URL(avatarKey),
+		AvatarThumbnailURL: hdl.storage.URL(thumbnailKey),
+	}, nil
+}
+
+// DeleteAvatarCommand đại diện cho command user xóa ảnh đại diện
+type DeleteAvatarCommand struct {
+	UserID uuid.UUID
+}
+
+// DeleteAvatarCommandHandler xóa ảnh đại diện của user
+type DeleteAvatarCommandHandler struct {
+	repo    IAvatarRepo
+	storage IFileStorage
+}
+
+// NewDeleteAvatarCommandHandler khởi tạo handler mới
+func NewDeleteAvatarCommandHandler(repo IAvatarRepo, storage IFileStorage) *DeleteAvatarCommandHandler {
+	return &DeleteAvatarCommandHandler{repo: repo, storage: storage}
+}
+
+// Execute xóa ảnh đại diện, user chưa có ảnh đại diện không gây lỗi
+func (hdl *DeleteAvatarCommandHandler) Execute(ctx context.Context, cmd *DeleteAvatarCommand) error {
+	user, err := hdl.repo.FindById(ctx, cmd.UserID)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if user.AvatarKey == nil && user.AvatarThumbnailKey == nil {
+		return nil
+	}
+
+	if err := hdl.repo.UpdateAvatar(ctx, user.ID, nil, nil, actorFromContext(ctx, user.ID).String(), time.Now()); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	deleteAvatarFiles(ctx, hdl.storage, user.AvatarKey, user.AvatarThumbnailKey)
+
+	return nil
+}
+
+// deleteAvatarFiles xóa file ảnh sau khi dữ liệu đã được cập nhật nên lỗi chỉ được log lại
+func deleteAvatarFiles(ctx context.Context, storage IFileStorage, keys ...*string) {
+	for _, key := range keys {
+		if key == nil {
+			continue
+		}
+		if err := storage.Delete(ctx, *key); err != nil {
+			log.Printf("Error deleting avatar file %s: %v", *key, err)
+		}
+	}
+}
+
+// toProfileResponse chuyển user sang ProfileResponse kèm URL ảnh đại diện
+func toProfileResponse(user *usermodel.User, files IFileURLResolver) *usermodel.ProfileResponse {
+	profile := user.ToProfileResponse()
+
+	if user.AvatarKey != nil {
+		profile.AvatarURL = files.URL(*user.AvatarKey)
+	}
+	if user.AvatarThumbnailKey != nil {
+		profile.AvatarThumbnailURL = files.URL(*user.AvatarThumbnailKey)
+	}
+
+	return profile
+}
@@ -0,0 +1,141 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared"
+	sharecomponent "fat2fast/ikv/shared/component"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// ChangeEmailCommand đại diện cho command user yêu cầu đổi email đăng nhập
+type ChangeEmailCommand struct {
+	UserID    uuid.UUID
+	IPAddress string
+	UserAgent string
+	Dto       usermodel.ChangeEmailForm
+}
+
+// IChangeEmailRepo interface cho repository operations cần thiết
+type IChangeEmailRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	FindByEmail(ctx context.Context, email string) (*usermodel.User, error)
+	ReplaceEmailChangeRequest(ctx context.Context, request *usermodel.EmailChangeRequest) error
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// EmailChangeConfig là cấu hình luồng đổi email
+type EmailChangeConfig struct {
+	LinkURL    string
+	TokenExpIn time.Duration
+}
+
+// ChangeEmailCommandHandler lưu email mới ở trạng thái chờ và gửi link xác nhận tới email mới
+type ChangeEmailCommandHandler struct {
+	repo            IChangeEmailRepo
+	mailer          IMailer
+	currentPassword currentPasswordChecker
+	config          EmailChangeConfig
+}
+
+// NewChangeEmailCommandHandler khởi tạo handler mới
+func NewChangeEmailCommandHandler(repo IChangeEmailRepo, mailer IMailer, loginThrottle ILoginThrottle, passwordHasher IPasswordHasher, config EmailChangeConfig) *ChangeEmailCommandHandler {
+	return &ChangeEmailCommandHandler{
+		repo:            repo,
+		mailer:          mailer,
+		currentPassword: currentPasswordChecker{repo: repo, loginThrottle: loginThrottle, passwordHasher: passwordHasher},
+		config:          config,
+	}
+}
+
+// Execute kiểm tra mật khẩu hiện tại rồi gửi thông báo tới email cũ và link xác nhận tới email mới.
+// Email của tài khoản chỉ thay đổi khi link được mở (xem ConfirmEmailChangeCommandHandler).
+func (hdl *ChangeEmailCommandHandler) Execute(ctx context.Context, cmd *ChangeEmailCommand) error {
+	user, err := hdl.repo.FindById(ctx, cmd.UserID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return datatype.ErrNotFound.WithError(usermodel.ErrUserNotFound.Error())
+		}
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	client := SessionClient{IPAddress: cmd.IPAddress, UserAgent: cmd.UserAgent}
+	if err := hdl.currentPassword.Verify(ctx, user, cmd.Dto.CurrentPassword, usermodel.PasswordConfirmActionChangeEmail, client); err != nil {
+		return err
+	}
+
+	newEmail := strings.TrimSpace(cmd.Dto.NewEmail)
+	if strings.EqualFold(newEmail, user.Email) {
+		return datatype.ErrBadRequest.WithError(usermodel.ErrNewEmailSameAsOld.Error())
+	}
+
+	// Kiểm tra sớm để báo lỗi ngay; email vẫn có thể bị tài khoản khác lấy trước khi xác nhận
+	if _, err := hdl.repo.FindByEmail(ctx, newEmail); err == nil {
+		return datatype.ErrConflict.WithError(usermodel.ErrEmailAlreadyUsed.Error())
+	} else if !errors.Is(err, datatype.ErrRecordNotFound) {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	token, err := shared.RandomStr(32)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	now := time.Now()
+	newId, _ := uuid.NewV7()
+	request := &usermodel.EmailChangeRequest{
+		ID:        newId,
+		UserID:    user.ID,
+		NewEmail:  newEmail,
+		TokenHash: shared.HashToken(token),
+		ExpiresAt: now.Add(hdl.config.TokenExpIn),
+		CreatedAt: now,
+	}
+	if err := hdl.repo.ReplaceEmailChangeRequest(ctx, request); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	// Gửi thông báo tới email cũ trước: nếu không gửi được thì link xác nhận cũng không được gửi đi
+	notice := &sharecomponent.Mail{
+		To:      user.Email,
+		Subject: "Yêu cầu đổi email đăng nhập",
+		Body: fmt.Sprintf("Xin chào %s,\n\nChúng tôi nhận được yêu cầu đổi email đăng nhập của tài khoản sang %s.\nEmail chỉ được đổi sau khi link xác nhận gửi tới địa chỉ mới được mở.\nNếu bạn không thực hiện yêu cầu này, hãy đổi mật khẩu ngay.\n",
+			user.GetFullName(), newEmail),
+	}
+	if err := hdl.mailer.Send(ctx, notice); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	link := fmt.Sprintf("%s?token=%s", hdl.config.LinkURL, url.QueryEscape(token))
+	confirmation := &sharecomponent.Mail{
+		To:      newEmail,
+		Subject: "Xác nhận email đăng nhập mới",
+		Body: fmt.Sprintf("Xin chào %s,\n\nVui lòng mở link sau để dùng địa chỉ này làm email đăng nhập:\n%s\n\nLink chỉ dùng được một lần và có hiệu lực trong %s.\nNếu bạn không yêu cầu, hãy bỏ qua email này.\n",
+			user.GetFullName(), link, hdl.config.TokenExpIn),
+	}
+	if err := hdl.mailer.Send(ctx, confirmation); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	metadata, _ := json.Marshal(map[string]string{"new_email": newEmail})
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &user.ID,
+		ActorID:   &cmd.UserID,
+		Event:     usermodel.AuditEventEmailChangeRequested,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: now,
+	})
+
+	return nil
+}
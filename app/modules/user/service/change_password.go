@@ -0,0 +1,100 @@
+package userservice
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+)
+
+// ChangePasswordCommand đại diện cho command user tự đổi mật khẩu
+type ChangePasswordCommand struct {
+	UserID    uuid.UUID
+	IPAddress string
+	UserAgent string
+	Dto       usermodel.ChangePasswordForm
+}
+
+// IChangePasswordRepo interface cho repository operations cần thiết
+type IChangePasswordRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	UpdatePassword(ctx context.Context, userID uuid.UUID, hashedPassword string, updatedAt time.Time) error
+	RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// ChangePasswordCommandHandler xử lý đổi mật khẩu khi đã đăng nhập
+type ChangePasswordCommandHandler struct {
+	repo            IChangePasswordRepo
+	tokenPairIssuer ITokenPairIssuer
+	passwordHasher  IPasswordHasher
+	passwordPolicy  IPasswordPolicy
+	currentPassword currentPasswordChecker
+}
+
+// NewChangePasswordCommandHandler khởi tạo handler mới
+func NewChangePasswordCommandHandler(repo IChangePasswordRepo, tokenPairIssuer ITokenPairIssuer, loginThrottle ILoginThrottle, passwordHasher IPasswordHasher, passwordPolicy IPasswordPolicy) *ChangePasswordCommandHandler {
+	return &ChangePasswordCommandHandler{
+		repo:            repo,
+		tokenPairIssuer: tokenPairIssuer,
+		passwordHasher:  passwordHasher,
+		passwordPolicy:  passwordPolicy,
+		currentPassword: currentPasswordChecker{repo: repo, loginThrottle: loginThrottle, passwordHasher: passwordHasher},
+	}
+}
+
+// Execute kiểm tra mật khẩu hiện tại và đặt mật khẩu mới.
+// Credential version tăng nên mọi access token cũ bị từ chối, refresh token cũ bị thu hồi;
+// phiên hiện tại nhận cặp token mới để không bị đăng xuất.
+func (hdl *ChangePasswordCommandHandler) Execute(ctx context.Context, cmd *ChangePasswordCommand) (*AuthenticateResult, error) {
+	user, err := hdl.repo.FindById(ctx, cmd.UserID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, datatype.ErrNotFound.WithError(usermodel.ErrUserNotFound.Error())
+		}
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	client := SessionClient{IPAddress: cmd.IPAddress, UserAgent: cmd.UserAgent}
+	if err := hdl.currentPassword.Verify(ctx, user, cmd.Dto.CurrentPassword, usermodel.PasswordConfirmActionChangePassword, client); err != nil {
+		return nil, err
+	}
+
+	if cmd.Dto.NewPassword == cmd.Dto.CurrentPassword {
+		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrNewPasswordSameAsOld.Error())
+	}
+
+	if err := validateNewPassword(hdl.passwordPolicy, "new_password", cmd.Dto.NewPassword, user.Email, user.FirstName, user.LastName); err != nil {
+		return nil, err
+	}
+
+	hashPassword, err := hdl.passwordHasher.Hash(cmd.Dto.NewPassword)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	now := time.Now()
+	if err := hdl.repo.UpdatePassword(ctx, user.ID, hashPassword, now); err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	user.CredentialVersion++
+
+	if err := hdl.repo.RevokeUserRefreshTokens(ctx, user.ID); err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &user.ID,
+		ActorID:   &cmd.UserID,
+		Event:     usermodel.AuditEventPasswordChanged,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		CreatedAt: now,
+	})
+
+	return hdl.tokenPairIssuer.StartSession(ctx, user, client)
+}
@@ -0,0 +1,88 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// ConfirmEmailChangeCommand đại diện cho command xác nhận đổi email bằng token gửi tới email mới
+type ConfirmEmailChangeCommand struct {
+	IPAddress string
+	UserAgent string
+	Dto       usermodel.ConfirmEmailChangeForm
+}
+
+// IConfirmEmailChangeRepo interface cho repository operations cần thiết
+type IConfirmEmailChangeRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	FindEmailChangeRequestByHash(ctx context.Context, tokenHash string) (*usermodel.EmailChangeRequest, error)
+	ConfirmEmailChange(ctx context.Context, request *usermodel.EmailChangeRequest, now time.Time) (bool, error)
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// ConfirmEmailChangeCommandHandler đổi email của user khi token xác nhận hợp lệ
+type ConfirmEmailChangeCommandHandler struct {
+	repo IConfirmEmailChangeRepo
+}
+
+// NewConfirmEmailChangeCommandHandler khởi tạo handler mới
+func NewConfirmEmailChangeCommandHandler(repo IConfirmEmailChangeRepo) *ConfirmEmailChangeCommandHandler {
+	return &ConfirmEmailChangeCommandHandler{repo: repo}
+}
+
+// Execute kiểm tra token và đổi email. Nếu email mới đã được tài khoản khác đăng ký trong lúc chờ xác nhận
+// thì trả về lỗi conflict và token vẫn chưa bị dùng.
+func (hdl *ConfirmEmailChangeCommandHandler) Execute(ctx context.Context, cmd *ConfirmEmailChangeCommand) error {
+	now := time.Now()
+
+	request, err := hdl.repo.FindEmailChangeRequestByHash(ctx, shared.HashToken(cmd.Dto.Token))
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return datatype.ErrBadRequest.WithError(usermodel.ErrInvalidEmailChangeToken.Error())
+		}
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if request.ConfirmedAt != nil || !now.Before(request.ExpiresAt) {
+		return datatype.ErrBadRequest.WithError(usermodel.ErrInvalidEmailChangeToken.Error())
+	}
+
+	user, err := hdl.repo.FindById(ctx, request.UserID)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if user.Status == usermodel.StatusBanned || user.Status == usermodel.StatusDeleted {
+		return datatype.ErrBadRequest.WithError(usermodel.ErrUserBannedOrDeleted.Error())
+	}
+
+	confirmed, err := hdl.repo.ConfirmEmailChange(ctx, request, now)
+	if err != nil {
+		if errors.Is(err, usermodel.ErrEmailAlreadyUsed) {
+			return datatype.ErrConflict.WithError(usermodel.ErrEmailAlreadyUsed.Error())
+		}
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if !confirmed {
+		return datatype.ErrBadRequest.WithError(usermodel.ErrInvalidEmailChangeToken.Error())
+	}
+
+	metadata, _ := json.Marshal(map[string]string{"from": user.Email, "to": request.NewEmail})
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &user.ID,
+		ActorID:   &user.ID,
+		Event:     usermodel.AuditEventEmailChanged,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: now,
+	})
+
+	return nil
+}
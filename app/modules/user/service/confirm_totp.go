@@ -0,0 +1,97 @@
+package userservice
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+)
+
+// ConfirmTotpCommand đại diện cho command xác nhận đăng ký TOTP bằng mã đầu tiên từ app
+type ConfirmTotpCommand struct {
+	UserID    uuid.UUID
+	IPAddress string
+	UserAgent string
+	Dto       usermodel.ConfirmTotpForm
+}
+
+// ConfirmTotpResult chứa mã khôi phục, chỉ được trả về một lần
+type ConfirmTotpResult struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// IConfirmTotpRepo interface cho repository operations cần thiết
+type IConfirmTotpRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	FindTotpCredential(ctx context.Context, userID uuid.UUID) (*usermodel.TotpCredential, error)
+	UseTotpStep(ctx context.Context, userID uuid.UUID, step int64, now time.Time) (bool, error)
+	EnableTwoFactor(ctx context.Context, userID uuid.UUID, recoveryCodes []usermodel.RecoveryCode, now time.Time) error
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// ConfirmTotpCommandHandler bật 2FA sau khi user chứng minh app authenticator đã có đúng secret
+type ConfirmTotpCommandHandler struct {
+	repo              IConfirmTotpRepo
+	recoveryCodeCount int
+}
+
+// NewConfirmTotpCommandHandler khởi tạo handler mới
+func NewConfirmTotpCommandHandler(repo IConfirmTotpRepo, recoveryCodeCount int) *ConfirmTotpCommandHandler {
+	return &ConfirmTotpCommandHandler{repo: repo, recoveryCodeCount: recoveryCodeCount}
+}
+
+// Execute kiểm tra mã TOTP, bật 2FA và sinh bộ mã khôi phục mới
+func (hdl *ConfirmTotpCommandHandler) Execute(ctx context.Context, cmd *ConfirmTotpCommand) (*ConfirmTotpResult, error) {
+	user, err := hdl.repo.FindById(ctx, cmd.UserID)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if user.IsTwoFactorEnabled() {
+		return nil, datatype.ErrConflict.WithError(usermodel.ErrTwoFactorAlreadyEnabled.Error())
+	}
+
+	credential, err := hdl.repo.FindTotpCredential(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, datatype.ErrBadRequest.WithError(usermodel.ErrTwoFactorNotEnrolled.Error())
+		}
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if credential.ConfirmedAt != nil {
+		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrTwoFactorNotEnrolled.Error())
+	}
+
+	now := time.Now()
+	valid, err := verifyTotpCode(ctx, hdl.repo, credential, cmd.Dto.Code, now)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if !valid {
+		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrInvalidTwoFactorCode.Error())
+	}
+
+	codes, records, err := generateRecoveryCodes(user.ID, hdl.recoveryCodeCount, now)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if err := hdl.repo.EnableTwoFactor(ctx, user.ID, records, now); err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &user.ID,
+		ActorID:   &user.ID,
+		Event:     usermodel.AuditEventTwoFactorEnabled,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		CreatedAt: now,
+	})
+
+	return &ConfirmTotpResult{RecoveryCodes: codes}, nil
+}
@@ -0,0 +1,134 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	// apiKeyTag là tiền tố nhận diện API key, giúp công cụ quét secret phát hiện khóa bị lộ
+	apiKeyTag = "ikv_"
+	// apiKeyPrefixLen là số ký tự đầu của khóa được lưu rõ để hiển thị
+	apiKeyPrefixLen = 12
+)
+
+// adminOnlyScopes là các scope chỉ cấp được cho admin
+var adminOnlyScopes = []string{usermodel.ScopeUsersRead, usermodel.ScopeUsersWrite}
+
+// APIKeyConfig cấu hình thời hạn và số lượng API key của mỗi user
+type APIKeyConfig struct {
+	DefaultLifetime time.Duration
+	MaxLifetime     time.Duration
+	MaxPerUser      int
+}
+
+// CreateAPIKeyCommand đại diện cho command tạo API key
+type CreateAPIKeyCommand struct {
+	UserID    uuid.UUID
+	IPAddress string
+	UserAgent string
+	Dto       usermodel.CreateAPIKeyForm
+}
+
+// ICreateAPIKeyRepo interface cho repository operations cần thiết
+type ICreateAPIKeyRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	ListActiveAPIKeys(ctx context.Context, userID uuid.UUID, now time.Time) ([]*usermodel.APIKey, error)
+	InsertAPIKey(ctx context.Context, key *usermodel.APIKey) error
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// CreateAPIKeyCommandHandler sinh API key mới, khóa đầy đủ chỉ trả về một lần
+type CreateAPIKeyCommandHandler struct {
+	repo   ICreateAPIKeyRepo
+	config APIKeyConfig
+}
+
+// NewCreateAPIKeyCommandHandler khởi tạo handler mới
+func NewCreateAPIKeyCommandHandler(repo ICreateAPIKeyRepo, config APIKeyConfig) *CreateAPIKeyCommandHandler {
+	return &CreateAPIKeyCommandHandler{repo: repo, config: config}
+}
+
+// Execute kiểm tra scope, thời hạn và số lượng khóa rồi lưu giá trị băm của khóa mới
+func (hdl *CreateAPIKeyCommandHandler) Execute(ctx context.Context, cmd *CreateAPIKeyCommand) (*usermodel.APIKeyCreatedResponse, error) {
+	user, err := hdl.repo.FindById(ctx, cmd.UserID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, datatype.ErrNotFound.WithError(usermodel.ErrUserNotFound.Error())
+		}
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	scopes := slices.Compact(slices.Sorted(slices.Values(cmd.Dto.Scopes)))
+	if user.Role != usermodel.RoleAdmin && slices.ContainsFunc(scopes, isAdminOnlyScope) {
+		return nil, datatype.ErrForbidden.WithError(usermodel.ErrAPIKeyScopeNotAllowed.Error())
+	}
+
+	lifetime := hdl.config.DefaultLifetime
+	if cmd.Dto.ExpiresInDays > 0 {
+		lifetime = time.Duration(cmd.Dto.ExpiresInDays) * 24 * time.Hour
+	}
+	if lifetime > hdl.config.MaxLifetime {
+		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrAPIKeyLifetimeTooLong.Error()).
+			WithDetail("max_expires_in_days", int(hdl.config.MaxLifetime/(24*time.Hour)))
+	}
+
+	now := time.Now()
+	activeKeys, err := hdl.repo.ListActiveAPIKeys(ctx, user.ID, now)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if len(activeKeys) >= hdl.config.MaxPerUser {
+		return nil, datatype.ErrConflict.WithError(usermodel.ErrAPIKeyLimitReached.Error())
+	}
+
+	secret, err := shared.RandomStr(32)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	plainKey := apiKeyTag + secret
+
+	newId, _ := uuid.NewV7()
+	expiresAt := now.Add(lifetime)
+	key := &usermodel.APIKey{
+		ID:        newId,
+		UserID:    user.ID,
+		Name:      cmd.Dto.Name,
+		Prefix:    plainKey[:apiKeyPrefixLen],
+		KeyHash:   shared.HashToken(plainKey),
+		Scopes:    strings.Join(scopes, " "),
+		ExpiresAt: &expiresAt,
+		CreatedAt: now,
+	}
+	if err := hdl.repo.InsertAPIKey(ctx, key); err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	metadata, _ := json.Marshal(map[string]any{"key_id": key.ID, "name": key.Name, "scopes": scopes})
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &user.ID,
+		ActorID:   &user.ID,
+		Event:     usermodel.AuditEventAPIKeyCreated,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: now,
+	})
+
+	return &usermodel.APIKeyCreatedResponse{APIKeyResponse: *key.ToResponse(), Key: plainKey}, nil
+}
+
+// isAdminOnlyScope kiểm tra scope chỉ dành cho admin
+func isAdminOnlyScope(scope string) bool {
+	return slices.Contains(adminOnlyScopes, scope)
+}
@@ -0,0 +1,70 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+)
+
+// currentPasswordChecker xác nhận lại mật khẩu hiện tại trước các thao tác nhạy cảm khi đã đăng nhập.
+// Lần nhập sai được đếm chung với bộ đếm chống dò mật khẩu khi đăng nhập (theo email và IP)
+// để access token bị lộ không thể dùng để dò mật khẩu không giới hạn.
+type currentPasswordChecker struct {
+	repo           IAuditEventRepo
+	loginThrottle  ILoginThrottle
+	passwordHasher IPasswordHasher
+}
+
+// Verify trả về 429 khi email hoặc IP đang bị chặn, 400 khi mật khẩu sai; action được ghi vào metadata của audit
+func (c currentPasswordChecker) Verify(ctx context.Context, user *usermodel.User, password string, action string, client SessionClient) error {
+	now := time.Now()
+	if err := c.loginThrottle.Check(ctx, user.Email, client.IPAddress, now); err != nil {
+		if isLoginThrottled(err) {
+			c.recordFailed(ctx, user, action, usermodel.LoginFailureReasonThrottled, client, now)
+		}
+		return err
+	}
+
+	if ok, _, _ := c.passwordHasher.Verify(password, user.Salt, user.Password); ok {
+		if err := c.loginThrottle.RecordSuccess(ctx, user.Email); err != nil {
+			log.Printf("Error clearing login failures for user %s: %v", user.ID, err)
+		}
+		return nil
+	}
+
+	c.recordFailed(ctx, user, action, usermodel.LoginFailureReasonInvalidPassword, client, now)
+
+	locked, err := c.loginThrottle.RecordFailure(ctx, user.Email, client.IPAddress, now)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if locked {
+		recordAuditEvent(ctx, c.repo, &usermodel.AuditEvent{
+			UserID:    &user.ID,
+			Event:     usermodel.AuditEventAccountLocked,
+			IPAddress: client.IPAddress,
+			UserAgent: client.UserAgent,
+			CreatedAt: now,
+		})
+	}
+
+	return datatype.ErrBadRequest.WithError(usermodel.ErrCurrentPasswordInvalid.Error())
+}
+
+// recordFailed ghi sự kiện xác nhận mật khẩu thất bại kèm thao tác và lý do
+func (c currentPasswordChecker) recordFailed(ctx context.Context, user *usermodel.User, action string, reason string, client SessionClient, now time.Time) {
+	metadata, _ := json.Marshal(map[string]string{"action": action, "reason": reason})
+	recordAuditEvent(ctx, c.repo, &usermodel.AuditEvent{
+		UserID:    &user.ID,
+		ActorID:   &user.ID,
+		Event:     usermodel.AuditEventPasswordConfirmFailed,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: now,
+	})
+}
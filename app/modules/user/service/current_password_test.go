@@ -0,0 +1,82 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+)
+
+// fakePasswordHasher chấp nhận đúng một mật khẩu, giá trị băm là chính mật khẩu
+type fakePasswordHasher struct{}
+
+func (fakePasswordHasher) Hash(password string) (string, error) {
+	return password, nil
+}
+
+func (fakePasswordHasher) Verify(password string, _ string, encoded string) (bool, bool, error) {
+	return password == encoded, false, nil
+}
+
+type fakeAuditEventRepo struct {
+	events []usermodel.AuditEvent
+}
+
+func (r *fakeAuditEventRepo) InsertAuditEvent(_ context.Context, event *usermodel.AuditEvent) error {
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func TestCurrentPasswordCheckerThrottlesFailures(t *testing.T) {
+	ctx := context.Background()
+	userID, _ := uuid.NewV7()
+	user := &usermodel.User{ID: userID, Email: "user@example.com", Password: "current-password"}
+	client := SessionClient{IPAddress: "10.0.0.1", UserAgent: "test"}
+
+	config := testLoginThrottleConfig()
+	auditRepo := &fakeAuditEventRepo{}
+	checker := currentPasswordChecker{
+		repo:           auditRepo,
+		loginThrottle:  NewLoginThrottle(newFakeLoginThrottleRepo(), config),
+		passwordHasher: fakePasswordHasher{},
+	}
+
+	if err := checker.Verify(ctx, user, "current-password", usermodel.PasswordConfirmActionChangePassword, client); err != nil {
+		t.Fatalf("Verify(correct password) error = %v", err)
+	}
+
+	// Hết số lần sai miễn phí thì phải chờ, kể cả khi nhập đúng mật khẩu
+	for i := 0; i < config.FreeAttempts+1; i++ {
+		err := checker.Verify(ctx, user, "guess", usermodel.PasswordConfirmActionChangePassword, client)
+		var appErr *datatype.DefaultError
+		if !errors.As(err, &appErr) || appErr.StatusCode() != http.StatusBadRequest || appErr.Error() != usermodel.ErrCurrentPasswordInvalid.Error() {
+			t.Fatalf("Verify(wrong password) attempt %d error = %v, want 400 %q", i+1, err, usermodel.ErrCurrentPasswordInvalid)
+		}
+	}
+	err := checker.Verify(ctx, user, "current-password", usermodel.PasswordConfirmActionChangePassword, client)
+	assertTooManyRequests(t, err, usermodel.ErrLoginThrottled)
+
+	wantReasons := []string{
+		usermodel.LoginFailureReasonInvalidPassword,
+		usermodel.LoginFailureReasonInvalidPassword,
+		usermodel.LoginFailureReasonInvalidPassword,
+		usermodel.LoginFailureReasonThrottled,
+	}
+	if len(auditRepo.events) != len(wantReasons) {
+		t.Fatalf("recorded %d audit events, want %d", len(auditRepo.events), len(wantReasons))
+	}
+	for i, event := range auditRepo.events {
+		var metadata map[string]string
+		_ = json.Unmarshal([]byte(event.Metadata), &metadata)
+		if event.Event != usermodel.AuditEventPasswordConfirmFailed || *event.UserID != userID ||
+			metadata["reason"] != wantReasons[i] || metadata["action"] != usermodel.PasswordConfirmActionChangePassword {
+			t.Errorf("audit event %d = %s %v, want %s with reason %s", i, event.Event, metadata, usermodel.AuditEventPasswordConfirmFailed, wantReasons[i])
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+)
+
+// IUserDataRegistry interface thu thập và xóa dữ liệu của user trên mọi module được kích hoạt
+type IUserDataRegistry interface {
+	ExportUserData(ctx context.Context, userID uuid.UUID) (map[string]any, error)
+	EraseUserData(ctx context.Context, userID uuid.UUID) error
+}
+
+// IDataSubjectRequestRepo interface cho repository operations cần thiết
+type IDataSubjectRequestRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// ExportUserDataCommand đại diện cho command trích xuất dữ liệu cá nhân của user.
+// ActorID là uuid.Nil khi chạy từ CLI.
+type ExportUserDataCommand struct {
+	UserID    uuid.UUID
+	ActorID   uuid.UUID
+	IPAddress string
+	UserAgent string
+}
+
+// ExportUserDataCommandHandler gom dữ liệu của user từ mọi module thành một bản trích xuất JSON
+type ExportUserDataCommandHandler struct {
+	repo     IDataSubjectRequestRepo
+	registry IUserDataRegistry
+}
+
+// NewExportUserDataCommandHandler khởi tạo handler mới
+func NewExportUserDataCommandHandler(repo IDataSubjectRequestRepo, registry IUserDataRegistry) *ExportUserDataCommandHandler {
+	return &ExportUserDataCommandHandler{repo: repo, registry: registry}
+}
+
+// Execute thu thập dữ liệu và ghi audit việc trích xuất
+func (hdl *ExportUserDataCommandHandler) Execute(ctx context.Context, cmd *ExportUserDataCommand) (*usermodel.UserDataArchive, error) {
+	user, err := findDataSubject(ctx, hdl.repo, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	modules, err := hdl.registry.ExportUserData(ctx, user.ID)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	now := time.Now()
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &user.ID,
+		ActorID:   dataSubjectActor(cmd.ActorID),
+		Event:     usermodel.AuditEventUserDataExported,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		CreatedAt: now,
+	})
+
+	return &usermodel.UserDataArchive{UserID: user.ID, ExportedAt: now, Modules: modules}, nil
+}
+
+// EraseUserDataCommand đại diện cho command xóa dữ liệu cá nhân của user trên mọi module.
+// ActorID là uuid.Nil khi chạy từ CLI.
+type EraseUserDataCommand struct {
+	UserID    uuid.UUID
+	ActorID   uuid.UUID
+	IPAddress string
+	UserAgent string
+}
+
+// EraseUserDataCommandHandler yêu cầu mọi module xóa hoặc ẩn danh dữ liệu của user.
+// Thao tác không thể hoàn tác: tài khoản chuyển sang deleted và không khôi phục được.
+type EraseUserDataCommandHandler struct {
+	repo     IDataSubjectRequestRepo
+	registry IUserDataRegistry
+}
+
+// NewEraseUserDataCommandHandler khởi tạo handler mới
+func NewEraseUserDataCommandHandler(repo IDataSubjectRequestRepo, registry IUserDataRegistry) *EraseUserDataCommandHandler {
+	return &EraseUserDataCommandHandler{repo: repo, registry: registry}
+}
+
+// Execute xóa dữ liệu và ghi audit, admin không được tự xóa dữ liệu của chính mình
+func (hdl *EraseUserDataCommandHandler) Execute(ctx context.Context, cmd *EraseUserDataCommand) error {
+	if cmd.UserID == cmd.ActorID {
+		return datatype.ErrForbidden.WithError(usermodel.ErrCannotManageSelf.Error())
+	}
+
+	user, err := findDataSubject(ctx, hdl.repo, cmd.UserID)
+	if err != nil {
+		return err
+	}
+	if user.IsErased() {
+		return datatype.ErrConflict.WithError(usermodel.ErrUserAlreadyErased.Error())
+	}
+
+	if err := hdl.registry.EraseUserData(ctx, user.ID); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	metadata, _ := json.Marshal(map[string]string{"previous_status": string(user.Status)})
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &user.ID,
+		ActorID:   dataSubjectActor(cmd.ActorID),
+		Event:     usermodel.AuditEventUserErased,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: time.Now(),
+	})
+
+	return nil
+}
+
+// findDataSubject tìm user của yêu cầu dữ liệu cá nhân, trả về 404 nếu không tồn tại
+func findDataSubject(ctx context.Context, repo IDataSubjectRequestRepo, id uuid.UUID) (*usermodel.User, error) {
+	user, err := repo.FindById(ctx, id)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, datatype.ErrNotFound.WithError(usermodel.ErrUserNotFound.Error())
+		}
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	return user, nil
+}
+
+// dataSubjectActor trả về người thực hiện cho sự kiện audit, nil khi chạy từ CLI
+func dataSubjectActor(actorID uuid.UUID) *uuid.UUID {
+	if actorID == uuid.Nil {
+		return nil
+	}
+	return &actorID
+}
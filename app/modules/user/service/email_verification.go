@@ -0,0 +1,61 @@
+package userservice
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	sharecomponent "fat2fast/ikv/shared/component"
+)
+
+// EmailVerificationPurpose phân biệt token xác minh email với các loại token ký khác
+const EmailVerificationPurpose = "email_verification"
+
+// IMailer interface gửi email
+type IMailer interface {
+	Send(ctx context.Context, mail *sharecomponent.Mail) error
+}
+
+// ITokenSigner interface ký và kiểm tra token trong link gửi qua email
+type ITokenSigner interface {
+	Sign(purpose string, subject string, expiresAt time.Time) string
+	Verify(purpose string, token string, now time.Time) (string, error)
+}
+
+// IEmailVerificationSender interface gửi email xác minh cho user
+type IEmailVerificationSender interface {
+	Send(ctx context.Context, user *usermodel.User) error
+}
+
+// EmailVerificationSender tạo link xác minh đã ký và gửi qua mailer
+type EmailVerificationSender struct {
+	mailer     IMailer
+	signer     ITokenSigner
+	linkURL    string
+	tokenExpIn time.Duration
+}
+
+// NewEmailVerificationSender khởi tạo sender, linkURL là endpoint xác minh nhận tham số token
+func NewEmailVerificationSender(mailer IMailer, signer ITokenSigner, linkURL string, tokenExpIn time.Duration) *EmailVerificationSender {
+	return &EmailVerificationSender{mailer: mailer, signer: signer, linkURL: linkURL, tokenExpIn: tokenExpIn}
+}
+
+// Send gửi email chứa link xác minh, token gắn với cả user ID và email hiện tại của user
+func (s *EmailVerificationSender) Send(ctx context.Context, user *usermodel.User) error {
+	token := s.signer.Sign(EmailVerificationPurpose, emailVerificationSubject(user), time.Now().Add(s.tokenExpIn))
+	link := fmt.Sprintf("%s?token=%s", s.linkURL, url.QueryEscape(token))
+
+	return s.mailer.Send(ctx, &sharecomponent.Mail{
+		To:      user.Email,
+		Subject: "Xác minh địa chỉ email của bạn",
+		Body: fmt.Sprintf("Xin chào %s,\n\nVui lòng mở link sau để kích hoạt tài khoản:\n%s\n\nLink có hiệu lực trong %s.\nNếu bạn không đăng ký tài khoản, hãy bỏ qua email này.\n",
+			user.GetFullName(), link, s.tokenExpIn),
+	})
+}
+
+// emailVerificationSubject là nội dung được ký trong token xác minh
+func emailVerificationSubject(user *usermodel.User) string {
+	return user.ID.String() + ":" + user.Email
+}
@@ -0,0 +1,73 @@
+package userservice
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	sharecomponent "fat2fast/ikv/shared/component"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+)
+
+// EnrollTotpCommand đại diện cho command bắt đầu đăng ký TOTP
+type EnrollTotpCommand struct {
+	UserID uuid.UUID
+}
+
+// EnrollTotpResult chứa secret và otpauth URI để user thêm vào app authenticator
+type EnrollTotpResult struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauthUri"`
+}
+
+// IEnrollTotpRepo interface cho repository operations cần thiết
+type IEnrollTotpRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	UpsertPendingTotpCredential(ctx context.Context, credential *usermodel.TotpCredential) error
+}
+
+// EnrollTotpCommandHandler sinh secret TOTP mới, 2FA chỉ được bật sau khi user xác nhận bằng một mã hợp lệ
+type EnrollTotpCommandHandler struct {
+	repo   IEnrollTotpRepo
+	issuer string
+}
+
+// NewEnrollTotpCommandHandler khởi tạo handler mới, issuer là tên hiển thị trong app authenticator
+func NewEnrollTotpCommandHandler(repo IEnrollTotpRepo, issuer string) *EnrollTotpCommandHandler {
+	return &EnrollTotpCommandHandler{repo: repo, issuer: issuer}
+}
+
+// Execute tạo (hoặc tạo lại) secret cho lần đăng ký chưa xác nhận
+func (hdl *EnrollTotpCommandHandler) Execute(ctx context.Context, cmd *EnrollTotpCommand) (*EnrollTotpResult, error) {
+	user, err := hdl.repo.FindById(ctx, cmd.UserID)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if user.IsTwoFactorEnabled() {
+		return nil, datatype.ErrConflict.WithError(usermodel.ErrTwoFactorAlreadyEnabled.Error())
+	}
+
+	secret, err := sharecomponent.GenerateTOTPSecret()
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	now := time.Now()
+	credential := &usermodel.TotpCredential{
+		UserID:    user.ID,
+		Secret:    secret,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := hdl.repo.UpsertPendingTotpCredential(ctx, credential); err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	return &EnrollTotpResult{
+		Secret:     secret,
+		OtpauthURI: sharecomponent.TOTPKeyURI(hdl.issuer, user.Email, secret),
+	}, nil
+}
@@ -0,0 +1,107 @@
+package userservice
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared"
+	sharecomponent "fat2fast/ikv/shared/component"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// ForgotPasswordCommand đại diện cho command yêu cầu đặt lại mật khẩu
+type ForgotPasswordCommand struct {
+	Dto usermodel.ForgotPasswordForm
+}
+
+// IForgotPasswordRepo interface cho repository operations cần thiết
+type IForgotPasswordRepo interface {
+	FindByEmail(ctx context.Context, email string) (*usermodel.User, error)
+	CountPasswordResetTokensSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error)
+	InsertPasswordResetToken(ctx context.Context, token *usermodel.PasswordResetToken) error
+}
+
+// PasswordResetConfig là cấu hình luồng đặt lại mật khẩu
+type PasswordResetConfig struct {
+	LinkURL         string
+	TokenExpIn      time.Duration
+	RateLimitWindow time.Duration
+	RateLimitMax    int
+}
+
+// ForgotPasswordCommandHandler cấp token đặt lại mật khẩu và gửi link qua email
+type ForgotPasswordCommandHandler struct {
+	repo   IForgotPasswordRepo
+	mailer IMailer
+	config PasswordResetConfig
+}
+
+// NewForgotPasswordCommandHandler khởi tạo handler mới
+func NewForgotPasswordCommandHandler(repo IForgotPasswordRepo, mailer IMailer, config PasswordResetConfig) *ForgotPasswordCommandHandler {
+	return &ForgotPasswordCommandHandler{repo: repo, mailer: mailer, config: config}
+}
+
+// Execute luôn trả về thành công với email không tồn tại, tài khoản bị khóa hoặc vượt giới hạn gửi
+// để endpoint không tiết lộ email nào đã đăng ký.
+func (hdl *ForgotPasswordCommandHandler) Execute(ctx context.Context, cmd *ForgotPasswordCommand) error {
+	user, err := hdl.repo.FindByEmail(ctx, cmd.Dto.Email)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil
+		}
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if user.Status == usermodel.StatusBanned || user.Status == usermodel.StatusDeleted {
+		return nil
+	}
+
+	now := time.Now()
+
+	// Giới hạn số email đặt lại mật khẩu cho mỗi tài khoản trong một khoảng thời gian
+	count, err := hdl.repo.CountPasswordResetTokensSince(ctx, user.ID, now.Add(-hdl.config.RateLimitWindow))
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if count >= int64(hdl.config.RateLimitMax) {
+		log.Printf("Password reset rate limit reached for user %s", user.ID)
+		return nil
+	}
+
+	token, err := shared.RandomStr(32)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	newId, _ := uuid.NewV7()
+	resetToken := &usermodel.PasswordResetToken{
+		ID:        newId,
+		UserID:    user.ID,
+		TokenHash: shared.HashToken(token),
+		ExpiresAt: now.Add(hdl.config.TokenExpIn),
+		CreatedAt: now,
+	}
+	if err := hdl.repo.InsertPasswordResetToken(ctx, resetToken); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	link := fmt.Sprintf("%s?token=%s", hdl.config.LinkURL, url.QueryEscape(token))
+	mail := &sharecomponent.Mail{
+		To:      user.Email,
+		Subject: "Đặt lại mật khẩu",
+		Body: fmt.Sprintf("Xin chào %s,\n\nChúng tôi nhận được yêu cầu đặt lại mật khẩu cho tài khoản của bạn.\nMở link sau để đặt mật khẩu mới:\n%s\n\nLink chỉ dùng được một lần và có hiệu lực trong %s.\nNếu bạn không yêu cầu, hãy bỏ qua email này.\n",
+			user.GetFullName(), link, hdl.config.TokenExpIn),
+	}
+	if err := hdl.mailer.Send(ctx, mail); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	return nil
+}
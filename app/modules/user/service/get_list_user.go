@@ -2,14 +2,46 @@ package userservice
 
 import (
 	"context"
+
 	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
 )
 
+// ListQuery đại diện cho query lấy danh sách user (admin)
 type ListQuery struct {
-	Page  int
-	Limit int
+	datatype.Paging
+	Filter usermodel.UserFilter
 }
 
+// IListRepo interface cho repository list operations, Total của paging được cập nhật theo filter
 type IListRepo interface {
-	List(ctx context.Context, query *ListQuery) ([]*usermodel.User, error)
+	List(ctx context.Context, filter *usermodel.UserFilter, paging *datatype.Paging) ([]*usermodel.User, error)
+}
+
+// ListQueryHandler xử lý query lấy danh sách user có phân trang
+type ListQueryHandler struct {
+	repo  IListRepo
+	files IFileURLResolver
+}
+
+// NewListQueryHandler khởi tạo handler mới
+func NewListQueryHandler(repo IListRepo, files IFileURLResolver) *ListQueryHandler {
+	return &ListQueryHandler{repo: repo, files: files}
+}
+
+// Execute trả về một trang user, query.Paging được chuẩn hóa và cập nhật Total
+func (hdl *ListQueryHandler) Execute(ctx context.Context, query *ListQuery) ([]*usermodel.ProfileResponse, error) {
+	query.Paging.Process()
+
+	users, err := hdl.repo.List(ctx, &query.Filter, &query.Paging)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	result := make([]*usermodel.ProfileResponse, len(users))
+	for i, user := range users {
+		result[i] = toProfileResponse(user, hdl.files)
+	}
+
+	return result, nil
 }
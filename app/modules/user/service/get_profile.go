@@ -10,9 +10,11 @@ import (
 	"github.com/pkg/errors"
 )
 
-// GetProfileQuery đại diện cho query lấy thông tin profile user
+// GetProfileQuery đại diện cho query lấy thông tin profile user.
+// Requester là người gọi API, chỉ được xem profile của chính mình trừ khi là admin.
 type GetProfileQuery struct {
-	UserID uuid.UUID
+	UserID    uuid.UUID
+	Requester datatype.Requester
 }
 
 // IGetProfileRepo interface cho repository operations cần thiết
@@ -22,12 +24,13 @@ type IGetProfileRepo interface {
 
 // GetProfileQueryHandler xử lý query lấy thông tin profile
 type GetProfileQueryHandler struct {
-	repo IGetProfileRepo
+	repo  IGetProfileRepo
+	files IFileURLResolver
 }
 
 // NewGetProfileQueryHandler khởi tạo handler mới
-func NewGetProfileQueryHandler(repo IGetProfileRepo) *GetProfileQueryHandler {
-	return &GetProfileQueryHandler{repo: repo}
+func NewGetProfileQueryHandler(repo IGetProfileRepo, files IFileURLResolver) *GetProfileQueryHandler {
+	return &GetProfileQueryHandler{repo: repo, files: files}
 }
 
 // Execute thực thi query lấy thông tin profile
@@ -37,6 +40,10 @@ func (hdl *GetProfileQueryHandler) Execute(ctx context.Context, query *GetProfil
 		return nil, datatype.ErrBadRequest.WithError("User ID is required")
 	}
 
+	if err := authorizeProfileAccess(query.Requester, query.UserID, usermodel.ScopeUsersRead); err != nil {
+		return nil, err
+	}
+
 	// Lấy thông tin user từ database
 	user, err := hdl.repo.FindById(ctx, query.UserID)
 	if err != nil {
@@ -56,7 +63,19 @@ func (hdl *GetProfileQueryHandler) Execute(ctx context.Context, query *GetProfil
 	}
 
 	// Convert entity sang DTO response
-	profileResponse := user.ToProfileResponse()
+	profileResponse := toProfileResponse(user, hdl.files)
 
 	return profileResponse, nil
 }
+
+// authorizeProfileAccess chỉ cho phép chủ sở hữu profile hoặc admin thao tác.
+// Admin dùng API key phải có thêm adminScope để thao tác trên profile của user khác.
+func authorizeProfileAccess(requester datatype.Requester, userID uuid.UUID, adminScope string) error {
+	if requester == nil {
+		return datatype.ErrUnauthorized.WithError("Authentication is required")
+	}
+	if requester.UserID() != userID && !(datatype.IsAdmin(requester) && datatype.HasScopes(requester, adminScope)) {
+		return datatype.ErrForbidden.WithError(usermodel.ErrProfileAccessDenied.Error())
+	}
+	return nil
+}
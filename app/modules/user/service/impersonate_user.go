@@ -0,0 +1,135 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	sharecomponent "fat2fast/ikv/shared/component"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// ImpersonationConfig là cấu hình token impersonation
+type ImpersonationConfig struct {
+	TokenExpIn time.Duration
+}
+
+// ImpersonateUserCommand đại diện cho command admin đăng nhập dưới danh nghĩa user để tái hiện lỗi
+type ImpersonateUserCommand struct {
+	UserID    uuid.UUID
+	ActorID   uuid.UUID
+	IPAddress string
+	UserAgent string
+	Dto       usermodel.ImpersonateForm
+}
+
+// IImpersonateUserRepo interface cho repository operations cần thiết
+type IImpersonateUserRepo interface {
+	IOrganizationMemberFinder
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	FindDefaultOrganizationMember(ctx context.Context, userID uuid.UUID) (*usermodel.OrganizationMember, error)
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// ImpersonateUserCommandHandler cấp token impersonation cho admin
+type ImpersonateUserCommandHandler struct {
+	repo        IImpersonateUserRepo
+	tokenIssuer ITokenIssuer
+	config      ImpersonationConfig
+}
+
+// NewImpersonateUserCommandHandler khởi tạo handler mới
+func NewImpersonateUserCommandHandler(repo IImpersonateUserRepo, tokenIssuer ITokenIssuer, config ImpersonationConfig) *ImpersonateUserCommandHandler {
+	return &ImpersonateUserCommandHandler{repo: repo, tokenIssuer: tokenIssuer, config: config}
+}
+
+// Execute cấp access token ngắn hạn có subject là user và claim "act" là admin.
+// Token không thuộc phiên đăng nhập nào và không có refresh token, admin kết thúc impersonation bằng logout.
+// Không impersonate được chính mình, admin khác hoặc user bị cấm/đã xóa.
+func (hdl *ImpersonateUserCommandHandler) Execute(ctx context.Context, cmd *ImpersonateUserCommand) (*AuthenticateResult, error) {
+	if cmd.UserID == cmd.ActorID {
+		return nil, datatype.ErrForbidden.WithError(usermodel.ErrCannotImpersonate.Error())
+	}
+
+	user, err := hdl.repo.FindById(ctx, cmd.UserID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, datatype.ErrNotFound.WithError(usermodel.ErrUserNotFound.Error())
+		}
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if user.Role == usermodel.RoleAdmin {
+		return nil, datatype.ErrForbidden.WithError(usermodel.ErrCannotImpersonate.Error())
+	}
+	if user.Status == usermodel.StatusDeleted || user.Status == usermodel.StatusBanned {
+		return nil, datatype.ErrConflict.WithError(usermodel.ErrUserStatusTransition.Error())
+	}
+
+	member, err := hdl.organizationMember(ctx, user.ID, cmd.Dto.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expIn := min(int(hdl.config.TokenExpIn/time.Second), hdl.tokenIssuer.ExpIn())
+
+	claims := sharecomponent.TokenClaims{
+		CredentialVersion: user.CredentialVersion,
+		Actor:             &sharecomponent.TokenActor{Subject: cmd.ActorID.String()},
+	}
+	claims.Subject = user.ID.String()
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(time.Second * time.Duration(expIn)))
+	if member != nil {
+		claims.OrganizationID = member.OrganizationID.String()
+		claims.OrganizationRole = member.Role
+	}
+
+	token, err := hdl.tokenIssuer.IssueTokenWithClaims(ctx, claims)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	fields := map[string]string{
+		"reason":     strings.TrimSpace(cmd.Dto.Reason),
+		"expires_at": claims.ExpiresAt.Format(time.RFC3339),
+	}
+	if member != nil {
+		fields["organization_id"] = member.OrganizationID.String()
+	}
+	metadata, _ := json.Marshal(fields)
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &user.ID,
+		ActorID:   &cmd.ActorID,
+		Event:     usermodel.AuditEventImpersonationStarted,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: now,
+	})
+
+	return &AuthenticateResult{Token: token, ExpIn: expIn}, nil
+}
+
+// organizationMember trả về tư cách thành viên của user trong tổ chức được chọn,
+// không chọn thì dùng tổ chức tham gia sớm nhất như khi user tự đăng nhập (nil nếu user chưa có tổ chức)
+func (hdl *ImpersonateUserCommandHandler) organizationMember(ctx context.Context, userID uuid.UUID, organizationID *uuid.UUID) (*usermodel.OrganizationMember, error) {
+	if organizationID != nil {
+		return requireOrganizationRole(ctx, hdl.repo, *organizationID, userID)
+	}
+
+	member, err := hdl.repo.FindDefaultOrganizationMember(ctx, userID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	return member, nil
+}
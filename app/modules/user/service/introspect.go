@@ -0,0 +1,100 @@
+package userservice
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	sharecomponent "fat2fast/ikv/shared/component"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/pkg/errors"
+)
+
+// IntrospectionConfig là cấu hình endpoint introspect, Clients là secret của từng service theo client ID
+type IntrospectionConfig struct {
+	Clients map[string]string
+}
+
+// IntrospectQuery đại diện cho yêu cầu introspection token của một service khác (RFC 7662)
+type IntrospectQuery struct {
+	ClientID     string
+	ClientSecret string
+	Dto          usermodel.IntrospectForm
+}
+
+// IAccessTokenIntrospector interface xác thực access token và trả về kèm claims
+type IAccessTokenIntrospector interface {
+	Introspect(ctx context.Context, accessToken string) (datatype.Requester, *sharecomponent.TokenClaims, error)
+}
+
+// IAPIKeyIntrospector interface xác thực API key và trả về kèm khóa
+type IAPIKeyIntrospector interface {
+	Introspect(ctx context.Context, apiKey string) (datatype.Requester, *usermodel.APIKey, error)
+}
+
+// IntrospectQueryHandler cho phép service khác kiểm tra access token/API key mà không cần truy cập database của module User
+type IntrospectQueryHandler struct {
+	tokenIntrospector  IAccessTokenIntrospector
+	apiKeyIntrospector IAPIKeyIntrospector
+	config             IntrospectionConfig
+}
+
+// NewIntrospectQueryHandler khởi tạo handler mới
+func NewIntrospectQueryHandler(tokenIntrospector IAccessTokenIntrospector, apiKeyIntrospector IAPIKeyIntrospector, config IntrospectionConfig) *IntrospectQueryHandler {
+	return &IntrospectQueryHandler{tokenIntrospector: tokenIntrospector, apiKeyIntrospector: apiKeyIntrospector, config: config}
+}
+
+// Execute xác thực service gọi bằng client ID/secret rồi kiểm tra token giống middleware Auth().
+// Token không hợp lệ (hết hạn, bị thu hồi, user bị cấm...) trả về active = false thay vì lỗi, theo RFC 7662.
+// Token bắt đầu bằng tiền tố API key hoặc có token_type_hint "api_key" được kiểm tra như API key.
+func (hdl *IntrospectQueryHandler) Execute(ctx context.Context, query *IntrospectQuery) (*datatype.IntrospectionResult, error) {
+	if !hdl.authenticateClient(query.ClientID, query.ClientSecret) {
+		return nil, datatype.ErrUnauthorized.WithError(usermodel.ErrInvalidServiceClient.Error())
+	}
+
+	token := strings.TrimSpace(query.Dto.Token)
+
+	if query.Dto.TokenTypeHint == datatype.TokenTypeAPIKey || strings.HasPrefix(token, apiKeyTag) {
+		requester, key, err := hdl.apiKeyIntrospector.Introspect(ctx, token)
+		if err != nil {
+			return inactiveIntrospection(err)
+		}
+		return datatype.NewIntrospectionResult(requester, datatype.TokenTypeAPIKey, key.ExpiresAt), nil
+	}
+
+	requester, claims, err := hdl.tokenIntrospector.Introspect(ctx, token)
+	if err != nil {
+		return inactiveIntrospection(err)
+	}
+
+	result := datatype.NewIntrospectionResult(requester, datatype.TokenTypeAccessToken, nil)
+	if claims.ExpiresAt != nil {
+		result.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+
+	return result, nil
+}
+
+// authenticateClient so sánh secret trong thời gian hằng, client không cấu hình secret bị từ chối
+func (hdl *IntrospectQueryHandler) authenticateClient(clientID string, clientSecret string) bool {
+	secret, ok := hdl.config.Clients[clientID]
+	if !ok || secret == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(clientSecret)) == 1
+}
+
+// inactiveIntrospection chuyển lỗi xác thực token (401) thành kết quả active = false,
+// các lỗi khác (database...) được trả về để service gọi không coi token là không hợp lệ
+func inactiveIntrospection(err error) (*datatype.IntrospectionResult, error) {
+	var appErr *datatype.DefaultError
+	if errors.As(err, &appErr) && appErr.StatusCode() == http.StatusUnauthorized {
+		return &datatype.IntrospectionResult{Active: false}, nil
+	}
+
+	return nil, err
+}
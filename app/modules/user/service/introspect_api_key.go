@@ -0,0 +1,86 @@
+package userservice
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// apiKeyTouchInterval là khoảng thời gian tối thiểu giữa hai lần cập nhật last_used_at của một khóa
+const apiKeyTouchInterval = time.Minute
+
+// IIntrospectAPIKeyRepo interface cho repository operations cần thiết
+type IIntrospectAPIKeyRepo interface {
+	FindAPIKeyByHash(ctx context.Context, keyHash string) (*usermodel.APIKey, error)
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	TouchAPIKey(ctx context.Context, id uuid.UUID, now time.Time, interval time.Duration) error
+}
+
+// IntrospectAPIKeyQueryHandler xác thực API key và nạp thông tin chủ sở hữu
+type IntrospectAPIKeyQueryHandler struct {
+	repo IIntrospectAPIKeyRepo
+}
+
+// NewIntrospectAPIKeyQueryHandler khởi tạo handler mới
+func NewIntrospectAPIKeyQueryHandler(repo IIntrospectAPIKeyRepo) *IntrospectAPIKeyQueryHandler {
+	return &IntrospectAPIKeyQueryHandler{repo: repo}
+}
+
+// IntrospectAPIKey kiểm tra khóa còn hiệu lực và trả về Requester giới hạn trong scope của khóa.
+// Scope chỉ dành cho admin bị bỏ đi nếu chủ sở hữu không còn là admin.
+func (hdl *IntrospectAPIKeyQueryHandler) IntrospectAPIKey(ctx context.Context, apiKey string) (datatype.Requester, error) {
+	requester, _, err := hdl.Introspect(ctx, apiKey)
+	return requester, err
+}
+
+// Introspect giống IntrospectAPIKey nhưng trả về kèm khóa (thời hạn, ID) cho endpoint introspect
+func (hdl *IntrospectAPIKeyQueryHandler) Introspect(ctx context.Context, apiKey string) (datatype.Requester, *usermodel.APIKey, error) {
+	if !strings.HasPrefix(apiKey, apiKeyTag) {
+		return nil, nil, datatype.ErrUnauthorized.WithError(usermodel.ErrInvalidAPIKey.Error())
+	}
+
+	key, err := hdl.repo.FindAPIKeyByHash(ctx, shared.HashToken(apiKey))
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, nil, datatype.ErrUnauthorized.WithError(usermodel.ErrInvalidAPIKey.Error())
+		}
+		return nil, nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	now := time.Now()
+	if !key.IsActive(now) {
+		return nil, nil, datatype.ErrUnauthorized.WithError(usermodel.ErrInvalidAPIKey.Error())
+	}
+
+	user, err := hdl.repo.FindById(ctx, key.UserID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, nil, datatype.ErrUnauthorized.WithError(usermodel.ErrUserNotFound.Error())
+		}
+		return nil, nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if user.Status != usermodel.StatusActive {
+		return nil, nil, datatype.ErrUnauthorized.WithError(usermodel.ErrUserBannedOrDeleted.Error())
+	}
+
+	scopes := key.ScopeList()
+	if user.Role != usermodel.RoleAdmin {
+		scopes = slices.DeleteFunc(scopes, isAdminOnlyScope)
+	}
+
+	if err := hdl.repo.TouchAPIKey(ctx, key.ID, now, apiKeyTouchInterval); err != nil {
+		log.Printf("Error updating last used time of api key %s: %v", key.ID, err)
+	}
+
+	return datatype.NewScopedRequester(user.ID, key.ID.String(), user.FirstName, user.LastName, string(user.Role), string(user.Status), scopes), key, nil
+}
@@ -0,0 +1,188 @@
+package userservice
+
+import (
+	"context"
+	"log"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	sharecomponent "fat2fast/ikv/shared/component"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// ITokenValidator interface kiểm tra access token (chữ ký, thời hạn, denylist) và trả về claims
+type ITokenValidator interface {
+	ParseToken(ctx context.Context, tokenStr string) (*sharecomponent.TokenClaims, error)
+}
+
+// IIntrospectTokenRepo interface cho repository operations cần thiết
+type IIntrospectTokenRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	FindSession(ctx context.Context, id uuid.UUID) (*usermodel.Session, error)
+	TouchSession(ctx context.Context, id uuid.UUID, now time.Time, interval time.Duration) error
+	FindOrganizationMember(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID) (*usermodel.OrganizationMember, error)
+}
+
+// sessionTouchInterval là khoảng thời gian tối thiểu giữa hai lần cập nhật last_seen_at của phiên
+const sessionTouchInterval = time.Minute
+
+// IntrospectTokenQueryHandler xác thực access token và nạp thông tin user tương ứng
+type IntrospectTokenQueryHandler struct {
+	repo           IIntrospectTokenRepo
+	tokenValidator ITokenValidator
+}
+
+// NewIntrospectTokenQueryHandler khởi tạo handler mới
+func NewIntrospectTokenQueryHandler(repo IIntrospectTokenRepo, tokenValidator ITokenValidator) *IntrospectTokenQueryHandler {
+	return &IntrospectTokenQueryHandler{repo: repo, tokenValidator: tokenValidator}
+}
+
+// IntrospectToken kiểm tra token và trả về Requester, từ chối user bị cấm hoặc đã xóa
+// và token thuộc phiên đăng nhập đã bị thu hồi. Tổ chức trong claim "org" chỉ được gắn vào Requester
+// khi user vẫn là thành viên, role trong tổ chức lấy từ database thay vì claim "org_role".
+// Token impersonation (claim "act") chỉ còn hiệu lực khi người impersonate vẫn là admin đang hoạt động.
+func (hdl *IntrospectTokenQueryHandler) IntrospectToken(ctx context.Context, accessToken string) (datatype.Requester, error) {
+	requester, _, err := hdl.Introspect(ctx, accessToken)
+	return requester, err
+}
+
+// Introspect giống IntrospectToken nhưng trả về kèm claims của token (thời hạn, jti...) cho endpoint introspect
+func (hdl *IntrospectTokenQueryHandler) Introspect(ctx context.Context, accessToken string) (datatype.Requester, *sharecomponent.TokenClaims, error) {
+	claims, err := hdl.tokenValidator.ParseToken(ctx, accessToken)
+	if err != nil {
+		return nil, nil, datatype.ErrUnauthorized.WithWrap(err).WithError("Invalid or expired token").WithDebug(err.Error())
+	}
+
+	requester, err := hdl.introspectClaims(ctx, claims)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return requester, claims, nil
+}
+
+// introspectClaims kiểm tra user, phiên đăng nhập, người impersonate và tổ chức của token đã được xác thực chữ ký
+func (hdl *IntrospectTokenQueryHandler) introspectClaims(ctx context.Context, claims *sharecomponent.TokenClaims) (datatype.Requester, error) {
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, datatype.ErrUnauthorized.WithWrap(err).WithError("Invalid token subject")
+	}
+
+	user, err := hdl.repo.FindById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, datatype.ErrUnauthorized.WithError(usermodel.ErrUserNotFound.Error())
+		}
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if user.Status == usermodel.StatusDeleted || user.Status == usermodel.StatusBanned {
+		return nil, datatype.ErrUnauthorized.WithError(usermodel.ErrUserBannedOrDeleted.Error())
+	}
+
+	// Token cấp trước lần đổi mật khẩu gần nhất không còn hiệu lực
+	if claims.CredentialVersion != user.CredentialVersion {
+		return nil, datatype.ErrUnauthorized.WithError(usermodel.ErrCredentialChanged.Error())
+	}
+
+	if claims.SessionID != "" {
+		if err := hdl.checkSession(ctx, user.ID, claims.SessionID); err != nil {
+			return nil, err
+		}
+	}
+
+	requester := datatype.NewRequester(user.ID, claims.ID, claims.SessionID, user.FirstName, user.LastName, string(user.Role), string(user.Status))
+
+	if claims.Actor != nil {
+		impersonatorID, err := hdl.checkImpersonator(ctx, user, claims.Actor.Subject)
+		if err != nil {
+			return nil, err
+		}
+		requester = datatype.WithImpersonator(requester, impersonatorID)
+	}
+
+	if claims.OrganizationID != "" {
+		member, err := hdl.findOrganizationMember(ctx, user.ID, claims.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		if member != nil {
+			requester = datatype.WithOrganization(requester, member.OrganizationID, member.Role)
+		}
+	}
+
+	return requester, nil
+}
+
+// findOrganizationMember tìm tư cách thành viên của user trong tổ chức của token,
+// nil nếu user đã rời tổ chức: token vẫn dùng được nhưng không còn quyền trong tổ chức
+func (hdl *IntrospectTokenQueryHandler) findOrganizationMember(ctx context.Context, userID uuid.UUID, org string) (*usermodel.OrganizationMember, error) {
+	organizationID, err := uuid.Parse(org)
+	if err != nil {
+		return nil, datatype.ErrUnauthorized.WithWrap(err).WithError("Invalid token organization")
+	}
+
+	member, err := hdl.repo.FindOrganizationMember(ctx, organizationID, userID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	return member, nil
+}
+
+// checkImpersonator kiểm tra admin trong claim "act" vẫn được phép impersonate user:
+// admin bị cấm, bị xóa hoặc mất quyền admin, hoặc user đã trở thành admin thì token bị từ chối
+func (hdl *IntrospectTokenQueryHandler) checkImpersonator(ctx context.Context, user *usermodel.User, act string) (uuid.UUID, error) {
+	impersonatorID, err := uuid.Parse(act)
+	if err != nil {
+		return uuid.Nil, datatype.ErrUnauthorized.WithWrap(err).WithError("Invalid token actor")
+	}
+
+	impersonator, err := hdl.repo.FindById(ctx, impersonatorID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return uuid.Nil, datatype.ErrUnauthorized.WithError(usermodel.ErrImpersonationInvalid.Error())
+		}
+		return uuid.Nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if impersonator.Role != usermodel.RoleAdmin || impersonator.Status != usermodel.StatusActive || user.Role == usermodel.RoleAdmin {
+		return uuid.Nil, datatype.ErrUnauthorized.WithError(usermodel.ErrImpersonationInvalid.Error())
+	}
+
+	return impersonator.ID, nil
+}
+
+// checkSession kiểm tra phiên của token còn hiệu lực và ghi nhận hoạt động của phiên.
+// Token cấp trước khi có quản lý phiên không mang "sid" nên không đi qua bước này.
+func (hdl *IntrospectTokenQueryHandler) checkSession(ctx context.Context, userID uuid.UUID, sid string) error {
+	sessionID, err := uuid.Parse(sid)
+	if err != nil {
+		return datatype.ErrUnauthorized.WithWrap(err).WithError("Invalid token session")
+	}
+
+	session, err := hdl.repo.FindSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return datatype.ErrUnauthorized.WithError(usermodel.ErrSessionRevoked.Error())
+		}
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	// Không kiểm tra ExpiresAt vì phiên chỉ được gia hạn khi refresh, access token còn hạn vẫn dùng được
+	if session.UserID != userID || session.RevokedAt != nil {
+		return datatype.ErrUnauthorized.WithError(usermodel.ErrSessionRevoked.Error())
+	}
+
+	if err := hdl.repo.TouchSession(ctx, session.ID, time.Now(), sessionTouchInterval); err != nil {
+		log.Printf("Error touching session %s: %v", session.ID, err)
+	}
+
+	return nil
+}
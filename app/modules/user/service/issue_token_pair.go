@@ -0,0 +1,172 @@
+package userservice
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared"
+	sharecomponent "fat2fast/ikv/shared/component"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// ITokenPairRepo interface cho repository lưu refresh token và phiên đăng nhập
+type ITokenPairRepo interface {
+	InsertRefreshToken(ctx context.Context, token *usermodel.RefreshToken) error
+	InsertSession(ctx context.Context, session *usermodel.Session) error
+	ExtendSession(ctx context.Context, id uuid.UUID, now time.Time, expiresAt time.Time) error
+	FindSession(ctx context.Context, id uuid.UUID) (*usermodel.Session, error)
+	SetSessionOrganization(ctx context.Context, id uuid.UUID, organizationID *uuid.UUID) error
+	FindOrganizationMember(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID) (*usermodel.OrganizationMember, error)
+	FindDefaultOrganizationMember(ctx context.Context, userID uuid.UUID) (*usermodel.OrganizationMember, error)
+}
+
+// SessionClient là thông tin thiết bị mở phiên đăng nhập
+type SessionClient struct {
+	IPAddress string
+	UserAgent string
+}
+
+// TokenPairIssuer cấp access token (JWT) ngắn hạn kèm refresh token (opaque) dài hạn.
+// Mỗi lần đăng nhập mở một phiên, refresh token của phiên thuộc family có ID trùng với ID phiên.
+type TokenPairIssuer struct {
+	tokenIssuer  ITokenIssuer
+	repo         ITokenPairRepo
+	refreshExpIn int
+}
+
+// NewTokenPairIssuer khởi tạo TokenPairIssuer, refreshExpIn tính bằng giây
+func NewTokenPairIssuer(tokenIssuer ITokenIssuer, repo ITokenPairRepo, refreshExpIn int) *TokenPairIssuer {
+	return &TokenPairIssuer{tokenIssuer: tokenIssuer, repo: repo, refreshExpIn: refreshExpIn}
+}
+
+// StartSession mở phiên đăng nhập mới và cấp cặp token đầu tiên của phiên.
+// Phiên mới hoạt động trong tổ chức user tham gia sớm nhất (nếu có), user đổi tổ chức bằng SwitchOrganization.
+func (i *TokenPairIssuer) StartSession(ctx context.Context, user *usermodel.User, client SessionClient) (*AuthenticateResult, error) {
+	sessionID, _ := uuid.NewV7()
+	now := time.Now()
+
+	member, err := i.repo.FindDefaultOrganizationMember(ctx, user.ID)
+	if err != nil && !errors.Is(err, datatype.ErrRecordNotFound) {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	session := &usermodel.Session{
+		ID:         sessionID,
+		UserID:     user.ID,
+		IPAddress:  truncateRunes(client.IPAddress, 45),
+		UserAgent:  truncateRunes(client.UserAgent, 255),
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(time.Second * time.Duration(i.refreshExpIn)),
+	}
+	if member != nil {
+		session.OrganizationID = &member.OrganizationID
+	}
+	if err := i.repo.InsertSession(ctx, session); err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	return i.issue(ctx, user, sessionID, member, now)
+}
+
+// Issue cấp cặp token mới trong phiên đã có khi xoay vòng refresh token, đồng thời gia hạn phiên.
+// Access token mới giữ tổ chức đang hoạt động của phiên nếu user vẫn là thành viên.
+func (i *TokenPairIssuer) Issue(ctx context.Context, user *usermodel.User, sessionID uuid.UUID) (*AuthenticateResult, error) {
+	now := time.Now()
+
+	if err := i.repo.ExtendSession(ctx, sessionID, now, now.Add(time.Second*time.Duration(i.refreshExpIn))); err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	member, err := i.sessionOrganizationMember(ctx, user, sessionID)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	return i.issue(ctx, user, sessionID, member, now)
+}
+
+// SwitchOrganization đổi tổ chức đang hoạt động của phiên và cấp access token mới mang tổ chức đó.
+// Refresh token của phiên không đổi, các lần refresh sau dùng tổ chức mới.
+func (i *TokenPairIssuer) SwitchOrganization(ctx context.Context, user *usermodel.User, sessionID uuid.UUID, member *usermodel.OrganizationMember) (*AuthenticateResult, error) {
+	if err := i.repo.SetSessionOrganization(ctx, sessionID, &member.OrganizationID); err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	accessToken, err := i.accessToken(ctx, user, sessionID, member)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	return &AuthenticateResult{Token: accessToken, ExpIn: i.tokenIssuer.ExpIn()}, nil
+}
+
+// sessionOrganizationMember trả về tư cách thành viên của user trong tổ chức đang hoạt động của phiên,
+// nil nếu phiên chưa chọn tổ chức. User không còn là thành viên thì phiên được bỏ chọn tổ chức.
+func (i *TokenPairIssuer) sessionOrganizationMember(ctx context.Context, user *usermodel.User, sessionID uuid.UUID) (*usermodel.OrganizationMember, error) {
+	session, err := i.repo.FindSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.OrganizationID == nil {
+		return nil, nil
+	}
+
+	member, err := i.repo.FindOrganizationMember(ctx, *session.OrganizationID, user.ID)
+	if err == nil {
+		return member, nil
+	}
+	if !errors.Is(err, datatype.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return nil, i.repo.SetSessionOrganization(ctx, sessionID, nil)
+}
+
+func (i *TokenPairIssuer) issue(ctx context.Context, user *usermodel.User, sessionID uuid.UUID, member *usermodel.OrganizationMember, now time.Time) (*AuthenticateResult, error) {
+	accessToken, err := i.accessToken(ctx, user, sessionID, member)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	refreshToken, err := shared.RandomStr(32)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	newId, _ := uuid.NewV7()
+	token := &usermodel.RefreshToken{
+		ID:        newId,
+		FamilyID:  sessionID,
+		UserID:    user.ID,
+		TokenHash: shared.HashToken(refreshToken),
+		ExpiresAt: now.Add(time.Second * time.Duration(i.refreshExpIn)),
+		CreatedAt: now,
+	}
+	if err := i.repo.InsertRefreshToken(ctx, token); err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	return &AuthenticateResult{
+		Token:        accessToken,
+		ExpIn:        i.tokenIssuer.ExpIn(),
+		RefreshToken: refreshToken,
+		RefreshExpIn: i.refreshExpIn,
+	}, nil
+}
+
+// accessToken ký access token của phiên, member khác nil thì token mang tổ chức và role của user trong tổ chức
+func (i *TokenPairIssuer) accessToken(ctx context.Context, user *usermodel.User, sessionID uuid.UUID, member *usermodel.OrganizationMember) (string, error) {
+	claims := sharecomponent.TokenClaims{CredentialVersion: user.CredentialVersion, SessionID: sessionID.String()}
+	claims.Subject = user.ID.String()
+	if member != nil {
+		claims.OrganizationID = member.OrganizationID.String()
+		claims.OrganizationRole = member.Role
+	}
+
+	return i.tokenIssuer.IssueTokenWithClaims(ctx, claims)
+}
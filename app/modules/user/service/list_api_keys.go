@@ -0,0 +1,46 @@
+package userservice
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+)
+
+// ListAPIKeysQuery đại diện cho query lấy danh sách API key của user
+type ListAPIKeysQuery struct {
+	UserID uuid.UUID
+}
+
+// IListAPIKeysRepo interface cho repository operations cần thiết
+type IListAPIKeysRepo interface {
+	ListActiveAPIKeys(ctx context.Context, userID uuid.UUID, now time.Time) ([]*usermodel.APIKey, error)
+}
+
+// ListAPIKeysQueryHandler trả về các API key còn hiệu lực của user (không bao gồm khóa)
+type ListAPIKeysQueryHandler struct {
+	repo IListAPIKeysRepo
+}
+
+// NewListAPIKeysQueryHandler khởi tạo handler mới
+func NewListAPIKeysQueryHandler(repo IListAPIKeysRepo) *ListAPIKeysQueryHandler {
+	return &ListAPIKeysQueryHandler{repo: repo}
+}
+
+// Execute lấy danh sách API key
+func (hdl *ListAPIKeysQueryHandler) Execute(ctx context.Context, query *ListAPIKeysQuery) ([]*usermodel.APIKeyResponse, error) {
+	keys, err := hdl.repo.ListActiveAPIKeys(ctx, query.UserID, time.Now())
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	result := make([]*usermodel.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		result[i] = key.ToResponse()
+	}
+
+	return result, nil
+}
@@ -0,0 +1,45 @@
+package userservice
+
+import (
+	"context"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+)
+
+// ListAuditEventsQuery đại diện cho query admin tra cứu sự kiện audit
+type ListAuditEventsQuery struct {
+	datatype.Paging
+	Filter usermodel.AuditEventFilter
+}
+
+// IListAuditEventsRepo interface cho repository operations cần thiết, Total của paging được cập nhật theo filter
+type IListAuditEventsRepo interface {
+	ListAuditEvents(ctx context.Context, filter *usermodel.AuditEventFilter, paging *datatype.Paging) ([]*usermodel.AuditEvent, error)
+}
+
+// ListAuditEventsQueryHandler xử lý query lấy danh sách sự kiện audit có phân trang
+type ListAuditEventsQueryHandler struct {
+	repo IListAuditEventsRepo
+}
+
+// NewListAuditEventsQueryHandler khởi tạo handler mới
+func NewListAuditEventsQueryHandler(repo IListAuditEventsRepo) *ListAuditEventsQueryHandler {
+	return &ListAuditEventsQueryHandler{repo: repo}
+}
+
+// Execute trả về một trang sự kiện audit, query.Paging được chuẩn hóa và cập nhật Total
+func (hdl *ListAuditEventsQueryHandler) Execute(ctx context.Context, query *ListAuditEventsQuery) ([]*usermodel.AuditEvent, error) {
+	query.Paging.Process()
+
+	if query.Filter.From != nil && query.Filter.To != nil && !query.Filter.From.Before(*query.Filter.To) {
+		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrInvalidTimeRange.Error())
+	}
+
+	events, err := hdl.repo.ListAuditEvents(ctx, &query.Filter, &query.Paging)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	return events, nil
+}
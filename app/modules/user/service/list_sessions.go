@@ -0,0 +1,48 @@
+package userservice
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+)
+
+// ListSessionsQuery đại diện cho query lấy danh sách phiên đăng nhập của user
+type ListSessionsQuery struct {
+	UserID uuid.UUID
+	// CurrentSessionID là phiên của token đang gọi API, rỗng khi xác thực bằng API key
+	CurrentSessionID string
+}
+
+// IListSessionsRepo interface cho repository operations cần thiết
+type IListSessionsRepo interface {
+	ListActiveSessions(ctx context.Context, userID uuid.UUID, now time.Time) ([]*usermodel.Session, error)
+}
+
+// ListSessionsQueryHandler trả về các phiên đăng nhập còn hiệu lực của user
+type ListSessionsQueryHandler struct {
+	repo IListSessionsRepo
+}
+
+// NewListSessionsQueryHandler khởi tạo handler mới
+func NewListSessionsQueryHandler(repo IListSessionsRepo) *ListSessionsQueryHandler {
+	return &ListSessionsQueryHandler{repo: repo}
+}
+
+// Execute lấy danh sách phiên, đánh dấu phiên hiện tại
+func (hdl *ListSessionsQueryHandler) Execute(ctx context.Context, query *ListSessionsQuery) ([]*usermodel.SessionResponse, error) {
+	sessions, err := hdl.repo.ListActiveSessions(ctx, query.UserID, time.Now())
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	result := make([]*usermodel.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		result[i] = session.ToResponse(session.ID.String() == query.CurrentSessionID)
+	}
+
+	return result, nil
+}
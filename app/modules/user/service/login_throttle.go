@@ -0,0 +1,158 @@
+package userservice
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+)
+
+// LoginThrottleConfig cấu hình chống dò mật khẩu khi đăng nhập
+type LoginThrottleConfig struct {
+	// Số lần sai liên tiếp trước khi khóa tạm thời theo email / theo IP
+	MaxAccountFailures int
+	MaxIPFailures      int
+	// Lần sai cách lần trước quá FailureWindow thì bộ đếm bắt đầu lại
+	FailureWindow   time.Duration
+	LockoutDuration time.Duration
+	// Sau FreeAttempts lần sai, mỗi lần thử phải chờ BaseDelay * 2^(n-FreeAttempts-1), tối đa MaxDelay
+	FreeAttempts int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+}
+
+// ILoginThrottleRepo interface cho repository operations cần thiết
+type ILoginThrottleRepo interface {
+	FindLoginFailures(ctx context.Context, email string, ip string) ([]usermodel.LoginFailure, error)
+	RecordLoginFailure(ctx context.Context, scope string, identifier string, now time.Time, window time.Duration) (int, error)
+	LockLoginFailure(ctx context.Context, scope string, identifier string, lockedUntil time.Time) error
+	ClearLoginFailures(ctx context.Context, scope string, identifier string) error
+}
+
+// ILoginThrottle kiểm soát số lần đăng nhập sai theo email và theo IP
+type ILoginThrottle interface {
+	Check(ctx context.Context, email string, ip string, now time.Time) error
+	RecordFailure(ctx context.Context, email string, ip string, now time.Time) (bool, error)
+	RecordSuccess(ctx context.Context, email string) error
+	Unlock(ctx context.Context, email string) error
+}
+
+// LoginThrottle đếm số lần đăng nhập sai theo email và IP, bắt chờ tăng dần rồi khóa tạm thời khi vượt ngưỡng.
+// Lượt khóa tự hết hạn sau LockoutDuration, admin có thể mở khóa sớm.
+type LoginThrottle struct {
+	repo   ILoginThrottleRepo
+	config LoginThrottleConfig
+}
+
+// NewLoginThrottle khởi tạo LoginThrottle mới
+func NewLoginThrottle(repo ILoginThrottleRepo, config LoginThrottleConfig) *LoginThrottle {
+	return &LoginThrottle{repo: repo, config: config}
+}
+
+// Check trả về lỗi 429 nếu email hoặc IP đang bị khóa hoặc chưa hết thời gian chờ giữa hai lần thử.
+// Email chưa đăng ký cũng được đếm như email đã đăng ký nên phản hồi không tiết lộ email nào tồn tại.
+func (t *LoginThrottle) Check(ctx context.Context, email string, ip string, now time.Time) error {
+	failures, err := t.repo.FindLoginFailures(ctx, normalizeLoginEmail(email), ip)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	var retryAfter time.Duration
+	locked := false
+	for _, failure := range failures {
+		if failure.IsLocked(now) {
+			locked = true
+			retryAfter = max(retryAfter, failure.LockedUntil.Sub(now))
+			continue
+		}
+		if failure.LockedUntil != nil || now.Sub(failure.LastFailedAt) > t.config.FailureWindow {
+			continue
+		}
+		if wait := failure.LastFailedAt.Add(t.delay(failure.FailedCount)).Sub(now); wait > 0 {
+			retryAfter = max(retryAfter, wait)
+		}
+	}
+
+	if retryAfter <= 0 {
+		return nil
+	}
+
+	message := usermodel.ErrLoginThrottled.Error()
+	if locked {
+		message = usermodel.ErrLoginLocked.Error()
+	}
+	return datatype.ErrTooManyRequests.WithError(message).
+		WithDetail("retry_after", int(math.Ceil(retryAfter.Seconds())))
+}
+
+// RecordFailure ghi nhận một lần đăng nhập sai, trả về true nếu email vừa bị khóa
+func (t *LoginThrottle) RecordFailure(ctx context.Context, email string, ip string, now time.Time) (bool, error) {
+	accountLocked, err := t.recordFailure(ctx, usermodel.LoginFailureScopeAccount, normalizeLoginEmail(email), t.config.MaxAccountFailures, now)
+	if err != nil {
+		return false, err
+	}
+
+	if ip != "" {
+		if _, err := t.recordFailure(ctx, usermodel.LoginFailureScopeIP, ip, t.config.MaxIPFailures, now); err != nil {
+			return false, err
+		}
+	}
+
+	return accountLocked, nil
+}
+
+func (t *LoginThrottle) recordFailure(ctx context.Context, scope string, identifier string, threshold int, now time.Time) (bool, error) {
+	failedCount, err := t.repo.RecordLoginFailure(ctx, scope, identifier, now, t.config.FailureWindow)
+	if err != nil {
+		return false, err
+	}
+
+	if threshold <= 0 || failedCount != threshold {
+		return false, nil
+	}
+
+	if err := t.repo.LockLoginFailure(ctx, scope, identifier, now.Add(t.config.LockoutDuration)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RecordSuccess xóa bộ đếm của email sau khi đăng nhập thành công.
+// Bộ đếm theo IP giữ nguyên để một tài khoản hợp lệ không xóa được dấu vết dò mật khẩu từ cùng IP.
+func (t *LoginThrottle) RecordSuccess(ctx context.Context, email string) error {
+	return t.repo.ClearLoginFailures(ctx, usermodel.LoginFailureScopeAccount, normalizeLoginEmail(email))
+}
+
+// Unlock mở khóa email trước khi lượt khóa hết hạn
+func (t *LoginThrottle) Unlock(ctx context.Context, email string) error {
+	return t.repo.ClearLoginFailures(ctx, usermodel.LoginFailureScopeAccount, normalizeLoginEmail(email))
+}
+
+// delay tính thời gian chờ bắt buộc sau failedCount lần sai liên tiếp
+func (t *LoginThrottle) delay(failedCount int) time.Duration {
+	exponent := failedCount - t.config.FreeAttempts - 1
+	if exponent < 0 || t.config.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := t.config.BaseDelay
+	for i := 0; i < exponent && delay < t.config.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, t.config.MaxDelay)
+}
+
+// isLoginThrottled kiểm tra lỗi trả về từ ILoginThrottle.Check có phải do bị chặn (429) hay không
+func isLoginThrottled(err error) bool {
+	var appErr *datatype.DefaultError
+	return errors.As(err, &appErr) && appErr.StatusCode() == http.StatusTooManyRequests
+}
+
+func normalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
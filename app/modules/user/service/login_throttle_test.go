@@ -0,0 +1,191 @@
+package userservice
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+)
+
+// fakeLoginThrottleRepo lưu bộ đếm trong bộ nhớ, giống cách repository GORM đặt lại bộ đếm khi quá window
+type fakeLoginThrottleRepo struct {
+	failures map[string]*usermodel.LoginFailure
+}
+
+func newFakeLoginThrottleRepo() *fakeLoginThrottleRepo {
+	return &fakeLoginThrottleRepo{failures: map[string]*usermodel.LoginFailure{}}
+}
+
+func (r *fakeLoginThrottleRepo) FindLoginFailures(_ context.Context, email string, ip string) ([]usermodel.LoginFailure, error) {
+	var result []usermodel.LoginFailure
+	for _, key := range []string{usermodel.LoginFailureScopeAccount + ":" + email, usermodel.LoginFailureScopeIP + ":" + ip} {
+		if failure, ok := r.failures[key]; ok {
+			result = append(result, *failure)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeLoginThrottleRepo) RecordLoginFailure(_ context.Context, scope string, identifier string, now time.Time, window time.Duration) (int, error) {
+	key := scope + ":" + identifier
+	failure, ok := r.failures[key]
+	if !ok || now.Sub(failure.LastFailedAt) > window || (failure.LockedUntil != nil && !failure.IsLocked(now)) {
+		failure = &usermodel.LoginFailure{Scope: scope, Identifier: identifier}
+		r.failures[key] = failure
+	}
+	failure.FailedCount++
+	failure.LastFailedAt = now
+	return failure.FailedCount, nil
+}
+
+func (r *fakeLoginThrottleRepo) LockLoginFailure(_ context.Context, scope string, identifier string, lockedUntil time.Time) error {
+	r.failures[scope+":"+identifier].LockedUntil = &lockedUntil
+	return nil
+}
+
+func (r *fakeLoginThrottleRepo) ClearLoginFailures(_ context.Context, scope string, identifier string) error {
+	delete(r.failures, scope+":"+identifier)
+	return nil
+}
+
+func testLoginThrottleConfig() LoginThrottleConfig {
+	return LoginThrottleConfig{
+		MaxAccountFailures: 5,
+		MaxIPFailures:      20,
+		FailureWindow:      15 * time.Minute,
+		LockoutDuration:    15 * time.Minute,
+		FreeAttempts:       2,
+		BaseDelay:          time.Second,
+		MaxDelay:           5 * time.Second,
+	}
+}
+
+func TestLoginThrottleDelay(t *testing.T) {
+	throttle := NewLoginThrottle(nil, testLoginThrottleConfig())
+
+	tests := []struct {
+		failedCount int
+		want        time.Duration
+	}{
+		{failedCount: 0, want: 0},
+		{failedCount: 1, want: 0},
+		{failedCount: 2, want: 0},
+		{failedCount: 3, want: time.Second},
+		{failedCount: 4, want: 2 * time.Second},
+		{failedCount: 5, want: 4 * time.Second},
+		{failedCount: 6, want: 5 * time.Second},
+		{failedCount: 100, want: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := throttle.delay(tt.failedCount); got != tt.want {
+			t.Errorf("delay(%d) = %v, want %v", tt.failedCount, got, tt.want)
+		}
+	}
+}
+
+func TestLoginThrottleDelayDisabled(t *testing.T) {
+	config := testLoginThrottleConfig()
+	config.BaseDelay = 0
+	throttle := NewLoginThrottle(nil, config)
+
+	if got := throttle.delay(10); got != 0 {
+		t.Errorf("delay(10) with BaseDelay 0 = %v, want 0", got)
+	}
+}
+
+func TestLoginThrottleLockout(t *testing.T) {
+	ctx := context.Background()
+	config := testLoginThrottleConfig()
+	throttle := NewLoginThrottle(newFakeLoginThrottleRepo(), config)
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	for i := 1; i <= config.MaxAccountFailures; i++ {
+		// Mỗi lần thử cách lần trước đủ MaxDelay nên không bị chặn vì thời gian chờ
+		now = now.Add(config.MaxDelay)
+		if err := throttle.Check(ctx, "User@Example.com ", "10.0.0.1", now); err != nil {
+			t.Fatalf("attempt %d: Check() error = %v, want nil", i, err)
+		}
+
+		locked, err := throttle.RecordFailure(ctx, "user@example.com", "10.0.0.1", now)
+		if err != nil {
+			t.Fatalf("attempt %d: RecordFailure() error = %v", i, err)
+		}
+		if wantLocked := i == config.MaxAccountFailures; locked != wantLocked {
+			t.Fatalf("attempt %d: RecordFailure() locked = %v, want %v", i, locked, wantLocked)
+		}
+	}
+
+	err := throttle.Check(ctx, "user@example.com", "10.0.0.2", now.Add(config.LockoutDuration-time.Second))
+	assertTooManyRequests(t, err, usermodel.ErrLoginLocked)
+
+	if err := throttle.Check(ctx, "user@example.com", "10.0.0.2", now.Add(config.LockoutDuration+time.Second)); err != nil {
+		t.Errorf("Check() after lockout = %v, want nil", err)
+	}
+}
+
+func TestLoginThrottleProgressiveDelay(t *testing.T) {
+	ctx := context.Background()
+	config := testLoginThrottleConfig()
+	throttle := NewLoginThrottle(newFakeLoginThrottleRepo(), config)
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < config.FreeAttempts+1; i++ {
+		if _, err := throttle.RecordFailure(ctx, "user@example.com", "", now); err != nil {
+			t.Fatalf("RecordFailure() error = %v", err)
+		}
+	}
+
+	// Lần sai thứ FreeAttempts+1 bắt chờ BaseDelay
+	err := throttle.Check(ctx, "user@example.com", "", now.Add(config.BaseDelay/2))
+	assertTooManyRequests(t, err, usermodel.ErrLoginThrottled)
+
+	if err := throttle.Check(ctx, "user@example.com", "", now.Add(config.BaseDelay)); err != nil {
+		t.Errorf("Check() after delay = %v, want nil", err)
+	}
+
+	// Đăng nhập thành công xóa bộ đếm theo email
+	if err := throttle.RecordSuccess(ctx, "USER@example.com"); err != nil {
+		t.Fatalf("RecordSuccess() error = %v", err)
+	}
+	if err := throttle.Check(ctx, "user@example.com", "", now); err != nil {
+		t.Errorf("Check() after success = %v, want nil", err)
+	}
+}
+
+func TestLoginThrottleFailureWindow(t *testing.T) {
+	ctx := context.Background()
+	config := testLoginThrottleConfig()
+	throttle := NewLoginThrottle(newFakeLoginThrottleRepo(), config)
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < config.FreeAttempts+3; i++ {
+		if _, err := throttle.RecordFailure(ctx, "user@example.com", "", now); err != nil {
+			t.Fatalf("RecordFailure() error = %v", err)
+		}
+	}
+
+	// Lần sai cuối đã quá FailureWindow thì không còn phải chờ
+	if err := throttle.Check(ctx, "user@example.com", "", now.Add(config.FailureWindow+time.Second)); err != nil {
+		t.Errorf("Check() after failure window = %v, want nil", err)
+	}
+}
+
+func assertTooManyRequests(t *testing.T, err error, want error) {
+	t.Helper()
+
+	var appErr *datatype.DefaultError
+	if !errors.As(err, &appErr) || appErr.StatusCode() != http.StatusTooManyRequests {
+		t.Fatalf("error = %v, want 429", err)
+	}
+	if !isLoginThrottled(err) {
+		t.Errorf("isLoginThrottled(%v) = false, want true", err)
+	}
+	if appErr.Error() != want.Error() {
+		t.Errorf("error message = %q, want %q", appErr.Error(), want.Error())
+	}
+}
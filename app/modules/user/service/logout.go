@@ -0,0 +1,159 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// LogoutCommand đại diện cho command đăng xuất phiên hiện tại
+type LogoutCommand struct {
+	UserID    uuid.UUID
+	TokenID   string
+	SessionID string
+	IPAddress string
+	UserAgent string
+	Dto       usermodel.LogoutForm
+}
+
+// LogoutAllCommand đại diện cho command đăng xuất khỏi mọi thiết bị
+type LogoutAllCommand struct {
+	UserID    uuid.UUID
+	IPAddress string
+	UserAgent string
+}
+
+// ITokenRevoker interface thu hồi access token
+type ITokenRevoker interface {
+	RevokeToken(ctx context.Context, userID uuid.UUID, jti string) error
+	RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error
+}
+
+// ILogoutRepo interface cho repository operations cần thiết
+type ILogoutRepo interface {
+	FindRefreshTokenByHash(ctx context.Context, tokenHash string) (*usermodel.RefreshToken, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeSession(ctx context.Context, userID uuid.UUID, id uuid.UUID) (bool, error)
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// LogoutCommandHandler xử lý đăng xuất và thu hồi token phía server
+type LogoutCommandHandler struct {
+	repo         ILogoutRepo
+	tokenRevoker ITokenRevoker
+}
+
+// NewLogoutCommandHandler khởi tạo handler mới
+func NewLogoutCommandHandler(repo ILogoutRepo, tokenRevoker ITokenRevoker) *LogoutCommandHandler {
+	return &LogoutCommandHandler{repo: repo, tokenRevoker: tokenRevoker}
+}
+
+// Execute thu hồi access token hiện tại, phiên đăng nhập của token và refresh token family đi kèm (nếu có)
+func (hdl *LogoutCommandHandler) Execute(ctx context.Context, cmd *LogoutCommand) error {
+	if cmd.TokenID == "" {
+		return datatype.ErrBadRequest.WithError("Token ID is required")
+	}
+
+	if err := hdl.tokenRevoker.RevokeToken(ctx, cmd.UserID, cmd.TokenID); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if sessionID, err := uuid.Parse(cmd.SessionID); err == nil {
+		if _, err := hdl.repo.RevokeSession(ctx, cmd.UserID, sessionID); err != nil {
+			return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+		}
+	}
+
+	if err := hdl.revokeRefreshToken(ctx, cmd); err != nil {
+		return err
+	}
+
+	metadata, _ := json.Marshal(map[string]string{"session_id": cmd.SessionID})
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &cmd.UserID,
+		ActorID:   &cmd.UserID,
+		Event:     usermodel.AuditEventLogout,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: time.Now(),
+	})
+
+	return nil
+}
+
+// revokeRefreshToken thu hồi refresh token family mà client gửi kèm khi đăng xuất (nếu có)
+func (hdl *LogoutCommandHandler) revokeRefreshToken(ctx context.Context, cmd *LogoutCommand) error {
+	if cmd.Dto.RefreshToken == "" {
+		return nil
+	}
+
+	token, err := hdl.repo.FindRefreshTokenByHash(ctx, shared.HashToken(cmd.Dto.RefreshToken))
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil
+		}
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	// Không cho phép thu hồi refresh token của user khác
+	if token.UserID != cmd.UserID {
+		return nil
+	}
+
+	if err := hdl.repo.RevokeRefreshTokenFamily(ctx, token.FamilyID); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	return nil
+}
+
+// ILogoutAllRepo interface cho repository operations cần thiết
+type ILogoutAllRepo interface {
+	RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// LogoutAllCommandHandler xử lý đăng xuất khỏi mọi thiết bị
+type LogoutAllCommandHandler struct {
+	repo         ILogoutAllRepo
+	tokenRevoker ITokenRevoker
+}
+
+// NewLogoutAllCommandHandler khởi tạo handler mới
+func NewLogoutAllCommandHandler(repo ILogoutAllRepo, tokenRevoker ITokenRevoker) *LogoutAllCommandHandler {
+	return &LogoutAllCommandHandler{repo: repo, tokenRevoker: tokenRevoker}
+}
+
+// Execute thu hồi mọi access token và refresh token của user
+func (hdl *LogoutAllCommandHandler) Execute(ctx context.Context, cmd *LogoutAllCommand) error {
+	if cmd.UserID == uuid.Nil {
+		return datatype.ErrBadRequest.WithError("User ID is required")
+	}
+
+	if err := hdl.repo.RevokeUserRefreshTokens(ctx, cmd.UserID); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if err := hdl.tokenRevoker.RevokeAllUserTokens(ctx, cmd.UserID); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &cmd.UserID,
+		ActorID:   &cmd.UserID,
+		Event:     usermodel.AuditEventLogoutAll,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		CreatedAt: time.Now(),
+	})
+
+	return nil
+}
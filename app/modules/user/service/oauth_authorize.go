@@ -0,0 +1,104 @@
+package userservice
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"log"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+)
+
+// OAuthAuthorizeCommand đại diện cho command bắt đầu đăng nhập qua identity provider
+type OAuthAuthorizeCommand struct {
+	Provider string
+}
+
+// OAuthAuthorizeResult chứa URL để client chuyển hướng người dùng tới provider.
+// StateHash được controller lưu vào cookie để gắn state với trình duyệt bắt đầu đăng nhập.
+type OAuthAuthorizeResult struct {
+	AuthorizationURL string `json:"authorizationUrl"`
+	StateHash        string `json:"-"`
+}
+
+// IOAuthProvider interface client OAuth2/OIDC của một identity provider
+type IOAuthProvider interface {
+	AuthCodeURL(state string, nonce string, codeChallenge string) string
+	FetchIdentity(ctx context.Context, code string, codeVerifier string, nonce string) (*usermodel.OAuthIdentity, error)
+	UserType() usermodel.UserType
+}
+
+// IOAuthAuthorizeRepo interface cho repository operations cần thiết
+type IOAuthAuthorizeRepo interface {
+	InsertOAuthState(ctx context.Context, state *usermodel.OAuthState) error
+	DeleteExpiredOAuthStates(ctx context.Context, now time.Time) (int64, error)
+}
+
+// OAuthAuthorizeCommandHandler tạo state, nonce, PKCE verifier và URL đăng nhập của provider
+type OAuthAuthorizeCommandHandler struct {
+	repo      IOAuthAuthorizeRepo
+	providers map[string]IOAuthProvider
+	stateTTL  time.Duration
+}
+
+// NewOAuthAuthorizeCommandHandler khởi tạo handler mới
+func NewOAuthAuthorizeCommandHandler(repo IOAuthAuthorizeRepo, providers map[string]IOAuthProvider, stateTTL time.Duration) *OAuthAuthorizeCommandHandler {
+	return &OAuthAuthorizeCommandHandler{repo: repo, providers: providers, stateTTL: stateTTL}
+}
+
+// Execute lưu state phía server và trả về URL chuyển hướng tới provider
+func (hdl *OAuthAuthorizeCommandHandler) Execute(ctx context.Context, cmd *OAuthAuthorizeCommand) (*OAuthAuthorizeResult, error) {
+	provider, ok := hdl.providers[cmd.Provider]
+	if !ok {
+		return nil, datatype.ErrNotFound.WithError(usermodel.ErrOAuthProviderNotFound.Error())
+	}
+
+	state, err := shared.RandomStr(32)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	nonce, err := shared.RandomStr(16)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	codeVerifier, err := shared.RandomStr(32)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	// Dọn các state đã hết hạn mà người dùng bỏ dở, lỗi ở đây không ảnh hưởng tới lần đăng nhập hiện tại
+	if _, err := hdl.repo.DeleteExpiredOAuthStates(ctx, time.Now()); err != nil {
+		log.Printf("Error deleting expired oauth states: %v", err)
+	}
+
+	newId, _ := uuid.NewV7()
+	now := time.Now()
+	oauthState := &usermodel.OAuthState{
+		ID:           newId,
+		Provider:     cmd.Provider,
+		StateHash:    shared.HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    now.Add(hdl.stateTTL),
+		CreatedAt:    now,
+	}
+	if err := hdl.repo.InsertOAuthState(ctx, oauthState); err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	return &OAuthAuthorizeResult{
+		AuthorizationURL: provider.AuthCodeURL(state, nonce, pkceChallenge(codeVerifier)),
+		StateHash:        oauthState.StateHash,
+	}, nil
+}
+
+// pkceChallenge tính code_challenge theo phương thức S256 (RFC 7636)
+func pkceChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
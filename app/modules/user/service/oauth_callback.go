@@ -0,0 +1,214 @@
+package userservice
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// OAuthCallbackCommand đại diện cho command xử lý redirect từ identity provider.
+// BrowserStateHash là giá trị băm của state lưu trong cookie của trình duyệt lúc bắt đầu đăng nhập.
+type OAuthCallbackCommand struct {
+	Provider         string
+	IPAddress        string
+	UserAgent        string
+	BrowserStateHash string
+	Dto              usermodel.OAuthCallbackForm
+}
+
+// IOAuthCallbackRepo interface cho repository operations cần thiết
+type IOAuthCallbackRepo interface {
+	ConsumeOAuthState(ctx context.Context, stateHash string) (*usermodel.OAuthState, error)
+	FindUserIdentity(ctx context.Context, provider string, subject string) (*usermodel.UserIdentity, error)
+	InsertUserIdentity(ctx context.Context, identity *usermodel.UserIdentity) error
+	InsertUserWithIdentity(ctx context.Context, user *usermodel.User, identity *usermodel.UserIdentity) error
+	FindByEmail(ctx context.Context, email string) (*usermodel.User, error)
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	ClaimPendingUser(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// OAuthCallbackCommandHandler đổi authorization code, liên kết hoặc tạo user và cấp token
+type OAuthCallbackCommandHandler struct {
+	repo            IOAuthCallbackRepo
+	providers       map[string]IOAuthProvider
+	tokenPairIssuer ITokenPairIssuer
+	loginChallenge  ILoginChallenge
+}
+
+// NewOAuthCallbackCommandHandler khởi tạo handler mới
+func NewOAuthCallbackCommandHandler(repo IOAuthCallbackRepo, providers map[string]IOAuthProvider, tokenPairIssuer ITokenPairIssuer, loginChallenge ILoginChallenge) *OAuthCallbackCommandHandler {
+	return &OAuthCallbackCommandHandler{repo: repo, providers: providers, tokenPairIssuer: tokenPairIssuer, loginChallenge: loginChallenge}
+}
+
+// Execute kiểm tra state, lấy thông tin user từ provider và đăng nhập.
+// Tài khoản bên ngoài chưa liên kết sẽ được gắn vào user có cùng email (chỉ khi provider xác nhận email),
+// nếu chưa có user thì tạo mới với loại user của provider.
+func (hdl *OAuthCallbackCommandHandler) Execute(ctx context.Context, cmd *OAuthCallbackCommand) (*AuthenticateResult, error) {
+	provider, ok := hdl.providers[cmd.Provider]
+	if !ok {
+		return nil, datatype.ErrNotFound.WithError(usermodel.ErrOAuthProviderNotFound.Error())
+	}
+
+	// State phải khớp cookie của trình duyệt đã bắt đầu đăng nhập, tránh kẻ tấn công gửi URL callback
+	// (code + state) của chính họ để nạn nhân đăng nhập vào tài khoản của kẻ tấn công (login CSRF)
+	stateHash := shared.HashToken(cmd.Dto.State)
+	if cmd.BrowserStateHash == "" || subtle.ConstantTimeCompare([]byte(stateHash), []byte(cmd.BrowserStateHash)) != 1 {
+		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrOAuthStateInvalid.Error())
+	}
+
+	// State bị xóa ngay cả khi provider trả về lỗi để không thể dùng lại
+	state, err := hdl.repo.ConsumeOAuthState(ctx, stateHash)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, datatype.ErrBadRequest.WithError(usermodel.ErrOAuthStateInvalid.Error())
+		}
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	now := time.Now()
+	if state.Provider != cmd.Provider || state.IsExpired(now) {
+		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrOAuthStateInvalid.Error())
+	}
+
+	client := SessionClient{IPAddress: cmd.IPAddress, UserAgent: cmd.UserAgent}
+	attempt := loginAttempt{Method: usermodel.LoginMethodOAuth, Provider: cmd.Provider, Client: client, Now: now}
+
+	if cmd.Dto.Error != "" || cmd.Dto.Code == "" {
+		recordLoginFailed(ctx, hdl.repo, nil, "", usermodel.LoginFailureReasonOAuthFailed, attempt)
+		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrOAuthLoginFailed.Error()).
+			WithDebug(cmd.Dto.Error + ": " + cmd.Dto.ErrorDescription)
+	}
+
+	identity, err := provider.FetchIdentity(ctx, cmd.Dto.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		recordLoginFailed(ctx, hdl.repo, nil, "", usermodel.LoginFailureReasonOAuthFailed, attempt)
+		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrOAuthLoginFailed.Error()).WithWrap(err).WithDebug(err.Error())
+	}
+
+	user, err := hdl.resolveUser(ctx, cmd.Provider, provider.UserType(), identity)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Status == usermodel.StatusDeleted || user.Status == usermodel.StatusBanned {
+		recordLoginFailed(ctx, hdl.repo, user, "", usermodel.LoginFailureReasonBannedOrDeleted, attempt)
+		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrUserBannedOrDeleted.Error())
+	}
+
+	// Social login không thay thế được bước 2FA của tài khoản đã bật
+	if user.IsTwoFactorEnabled() {
+		return hdl.loginChallenge.Issue(user), nil
+	}
+
+	result, err := hdl.tokenPairIssuer.StartSession(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	recordLoginSucceeded(ctx, hdl.repo, user, attempt)
+
+	return result, nil
+}
+
+// resolveUser tìm user đã liên kết, liên kết theo email đã xác minh hoặc tạo user mới
+func (hdl *OAuthCallbackCommandHandler) resolveUser(ctx context.Context, providerName string, userType usermodel.UserType, identity *usermodel.OAuthIdentity) (*usermodel.User, error) {
+	linked, err := hdl.repo.FindUserIdentity(ctx, providerName, identity.Subject)
+	if err == nil {
+		user, err := hdl.repo.FindById(ctx, linked.UserID)
+		if err != nil {
+			return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+		}
+		return user, nil
+	}
+	if !errors.Is(err, datatype.ErrRecordNotFound) {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	// Chỉ liên kết hoặc tạo tài khoản khi provider xác nhận quyền sở hữu email,
+	// tránh chiếm tài khoản có sẵn bằng một email chưa xác minh
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrOAuthEmailNotVerified.Error())
+	}
+
+	newId, _ := uuid.NewV7()
+	userIdentity := &usermodel.UserIdentity{
+		ID:        newId,
+		Provider:  providerName,
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+		CreatedAt: time.Now(),
+	}
+
+	user, err := hdl.repo.FindByEmail(ctx, identity.Email)
+	if err == nil {
+		// Provider đã xác nhận email nên tài khoản đang chờ xác minh được kích hoạt luôn
+		if user.Status == usermodel.StatusPending {
+			if err := hdl.repo.ClaimPendingUser(ctx, user.ID, userIdentity.CreatedAt); err != nil {
+				return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+			}
+			user.Status = usermodel.StatusActive
+		}
+
+		userIdentity.UserID = user.ID
+		if err := hdl.repo.InsertUserIdentity(ctx, userIdentity); err != nil {
+			return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+		}
+		return user, nil
+	}
+	if !errors.Is(err, datatype.ErrRecordNotFound) {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	user = newOAuthUser(userType, identity)
+	userIdentity.UserID = user.ID
+	if err := hdl.repo.InsertUserWithIdentity(ctx, user, userIdentity); err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	return user, nil
+}
+
+// newOAuthUser tạo user đăng nhập bằng provider bên ngoài, không có password nên không thể đăng nhập bằng email/password
+func newOAuthUser(userType usermodel.UserType, identity *usermodel.OAuthIdentity) *usermodel.User {
+	salt, _ := shared.RandomStr(16)
+	newId, _ := uuid.NewV7()
+	now := time.Now().In(time.FixedZone("Asia/Ho_Chi_Minh", 7*3600))
+
+	firstName := identity.FirstName
+	if firstName == "" {
+		firstName = strings.Split(identity.Email, "@")[0]
+	}
+
+	return &usermodel.User{
+		ID:              newId,
+		Email:           identity.Email,
+		FirstName:       truncateRunes(firstName, 50),
+		LastName:        truncateRunes(identity.LastName, 50),
+		Salt:            salt,
+		Status:          usermodel.StatusActive,
+		Type:            userType,
+		Role:            usermodel.RoleUser,
+		CreatedAt:       &now,
+		CreatedBy:       "system",
+		UpdatedAt:       &now,
+		UpdatedBy:       "system",
+		EmailVerifiedAt: &now,
+	}
+}
+
+// truncateRunes cắt chuỗi theo số ký tự để vừa độ dài cột
+func truncateRunes(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max])
+}
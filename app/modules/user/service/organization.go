@@ -0,0 +1,178 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// IOrganizationMemberFinder interface tìm tư cách thành viên của user trong tổ chức
+type IOrganizationMemberFinder interface {
+	FindOrganizationMember(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID) (*usermodel.OrganizationMember, error)
+}
+
+// requireOrganizationRole kiểm tra user là thành viên của tổ chức với một trong các role chỉ định (không chỉ định thì mọi role).
+// Người ngoài tổ chức nhận lỗi not found để không lộ sự tồn tại của tổ chức.
+func requireOrganizationRole(ctx context.Context, repo IOrganizationMemberFinder, organizationID uuid.UUID, userID uuid.UUID, roles ...string) (*usermodel.OrganizationMember, error) {
+	member, err := repo.FindOrganizationMember(ctx, organizationID, userID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, datatype.ErrNotFound.WithError(usermodel.ErrOrganizationNotFound.Error())
+		}
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if len(roles) > 0 && !slices.Contains(roles, member.Role) {
+		return nil, datatype.ErrForbidden.WithError(usermodel.ErrOrgPermissionDenied.Error())
+	}
+
+	return member, nil
+}
+
+// CreateOrganizationCommand đại diện cho command user tạo tổ chức mới
+type CreateOrganizationCommand struct {
+	UserID    uuid.UUID
+	IPAddress string
+	UserAgent string
+	Dto       usermodel.CreateOrganizationForm
+}
+
+// ICreateOrganizationRepo interface cho repository operations cần thiết
+type ICreateOrganizationRepo interface {
+	CreateOrganization(ctx context.Context, organization *usermodel.Organization, owner *usermodel.OrganizationMember) error
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// CreateOrganizationCommandHandler tạo tổ chức mới, người tạo trở thành owner
+type CreateOrganizationCommandHandler struct {
+	repo ICreateOrganizationRepo
+}
+
+// NewCreateOrganizationCommandHandler khởi tạo handler mới
+func NewCreateOrganizationCommandHandler(repo ICreateOrganizationRepo) *CreateOrganizationCommandHandler {
+	return &CreateOrganizationCommandHandler{repo: repo}
+}
+
+// Execute tạo tổ chức. Phiên hiện tại không tự chuyển sang tổ chức mới, user chọn tổ chức bằng API switch.
+func (hdl *CreateOrganizationCommandHandler) Execute(ctx context.Context, cmd *CreateOrganizationCommand) (*usermodel.OrganizationResponse, error) {
+	now := time.Now()
+	newId, _ := uuid.NewV7()
+
+	organization := &usermodel.Organization{
+		ID:        newId,
+		Name:      strings.TrimSpace(cmd.Dto.Name),
+		CreatedBy: actorFromContext(ctx, cmd.UserID),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	owner := &usermodel.OrganizationMember{
+		OrganizationID: organization.ID,
+		UserID:         cmd.UserID,
+		Role:           datatype.OrgRoleOwner,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := hdl.repo.CreateOrganization(ctx, organization, owner); err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	metadata, _ := json.Marshal(map[string]string{"organization_id": organization.ID.String()})
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &cmd.UserID,
+		ActorID:   &cmd.UserID,
+		Event:     usermodel.AuditEventOrganizationCreated,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: now,
+	})
+
+	userOrganization := &usermodel.UserOrganization{Organization: *organization, Role: owner.Role, JoinedAt: now}
+	return userOrganization.ToResponse(false), nil
+}
+
+// ListOrganizationsQuery đại diện cho query lấy các tổ chức của user
+type ListOrganizationsQuery struct {
+	UserID uuid.UUID
+	// CurrentOrganizationID là tổ chức đang hoạt động của token đang gọi API, uuid.Nil nếu chưa chọn
+	CurrentOrganizationID uuid.UUID
+}
+
+// IListOrganizationsRepo interface cho repository operations cần thiết
+type IListOrganizationsRepo interface {
+	ListUserOrganizations(ctx context.Context, userID uuid.UUID) ([]*usermodel.UserOrganization, error)
+}
+
+// ListOrganizationsQueryHandler trả về các tổ chức mà user là thành viên
+type ListOrganizationsQueryHandler struct {
+	repo IListOrganizationsRepo
+}
+
+// NewListOrganizationsQueryHandler khởi tạo handler mới
+func NewListOrganizationsQueryHandler(repo IListOrganizationsRepo) *ListOrganizationsQueryHandler {
+	return &ListOrganizationsQueryHandler{repo: repo}
+}
+
+// Execute lấy danh sách tổ chức, đánh dấu tổ chức đang hoạt động
+func (hdl *ListOrganizationsQueryHandler) Execute(ctx context.Context, query *ListOrganizationsQuery) ([]*usermodel.OrganizationResponse, error) {
+	organizations, err := hdl.repo.ListUserOrganizations(ctx, query.UserID)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	result := make([]*usermodel.OrganizationResponse, len(organizations))
+	for i, organization := range organizations {
+		result[i] = organization.ToResponse(organization.ID == query.CurrentOrganizationID)
+	}
+
+	return result, nil
+}
+
+// ListOrganizationMembersQuery đại diện cho query lấy thành viên của tổ chức
+type ListOrganizationMembersQuery struct {
+	UserID         uuid.UUID
+	OrganizationID uuid.UUID
+}
+
+// IListOrganizationMembersRepo interface cho repository operations cần thiết
+type IListOrganizationMembersRepo interface {
+	IOrganizationMemberFinder
+	ListOrganizationMembers(ctx context.Context, organizationID uuid.UUID) ([]*usermodel.OrganizationMemberDetail, error)
+}
+
+// ListOrganizationMembersQueryHandler trả về thành viên của tổ chức, chỉ thành viên của tổ chức được xem
+type ListOrganizationMembersQueryHandler struct {
+	repo IListOrganizationMembersRepo
+}
+
+// NewListOrganizationMembersQueryHandler khởi tạo handler mới
+func NewListOrganizationMembersQueryHandler(repo IListOrganizationMembersRepo) *ListOrganizationMembersQueryHandler {
+	return &ListOrganizationMembersQueryHandler{repo: repo}
+}
+
+// Execute lấy danh sách thành viên
+func (hdl *ListOrganizationMembersQueryHandler) Execute(ctx context.Context, query *ListOrganizationMembersQuery) ([]*usermodel.OrganizationMemberResponse, error) {
+	if _, err := requireOrganizationRole(ctx, hdl.repo, query.OrganizationID, query.UserID); err != nil {
+		return nil, err
+	}
+
+	members, err := hdl.repo.ListOrganizationMembers(ctx, query.OrganizationID)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	result := make([]*usermodel.OrganizationMemberResponse, len(members))
+	for i, member := range members {
+		result[i] = member.ToResponse()
+	}
+
+	return result, nil
+}
@@ -0,0 +1,233 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared"
+	sharecomponent "fat2fast/ikv/shared/component"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// OrganizationInvitationConfig là cấu hình lời mời tham gia tổ chức
+type OrganizationInvitationConfig struct {
+	LinkURL    string
+	TokenExpIn time.Duration
+}
+
+// InviteOrganizationMemberCommand đại diện cho command owner/admin mời một email tham gia tổ chức
+type InviteOrganizationMemberCommand struct {
+	UserID         uuid.UUID
+	OrganizationID uuid.UUID
+	IPAddress      string
+	UserAgent      string
+	Dto            usermodel.InviteOrganizationMemberForm
+}
+
+// IInviteOrganizationMemberRepo interface cho repository operations cần thiết
+type IInviteOrganizationMemberRepo interface {
+	IOrganizationMemberFinder
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	FindByEmail(ctx context.Context, email string) (*usermodel.User, error)
+	FindOrganization(ctx context.Context, id uuid.UUID) (*usermodel.Organization, error)
+	ReplaceOrganizationInvitation(ctx context.Context, invitation *usermodel.OrganizationInvitation) error
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// InviteOrganizationMemberCommandHandler gửi link mời tham gia tổ chức tới email được mời
+type InviteOrganizationMemberCommandHandler struct {
+	repo   IInviteOrganizationMemberRepo
+	mailer IMailer
+	config OrganizationInvitationConfig
+}
+
+// NewInviteOrganizationMemberCommandHandler khởi tạo handler mới
+func NewInviteOrganizationMemberCommandHandler(repo IInviteOrganizationMemberRepo, mailer IMailer, config OrganizationInvitationConfig) *InviteOrganizationMemberCommandHandler {
+	return &InviteOrganizationMemberCommandHandler{repo: repo, mailer: mailer, config: config}
+}
+
+// Execute tạo lời mời và gửi email. Người được mời chưa cần có tài khoản, lời mời được chấp nhận
+// bởi tài khoản đăng nhập bằng đúng email được mời.
+func (hdl *InviteOrganizationMemberCommandHandler) Execute(ctx context.Context, cmd *InviteOrganizationMemberCommand) (*usermodel.OrganizationInvitationResponse, error) {
+	if _, err := requireOrganizationRole(ctx, hdl.repo, cmd.OrganizationID, cmd.UserID, datatype.OrgRoleOwner, datatype.OrgRoleAdmin); err != nil {
+		return nil, err
+	}
+
+	organization, err := hdl.repo.FindOrganization(ctx, cmd.OrganizationID)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	inviter, err := hdl.repo.FindById(ctx, cmd.UserID)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	email := strings.TrimSpace(cmd.Dto.Email)
+	if invitee, err := hdl.repo.FindByEmail(ctx, email); err == nil {
+		if _, err := hdl.repo.FindOrganizationMember(ctx, organization.ID, invitee.ID); err == nil {
+			return nil, datatype.ErrConflict.WithError(usermodel.ErrAlreadyOrgMember.Error())
+		} else if !errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+		}
+	} else if !errors.Is(err, datatype.ErrRecordNotFound) {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	token, err := shared.RandomStr(32)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	now := time.Now()
+	newId, _ := uuid.NewV7()
+	invitation := &usermodel.OrganizationInvitation{
+		ID:             newId,
+		OrganizationID: organization.ID,
+		Email:          email,
+		Role:           cmd.Dto.Role,
+		TokenHash:      shared.HashToken(token),
+		InvitedBy:      actorFromContext(ctx, inviter.ID),
+		ExpiresAt:      now.Add(hdl.config.TokenExpIn),
+		CreatedAt:      now,
+	}
+	if err := hdl.repo.ReplaceOrganizationInvitation(ctx, invitation); err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	link := fmt.Sprintf("%s?token=%s", hdl.config.LinkURL, url.QueryEscape(token))
+	mail := &sharecomponent.Mail{
+		To:      email,
+		Subject: fmt.Sprintf("Lời mời tham gia %s", organization.Name),
+		Body: fmt.Sprintf("Xin chào,\n\n%s mời bạn tham gia tổ chức %s với vai trò %s.\nĐăng nhập bằng địa chỉ email này rồi mở link sau để chấp nhận lời mời:\n%s\n\nLink chỉ dùng được một lần và có hiệu lực trong %s.\nNếu bạn không mong đợi lời mời này, hãy bỏ qua email.\n",
+			inviter.GetFullName(), organization.Name, invitation.Role, link, hdl.config.TokenExpIn),
+	}
+	if err := hdl.mailer.Send(ctx, mail); err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	// Email được mời có thể chưa thuộc tài khoản nào nên không ghi vào metadata
+	metadata, _ := json.Marshal(map[string]string{
+		"organization_id": organization.ID.String(),
+		"invitation_id":   invitation.ID.String(),
+		"role":            invitation.Role,
+	})
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		ActorID:   &cmd.UserID,
+		Event:     usermodel.AuditEventOrgMemberInvited,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: now,
+	})
+
+	return &usermodel.OrganizationInvitationResponse{
+		ID:        invitation.ID,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		ExpiresAt: invitation.ExpiresAt,
+	}, nil
+}
+
+// AcceptOrganizationInvitationCommand đại diện cho command user chấp nhận lời mời bằng token trong email
+type AcceptOrganizationInvitationCommand struct {
+	UserID    uuid.UUID
+	IPAddress string
+	UserAgent string
+	Dto       usermodel.AcceptOrganizationInvitationForm
+}
+
+// IAcceptOrganizationInvitationRepo interface cho repository operations cần thiết
+type IAcceptOrganizationInvitationRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	FindOrganization(ctx context.Context, id uuid.UUID) (*usermodel.Organization, error)
+	FindOrganizationInvitationByHash(ctx context.Context, tokenHash string) (*usermodel.OrganizationInvitation, error)
+	AcceptOrganizationInvitation(ctx context.Context, invitation *usermodel.OrganizationInvitation, member *usermodel.OrganizationMember, now time.Time) (bool, error)
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// AcceptOrganizationInvitationCommandHandler thêm user vào tổ chức khi token mời hợp lệ
+type AcceptOrganizationInvitationCommandHandler struct {
+	repo IAcceptOrganizationInvitationRepo
+}
+
+// NewAcceptOrganizationInvitationCommandHandler khởi tạo handler mới
+func NewAcceptOrganizationInvitationCommandHandler(repo IAcceptOrganizationInvitationRepo) *AcceptOrganizationInvitationCommandHandler {
+	return &AcceptOrganizationInvitationCommandHandler{repo: repo}
+}
+
+// Execute kiểm tra token và email của user đang đăng nhập rồi thêm user vào tổ chức với role trong lời mời.
+// Link bị chuyển tiếp cho người khác không dùng được vì email không khớp.
+func (hdl *AcceptOrganizationInvitationCommandHandler) Execute(ctx context.Context, cmd *AcceptOrganizationInvitationCommand) (*usermodel.OrganizationResponse, error) {
+	now := time.Now()
+
+	invitation, err := hdl.repo.FindOrganizationInvitationByHash(ctx, shared.HashToken(cmd.Dto.Token))
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, datatype.ErrBadRequest.WithError(usermodel.ErrInvalidInvitationToken.Error())
+		}
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if invitation.AcceptedAt != nil || !now.Before(invitation.ExpiresAt) {
+		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrInvalidInvitationToken.Error())
+	}
+
+	user, err := hdl.repo.FindById(ctx, cmd.UserID)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if normalizeLoginEmail(user.Email) != normalizeLoginEmail(invitation.Email) {
+		return nil, datatype.ErrForbidden.WithError(usermodel.ErrInvitationEmailMismatch.Error())
+	}
+
+	organization, err := hdl.repo.FindOrganization(ctx, invitation.OrganizationID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, datatype.ErrBadRequest.WithError(usermodel.ErrInvalidInvitationToken.Error())
+		}
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	member := &usermodel.OrganizationMember{
+		OrganizationID: organization.ID,
+		UserID:         user.ID,
+		Role:           invitation.Role,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	accepted, err := hdl.repo.AcceptOrganizationInvitation(ctx, invitation, member, now)
+	if err != nil {
+		if errors.Is(err, usermodel.ErrAlreadyOrgMember) {
+			return nil, datatype.ErrConflict.WithError(usermodel.ErrAlreadyOrgMember.Error())
+		}
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if !accepted {
+		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrInvalidInvitationToken.Error())
+	}
+
+	metadata, _ := json.Marshal(map[string]string{
+		"organization_id": organization.ID.String(),
+		"invitation_id":   invitation.ID.String(),
+		"role":            member.Role,
+	})
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &user.ID,
+		ActorID:   &user.ID,
+		Event:     usermodel.AuditEventOrgMemberJoined,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: now,
+	})
+
+	userOrganization := &usermodel.UserOrganization{Organization: *organization, Role: member.Role, JoinedAt: now}
+	return userOrganization.ToResponse(false), nil
+}
@@ -0,0 +1,161 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// ChangeOrganizationMemberRoleCommand đại diện cho command owner đổi role của thành viên trong tổ chức
+type ChangeOrganizationMemberRoleCommand struct {
+	UserID         uuid.UUID
+	OrganizationID uuid.UUID
+	MemberID       uuid.UUID
+	IPAddress      string
+	UserAgent      string
+	Dto            usermodel.ChangeOrganizationMemberRoleForm
+}
+
+// IOrganizationMemberRepo interface cho repository operations cần thiết khi quản lý thành viên
+type IOrganizationMemberRepo interface {
+	IOrganizationMemberFinder
+	ChangeOrganizationMemberRole(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID, role string, now time.Time) error
+	RemoveOrganizationMember(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID) error
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// ChangeOrganizationMemberRoleCommandHandler đổi role của thành viên, chỉ owner được đổi role
+type ChangeOrganizationMemberRoleCommandHandler struct {
+	repo IOrganizationMemberRepo
+}
+
+// NewChangeOrganizationMemberRoleCommandHandler khởi tạo handler mới
+func NewChangeOrganizationMemberRoleCommandHandler(repo IOrganizationMemberRepo) *ChangeOrganizationMemberRoleCommandHandler {
+	return &ChangeOrganizationMemberRoleCommandHandler{repo: repo}
+}
+
+// Execute đổi role, owner được tự hạ quyền khi tổ chức còn owner khác
+func (hdl *ChangeOrganizationMemberRoleCommandHandler) Execute(ctx context.Context, cmd *ChangeOrganizationMemberRoleCommand) error {
+	if _, err := requireOrganizationRole(ctx, hdl.repo, cmd.OrganizationID, cmd.UserID, datatype.OrgRoleOwner); err != nil {
+		return err
+	}
+
+	member, err := hdl.repo.FindOrganizationMember(ctx, cmd.OrganizationID, cmd.MemberID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return datatype.ErrNotFound.WithError(usermodel.ErrOrgMemberNotFound.Error())
+		}
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if member.Role == cmd.Dto.Role {
+		return nil
+	}
+
+	now := time.Now()
+	if err := hdl.repo.ChangeOrganizationMemberRole(ctx, cmd.OrganizationID, cmd.MemberID, cmd.Dto.Role, now); err != nil {
+		return organizationMemberError(err)
+	}
+
+	metadata, _ := json.Marshal(map[string]string{
+		"organization_id": cmd.OrganizationID.String(),
+		"from":            member.Role,
+		"to":              cmd.Dto.Role,
+	})
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &cmd.MemberID,
+		ActorID:   &cmd.UserID,
+		Event:     usermodel.AuditEventOrgMemberRoleChanged,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: now,
+	})
+
+	return nil
+}
+
+// RemoveOrganizationMemberCommand đại diện cho command xóa thành viên khỏi tổ chức hoặc tự rời tổ chức
+type RemoveOrganizationMemberCommand struct {
+	UserID         uuid.UUID
+	OrganizationID uuid.UUID
+	MemberID       uuid.UUID
+	IPAddress      string
+	UserAgent      string
+}
+
+// RemoveOrganizationMemberCommandHandler xóa thành viên khỏi tổ chức
+type RemoveOrganizationMemberCommandHandler struct {
+	repo IOrganizationMemberRepo
+}
+
+// NewRemoveOrganizationMemberCommandHandler khởi tạo handler mới
+func NewRemoveOrganizationMemberCommandHandler(repo IOrganizationMemberRepo) *RemoveOrganizationMemberCommandHandler {
+	return &RemoveOrganizationMemberCommandHandler{repo: repo}
+}
+
+// Execute xóa thành viên: mọi thành viên được tự rời tổ chức, owner xóa được mọi thành viên,
+// admin chỉ xóa được thành viên có role member. Owner cuối cùng không thể rời hoặc bị xóa.
+// Token đang mang tổ chức của thành viên bị xóa mất quyền trong tổ chức ngay ở request tiếp theo.
+func (hdl *RemoveOrganizationMemberCommandHandler) Execute(ctx context.Context, cmd *RemoveOrganizationMemberCommand) error {
+	actor, err := requireOrganizationRole(ctx, hdl.repo, cmd.OrganizationID, cmd.UserID)
+	if err != nil {
+		return err
+	}
+
+	member := actor
+	if cmd.MemberID != cmd.UserID {
+		if actor.Role != datatype.OrgRoleOwner && actor.Role != datatype.OrgRoleAdmin {
+			return datatype.ErrForbidden.WithError(usermodel.ErrOrgPermissionDenied.Error())
+		}
+
+		member, err = hdl.repo.FindOrganizationMember(ctx, cmd.OrganizationID, cmd.MemberID)
+		if err != nil {
+			if errors.Is(err, datatype.ErrRecordNotFound) {
+				return datatype.ErrNotFound.WithError(usermodel.ErrOrgMemberNotFound.Error())
+			}
+			return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+		}
+
+		if actor.Role == datatype.OrgRoleAdmin && member.Role != datatype.OrgRoleMember {
+			return datatype.ErrForbidden.WithError(usermodel.ErrOrgPermissionDenied.Error())
+		}
+	}
+
+	if err := hdl.repo.RemoveOrganizationMember(ctx, cmd.OrganizationID, member.UserID); err != nil {
+		return organizationMemberError(err)
+	}
+
+	metadata, _ := json.Marshal(map[string]string{
+		"organization_id": cmd.OrganizationID.String(),
+		"role":            member.Role,
+	})
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &member.UserID,
+		ActorID:   &cmd.UserID,
+		Event:     usermodel.AuditEventOrgMemberRemoved,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: time.Now(),
+	})
+
+	return nil
+}
+
+// organizationMemberError chuyển lỗi của repository khi thay đổi thành viên sang lỗi HTTP
+func organizationMemberError(err error) error {
+	switch {
+	case errors.Is(err, usermodel.ErrOrgMemberNotFound):
+		return datatype.ErrNotFound.WithError(usermodel.ErrOrgMemberNotFound.Error())
+	case errors.Is(err, usermodel.ErrLastOrganizationOwner):
+		return datatype.ErrConflict.WithError(usermodel.ErrLastOrganizationOwner.Error())
+	default:
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+}
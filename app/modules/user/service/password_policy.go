@@ -0,0 +1,26 @@
+package userservice
+
+import (
+	usermodel "fat2fast/ikv/modules/user/model"
+	sharecomponent "fat2fast/ikv/shared/component"
+	"fat2fast/ikv/shared/datatype"
+)
+
+// IPasswordPolicy interface kiểm tra mật khẩu mới theo chính sách mật khẩu
+type IPasswordPolicy interface {
+	Validate(password string, userInputs ...string) ([]sharecomponent.PasswordViolation, error)
+}
+
+// validateNewPassword kiểm tra mật khẩu mới, trả về lỗi 400 kèm các quy tắc chưa đạt trong details[field]
+func validateNewPassword(policy IPasswordPolicy, field string, password string, userInputs ...string) error {
+	violations, err := policy.Validate(password, userInputs...)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if len(violations) > 0 {
+		return datatype.ErrBadRequest.WithError(usermodel.ErrPasswordPolicy.Error()).WithDetail(field, violations)
+	}
+
+	return nil
+}
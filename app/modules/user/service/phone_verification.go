@@ -0,0 +1,232 @@
+package userservice
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared"
+	sharecomponent "fat2fast/ikv/shared/component"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// phoneCodeDigits là số chữ số của mã OTP xác minh số điện thoại
+const phoneCodeDigits = 6
+
+// ISMSSender interface gửi SMS
+type ISMSSender interface {
+	Send(ctx context.Context, sms *sharecomponent.SMS) error
+}
+
+// PhoneVerificationConfig là cấu hình luồng xác minh số điện thoại
+type PhoneVerificationConfig struct {
+	CodeExpIn       time.Duration
+	ResendInterval  time.Duration
+	RateLimitWindow time.Duration
+	RateLimitMax    int
+	MaxAttempts     int
+}
+
+// SendPhoneVerificationCommand đại diện cho command gửi mã OTP tới số điện thoại của user
+type SendPhoneVerificationCommand struct {
+	UserID uuid.UUID
+}
+
+// ISendPhoneVerificationRepo interface cho repository operations cần thiết
+type ISendPhoneVerificationRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	FindLatestPhoneVerificationCode(ctx context.Context, userID uuid.UUID) (*usermodel.PhoneVerificationCode, error)
+	CountPhoneVerificationCodesSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error)
+	InsertPhoneVerificationCode(ctx context.Context, code *usermodel.PhoneVerificationCode) error
+}
+
+// SendPhoneVerificationCommandHandler cấp mã OTP và gửi qua SMS.
+// Mỗi user chỉ được gửi một mã mỗi ResendInterval và tối đa RateLimitMax mã trong RateLimitWindow.
+type SendPhoneVerificationCommandHandler struct {
+	repo      ISendPhoneVerificationRepo
+	smsSender ISMSSender
+	config    PhoneVerificationConfig
+}
+
+// NewSendPhoneVerificationCommandHandler khởi tạo handler mới
+func NewSendPhoneVerificationCommandHandler(repo ISendPhoneVerificationRepo, smsSender ISMSSender, config PhoneVerificationConfig) *SendPhoneVerificationCommandHandler {
+	return &SendPhoneVerificationCommandHandler{repo: repo, smsSender: smsSender, config: config}
+}
+
+// Execute gửi mã mới tới số điện thoại hiện tại của user, mã gửi trước đó không còn dùng được
+func (hdl *SendPhoneVerificationCommandHandler) Execute(ctx context.Context, cmd *SendPhoneVerificationCommand) error {
+	user, err := hdl.repo.FindById(ctx, cmd.UserID)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if user.Phone == "" {
+		return datatype.ErrBadRequest.WithError(usermodel.ErrPhoneRequired.Error())
+	}
+	if user.PhoneVerifiedAt != nil {
+		return datatype.ErrConflict.WithError(usermodel.ErrPhoneAlreadyVerified.Error())
+	}
+
+	now := time.Now()
+
+	latest, err := hdl.repo.FindLatestPhoneVerificationCode(ctx, user.ID)
+	if err != nil && !errors.Is(err, datatype.ErrRecordNotFound) {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if latest != nil && now.Before(latest.CreatedAt.Add(hdl.config.ResendInterval)) {
+		return datatype.ErrTooManyRequests.WithError(usermodel.ErrPhoneCodeThrottled.Error())
+	}
+
+	// Giới hạn số SMS gửi cho mỗi tài khoản trong một khoảng thời gian để tránh bị lạm dụng gửi tin
+	count, err := hdl.repo.CountPhoneVerificationCodesSince(ctx, user.ID, now.Add(-hdl.config.RateLimitWindow))
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if count >= int64(hdl.config.RateLimitMax) {
+		return datatype.ErrTooManyRequests.WithError(usermodel.ErrPhoneCodeThrottled.Error())
+	}
+
+	code, err := randomDigits(phoneCodeDigits)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	newId, _ := uuid.NewV7()
+	verificationCode := &usermodel.PhoneVerificationCode{
+		ID:        newId,
+		UserID:    user.ID,
+		Phone:     user.Phone,
+		CodeHash:  hashPhoneCode(newId, code),
+		ExpiresAt: now.Add(hdl.config.CodeExpIn),
+		CreatedAt: now,
+	}
+	if err := hdl.repo.InsertPhoneVerificationCode(ctx, verificationCode); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	// Nội dung không dấu để tin nhắn dùng bảng mã GSM-7 và không bị tách thành nhiều SMS
+	sms := &sharecomponent.SMS{
+		To:   user.Phone,
+		Body: fmt.Sprintf("Ma xac minh so dien thoai cua ban la %s, co hieu luc trong %s. Khong chia se ma nay cho bat ky ai.", code, hdl.config.CodeExpIn),
+	}
+	if err := hdl.smsSender.Send(ctx, sms); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	return nil
+}
+
+// VerifyPhoneCommand đại diện cho command xác minh số điện thoại bằng mã OTP
+type VerifyPhoneCommand struct {
+	UserID    uuid.UUID
+	IPAddress string
+	UserAgent string
+	Dto       usermodel.VerifyPhoneForm
+}
+
+// IVerifyPhoneRepo interface cho repository operations cần thiết
+type IVerifyPhoneRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	FindLatestPhoneVerificationCode(ctx context.Context, userID uuid.UUID) (*usermodel.PhoneVerificationCode, error)
+	ReservePhoneVerificationAttempt(ctx context.Context, id uuid.UUID, maxAttempts int, now time.Time) (bool, error)
+	ConfirmPhoneVerification(ctx context.Context, code *usermodel.PhoneVerificationCode, now time.Time) (bool, error)
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// VerifyPhoneCommandHandler kiểm tra mã OTP và đánh dấu số điện thoại đã xác minh
+type VerifyPhoneCommandHandler struct {
+	repo   IVerifyPhoneRepo
+	config PhoneVerificationConfig
+}
+
+// NewVerifyPhoneCommandHandler khởi tạo handler mới
+func NewVerifyPhoneCommandHandler(repo IVerifyPhoneRepo, config PhoneVerificationConfig) *VerifyPhoneCommandHandler {
+	return &VerifyPhoneCommandHandler{repo: repo, config: config}
+}
+
+// Execute chỉ chấp nhận mã gửi gần nhất, còn hạn và gửi tới số điện thoại hiện tại của user.
+// Mỗi lần nhập (kể cả đúng) đều tính vào MaxAttempts trước khi so sánh nên không thể dò mã bằng các request đồng thời.
+func (hdl *VerifyPhoneCommandHandler) Execute(ctx context.Context, cmd *VerifyPhoneCommand) error {
+	user, err := hdl.repo.FindById(ctx, cmd.UserID)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if user.Phone == "" {
+		return datatype.ErrBadRequest.WithError(usermodel.ErrPhoneRequired.Error())
+	}
+	if user.PhoneVerifiedAt != nil {
+		return datatype.ErrConflict.WithError(usermodel.ErrPhoneAlreadyVerified.Error())
+	}
+
+	code, err := hdl.repo.FindLatestPhoneVerificationCode(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return datatype.ErrBadRequest.WithError(usermodel.ErrInvalidPhoneCode.Error())
+		}
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	now := time.Now()
+	if code.ConsumedAt != nil || !now.Before(code.ExpiresAt) || code.Phone != user.Phone {
+		return datatype.ErrBadRequest.WithError(usermodel.ErrInvalidPhoneCode.Error())
+	}
+
+	reserved, err := hdl.repo.ReservePhoneVerificationAttempt(ctx, code.ID, hdl.config.MaxAttempts, now)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if !reserved {
+		return datatype.ErrBadRequest.WithError(usermodel.ErrInvalidPhoneCode.Error())
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashPhoneCode(code.ID, cmd.Dto.Code)), []byte(code.CodeHash)) != 1 {
+		return datatype.ErrBadRequest.WithError(usermodel.ErrInvalidPhoneCode.Error())
+	}
+
+	// Số điện thoại có thể đã đổi hoặc mã đã được dùng bởi request khác sau khi đọc
+	confirmed, err := hdl.repo.ConfirmPhoneVerification(ctx, code, now)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if !confirmed {
+		return datatype.ErrBadRequest.WithError(usermodel.ErrInvalidPhoneCode.Error())
+	}
+
+	metadata, _ := json.Marshal(map[string]string{"phone": code.Phone})
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &user.ID,
+		ActorID:   &cmd.UserID,
+		Event:     usermodel.AuditEventPhoneVerified,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: now,
+	})
+
+	return nil
+}
+
+// hashPhoneCode băm mã OTP kèm ID của mã để cùng một mã ở các lần gửi khác nhau không có cùng giá trị băm
+func hashPhoneCode(id uuid.UUID, code string) string {
+	return shared.HashToken(id.String() + ":" + code)
+}
+
+// randomDigits sinh chuỗi số ngẫu nhiên có độ dài n bằng crypto/rand
+func randomDigits(n int) (string, error) {
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+	v, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return fmt.Sprintf("%0*d", n, v), nil
+}
@@ -0,0 +1,127 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// RefreshTokenCommand đại diện cho command làm mới access token
+type RefreshTokenCommand struct {
+	IPAddress string
+	UserAgent string
+	Dto       usermodel.RefreshTokenForm
+}
+
+// IRefreshTokenRepo interface cho repository operations cần thiết
+type IRefreshTokenRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	FindRefreshTokenByHash(ctx context.Context, tokenHash string) (*usermodel.RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, id uuid.UUID) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// ITokenPairIssuer interface cấp cặp access/refresh token
+type ITokenPairIssuer interface {
+	StartSession(ctx context.Context, user *usermodel.User, client SessionClient) (*AuthenticateResult, error)
+	Issue(ctx context.Context, user *usermodel.User, sessionID uuid.UUID) (*AuthenticateResult, error)
+}
+
+// RefreshTokenCommandHandler xoay vòng refresh token và cấp access token mới
+type RefreshTokenCommandHandler struct {
+	repo            IRefreshTokenRepo
+	tokenPairIssuer ITokenPairIssuer
+}
+
+// NewRefreshTokenCommandHandler khởi tạo handler mới
+func NewRefreshTokenCommandHandler(repo IRefreshTokenRepo, tokenPairIssuer ITokenPairIssuer) *RefreshTokenCommandHandler {
+	return &RefreshTokenCommandHandler{repo: repo, tokenPairIssuer: tokenPairIssuer}
+}
+
+// Execute thực thi command làm mới token.
+// Refresh token chỉ dùng được một lần; nếu một token đã dùng bị gửi lại,
+// toàn bộ family sẽ bị thu hồi vì token có thể đã bị đánh cắp.
+func (hdl *RefreshTokenCommandHandler) Execute(ctx context.Context, cmd *RefreshTokenCommand) (*AuthenticateResult, error) {
+	token, err := hdl.repo.FindRefreshTokenByHash(ctx, shared.HashToken(cmd.Dto.RefreshToken))
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, datatype.ErrUnauthorized.WithError(usermodel.ErrInvalidRefreshToken.Error())
+		}
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if token.RevokedAt != nil || token.IsExpired(time.Now()) {
+		return nil, datatype.ErrUnauthorized.WithError(usermodel.ErrInvalidRefreshToken.Error())
+	}
+
+	if token.UsedAt != nil {
+		return nil, hdl.revokeFamily(ctx, cmd, token)
+	}
+
+	user, err := hdl.repo.FindById(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, datatype.ErrUnauthorized.WithError(usermodel.ErrInvalidRefreshToken.Error())
+		}
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	// User được mở khóa hoặc khôi phục khi chưa xác thực email sẽ về pending, không được refresh như khi đăng nhập
+	if err := user.CheckActive(); err != nil {
+		return nil, datatype.ErrUnauthorized.WithError(err.Error())
+	}
+
+	// Đánh dấu đã dùng có điều kiện để phát hiện hai request dùng cùng một token đồng thời
+	marked, err := hdl.repo.MarkRefreshTokenUsed(ctx, token.ID)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if !marked {
+		return nil, hdl.revokeFamily(ctx, cmd, token)
+	}
+
+	result, err := hdl.tokenPairIssuer.Issue(ctx, user, token.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, _ := json.Marshal(map[string]string{"session_id": token.FamilyID.String()})
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &user.ID,
+		ActorID:   &user.ID,
+		Event:     usermodel.AuditEventTokenRefreshed,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: time.Now(),
+	})
+
+	return result, nil
+}
+
+// revokeFamily thu hồi toàn bộ family khi phát hiện refresh token bị dùng lại
+func (hdl *RefreshTokenCommandHandler) revokeFamily(ctx context.Context, cmd *RefreshTokenCommand, token *usermodel.RefreshToken) error {
+	if err := hdl.repo.RevokeRefreshTokenFamily(ctx, token.FamilyID); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	metadata, _ := json.Marshal(map[string]string{"session_id": token.FamilyID.String()})
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &token.UserID,
+		Event:     usermodel.AuditEventRefreshTokenReused,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: time.Now(),
+	})
+
+	return datatype.ErrUnauthorized.WithError(usermodel.ErrRefreshTokenReused.Error())
+}
@@ -0,0 +1,207 @@
+package userservice
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+)
+
+type fakeRefreshTokenRepo struct {
+	user            *usermodel.User
+	tokens          map[string]*usermodel.RefreshToken
+	markFails       bool
+	revokedFamilies []uuid.UUID
+	auditEvents     []string
+}
+
+func (r *fakeRefreshTokenRepo) FindById(_ context.Context, id uuid.UUID) (*usermodel.User, error) {
+	if r.user == nil || r.user.ID != id {
+		return nil, datatype.ErrRecordNotFound
+	}
+	return r.user, nil
+}
+
+func (r *fakeRefreshTokenRepo) FindRefreshTokenByHash(_ context.Context, tokenHash string) (*usermodel.RefreshToken, error) {
+	token, ok := r.tokens[tokenHash]
+	if !ok {
+		return nil, datatype.ErrRecordNotFound
+	}
+	copied := *token
+	return &copied, nil
+}
+
+func (r *fakeRefreshTokenRepo) MarkRefreshTokenUsed(_ context.Context, id uuid.UUID) (bool, error) {
+	// markFails giả lập một request khác đã dùng token giữa lúc đọc và lúc đánh dấu
+	if r.markFails {
+		return false, nil
+	}
+	for _, token := range r.tokens {
+		if token.ID == id && token.UsedAt == nil {
+			now := time.Now()
+			token.UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeRefreshTokenRepo) RevokeRefreshTokenFamily(_ context.Context, familyID uuid.UUID) error {
+	r.revokedFamilies = append(r.revokedFamilies, familyID)
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) InsertAuditEvent(_ context.Context, event *usermodel.AuditEvent) error {
+	r.auditEvents = append(r.auditEvents, event.Event)
+	return nil
+}
+
+type fakeTokenPairIssuer struct {
+	issued []uuid.UUID
+}
+
+func (i *fakeTokenPairIssuer) StartSession(_ context.Context, _ *usermodel.User, _ SessionClient) (*AuthenticateResult, error) {
+	return &AuthenticateResult{}, nil
+}
+
+func (i *fakeTokenPairIssuer) Issue(_ context.Context, _ *usermodel.User, sessionID uuid.UUID) (*AuthenticateResult, error) {
+	i.issued = append(i.issued, sessionID)
+	return &AuthenticateResult{}, nil
+}
+
+func newRefreshTokenFixture(t *testing.T, status usermodel.UserStatus) (*fakeRefreshTokenRepo, *usermodel.RefreshToken) {
+	t.Helper()
+
+	userID, _ := uuid.NewV7()
+	tokenID, _ := uuid.NewV7()
+	familyID, _ := uuid.NewV7()
+	token := &usermodel.RefreshToken{
+		ID:        tokenID,
+		FamilyID:  familyID,
+		UserID:    userID,
+		TokenHash: shared.HashToken("refresh-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedAt: time.Now(),
+	}
+
+	return &fakeRefreshTokenRepo{
+		user:   &usermodel.User{ID: userID, Status: status},
+		tokens: map[string]*usermodel.RefreshToken{token.TokenHash: token},
+	}, token
+}
+
+func refreshCommand(refreshToken string) *RefreshTokenCommand {
+	return &RefreshTokenCommand{Dto: usermodel.RefreshTokenForm{RefreshToken: refreshToken}}
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	repo, token := newRefreshTokenFixture(t, usermodel.StatusActive)
+	issuer := &fakeTokenPairIssuer{}
+	hdl := NewRefreshTokenCommandHandler(repo, issuer)
+
+	if _, err := hdl.Execute(context.Background(), refreshCommand("refresh-token")); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(issuer.issued) != 1 || issuer.issued[0] != token.FamilyID {
+		t.Errorf("issued sessions = %v, want [%s]", issuer.issued, token.FamilyID)
+	}
+	if repo.tokens[token.TokenHash].UsedAt == nil {
+		t.Error("refresh token was not marked as used")
+	}
+
+	// Dùng lại token đã xoay vòng thu hồi cả family
+	_, err := hdl.Execute(context.Background(), refreshCommand("refresh-token"))
+	assertUnauthorized(t, err, usermodel.ErrRefreshTokenReused)
+	if len(repo.revokedFamilies) != 1 || repo.revokedFamilies[0] != token.FamilyID {
+		t.Errorf("revoked families = %v, want [%s]", repo.revokedFamilies, token.FamilyID)
+	}
+	if len(issuer.issued) != 1 {
+		t.Errorf("issued %d token pairs, want 1", len(issuer.issued))
+	}
+
+	// Family đã bị thu hồi thì token không còn dùng được
+	_, err = hdl.Execute(context.Background(), refreshCommand("refresh-token"))
+	assertUnauthorized(t, err, usermodel.ErrInvalidRefreshToken)
+
+	wantEvents := []string{usermodel.AuditEventTokenRefreshed, usermodel.AuditEventRefreshTokenReused}
+	if len(repo.auditEvents) != len(wantEvents) || repo.auditEvents[0] != wantEvents[0] || repo.auditEvents[1] != wantEvents[1] {
+		t.Errorf("audit events = %v, want %v", repo.auditEvents, wantEvents)
+	}
+}
+
+func TestRefreshTokenConcurrentUse(t *testing.T) {
+	repo, token := newRefreshTokenFixture(t, usermodel.StatusActive)
+	repo.markFails = true
+	issuer := &fakeTokenPairIssuer{}
+	hdl := NewRefreshTokenCommandHandler(repo, issuer)
+
+	_, err := hdl.Execute(context.Background(), refreshCommand("refresh-token"))
+	assertUnauthorized(t, err, usermodel.ErrRefreshTokenReused)
+	if len(repo.revokedFamilies) != 1 || repo.revokedFamilies[0] != token.FamilyID {
+		t.Errorf("revoked families = %v, want [%s]", repo.revokedFamilies, token.FamilyID)
+	}
+	if len(issuer.issued) != 0 {
+		t.Errorf("issued %d token pairs, want 0", len(issuer.issued))
+	}
+}
+
+func TestRefreshTokenRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  usermodel.UserStatus
+		mutate  func(token *usermodel.RefreshToken)
+		token   string
+		wantErr error
+	}{
+		{name: "unknown token", status: usermodel.StatusActive, token: "other-token", wantErr: usermodel.ErrInvalidRefreshToken},
+		{name: "expired", status: usermodel.StatusActive, token: "refresh-token", wantErr: usermodel.ErrInvalidRefreshToken,
+			mutate: func(token *usermodel.RefreshToken) { token.ExpiresAt = time.Now().Add(-time.Second) }},
+		{name: "revoked", status: usermodel.StatusActive, token: "refresh-token", wantErr: usermodel.ErrInvalidRefreshToken,
+			mutate: func(token *usermodel.RefreshToken) { now := time.Now(); token.RevokedAt = &now }},
+		{name: "banned user", status: usermodel.StatusBanned, token: "refresh-token", wantErr: usermodel.ErrUserBannedOrDeleted},
+		{name: "deleted user", status: usermodel.StatusDeleted, token: "refresh-token", wantErr: usermodel.ErrUserBannedOrDeleted},
+		{name: "inactive user", status: usermodel.StatusInactive, token: "refresh-token", wantErr: usermodel.ErrUserBannedOrDeleted},
+		{name: "pending user", status: usermodel.StatusPending, token: "refresh-token", wantErr: usermodel.ErrEmailNotVerified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, token := newRefreshTokenFixture(t, tt.status)
+			if tt.mutate != nil {
+				tt.mutate(token)
+			}
+			issuer := &fakeTokenPairIssuer{}
+
+			_, err := NewRefreshTokenCommandHandler(repo, issuer).Execute(context.Background(), refreshCommand(tt.token))
+			assertUnauthorized(t, err, tt.wantErr)
+			if len(issuer.issued) != 0 || len(repo.revokedFamilies) != 0 {
+				t.Errorf("issued = %v, revoked families = %v, want none", issuer.issued, repo.revokedFamilies)
+			}
+		})
+	}
+}
+
+func assertUnauthorized(t *testing.T, err error, want error) {
+	t.Helper()
+
+	var appErr *datatype.DefaultError
+	if !errors.As(err, &appErr) || appErr.StatusCode() != http.StatusUnauthorized {
+		t.Fatalf("error = %v, want 401", err)
+	}
+	if appErr.Error() != want.Error() {
+		t.Errorf("error message = %q, want %q", appErr.Error(), want.Error())
+	}
+}
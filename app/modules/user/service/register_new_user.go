@@ -2,10 +2,10 @@ package userservice
 
 import (
 	"context"
+	"log"
 	"time"
 
 	usermodel "fat2fast/ikv/modules/user/model"
-	"fat2fast/ikv/shared"
 	"fat2fast/ikv/shared/datatype"
 
 	"github.com/google/uuid"
@@ -18,16 +18,28 @@ type ICreateRepo interface {
 	Insert(ctx context.Context, data *usermodel.User) error
 }
 type CreateCommandHandler struct {
-	userRepo ICreateRepo
+	userRepo           ICreateRepo
+	verificationSender IEmailVerificationSender
+	passwordHasher     IPasswordHasher
+	passwordPolicy     IPasswordPolicy
+	phoneCountryCode   string
 }
 
-func NewCreateCommandHandler(userRepo ICreateRepo) *CreateCommandHandler {
-	return &CreateCommandHandler{userRepo: userRepo}
+func NewCreateCommandHandler(userRepo ICreateRepo, verificationSender IEmailVerificationSender, passwordHasher IPasswordHasher, passwordPolicy IPasswordPolicy, phoneCountryCode string) *CreateCommandHandler {
+	return &CreateCommandHandler{userRepo: userRepo, verificationSender: verificationSender, passwordHasher: passwordHasher, passwordPolicy: passwordPolicy, phoneCountryCode: phoneCountryCode}
 }
 func (uc *CreateCommandHandler) Execute(ctx context.Context, cmd *CreateCommand) (*usermodel.RegisterResponse, error) {
+	// Số điện thoại được lưu theo định dạng E.164, chưa xác minh cho tới khi user nhập mã OTP gửi qua SMS
+	phone, err := usermodel.NormalizePhone(cmd.Dto.Phone, uc.phoneCountryCode)
+	if err != nil {
+		return nil, datatype.ErrBadRequest.WithError(err.Error())
+	}
+
+	if err := validateNewPassword(uc.passwordPolicy, "password", cmd.Dto.Password, cmd.Dto.Email, cmd.Dto.FirstName, cmd.Dto.LastName); err != nil {
+		return nil, err
+	}
 
-	salt, _ := shared.RandomStr(16)
-	hashPassword, err := shared.HashPassword(cmd.Dto.Password, salt)
+	hashPassword, err := uc.passwordHasher.Hash(cmd.Dto.Password)
 
 	if err != nil {
 		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
@@ -42,26 +54,33 @@ func (uc *CreateCommandHandler) Execute(ctx context.Context, cmd *CreateCommand)
 		Password:  string(hashPassword),
 		FirstName: cmd.Dto.FirstName,
 		LastName:  cmd.Dto.LastName,
-		Phone:     cmd.Dto.Phone,
-		Salt:      salt,
-		Status:    usermodel.StatusActive,
-		Type:      usermodel.TypeEmailPassword,
-		Role:      usermodel.RoleUser,
-		CreatedAt: &now,
-		CreatedBy: "system",
-		UpdatedAt: &now,
-		UpdatedBy: "system",
+		Phone:     phone,
+		// User chỉ được active sau khi xác minh email
+		Status:                  usermodel.StatusPending,
+		Type:                    usermodel.TypeEmailPassword,
+		Role:                    usermodel.RoleUser,
+		CreatedAt:               &now,
+		CreatedBy:               "system",
+		UpdatedAt:               &now,
+		UpdatedBy:               "system",
+		EmailVerificationSentAt: &now,
 	}
 	err = uc.userRepo.Insert(ctx, user)
 	if err != nil {
 		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
 	}
+
+	// Lỗi gửi email không làm hỏng việc đăng ký, user có thể yêu cầu gửi lại
+	if err := uc.verificationSender.Send(ctx, user); err != nil {
+		log.Printf("Error sending verification email to user %s: %v", user.ID, err)
+	}
 	userResponse := &usermodel.RegisterResponse{
 		ID:        user.ID,
 		Email:     user.Email,
 		FirstName: user.FirstName,
 		LastName:  user.LastName,
 		Phone:     user.Phone,
+		Status:    user.Status,
 	}
 	return userResponse, nil
 }
@@ -0,0 +1,65 @@
+package userservice
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// ResendVerificationCommand đại diện cho command gửi lại email xác minh
+type ResendVerificationCommand struct {
+	Dto usermodel.ResendVerificationForm
+}
+
+// IResendVerificationRepo interface cho repository operations cần thiết
+type IResendVerificationRepo interface {
+	FindByEmail(ctx context.Context, email string) (*usermodel.User, error)
+	ReserveEmailVerificationSend(ctx context.Context, id uuid.UUID, now time.Time, interval time.Duration) (bool, error)
+}
+
+// ResendVerificationCommandHandler gửi lại email xác minh, giới hạn một lần mỗi resendInterval cho mỗi user
+type ResendVerificationCommandHandler struct {
+	repo           IResendVerificationRepo
+	sender         IEmailVerificationSender
+	resendInterval time.Duration
+}
+
+// NewResendVerificationCommandHandler khởi tạo handler mới
+func NewResendVerificationCommandHandler(repo IResendVerificationRepo, sender IEmailVerificationSender, resendInterval time.Duration) *ResendVerificationCommandHandler {
+	return &ResendVerificationCommandHandler{repo: repo, sender: sender, resendInterval: resendInterval}
+}
+
+// Execute gửi lại email xác minh. Email không tồn tại hoặc đã xác minh vẫn trả về thành công
+// để endpoint không tiết lộ trạng thái tài khoản.
+func (hdl *ResendVerificationCommandHandler) Execute(ctx context.Context, cmd *ResendVerificationCommand) error {
+	user, err := hdl.repo.FindByEmail(ctx, cmd.Dto.Email)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil
+		}
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if user.Status != usermodel.StatusPending {
+		return nil
+	}
+
+	reserved, err := hdl.repo.ReserveEmailVerificationSend(ctx, user.ID, time.Now(), hdl.resendInterval)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if !reserved {
+		return datatype.ErrTooManyRequests.WithError(usermodel.ErrVerifyEmailThrottled.Error())
+	}
+
+	if err := hdl.sender.Send(ctx, user); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package userservice
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// ResetPasswordCommand đại diện cho command đặt lại mật khẩu bằng token trong email
+type ResetPasswordCommand struct {
+	IPAddress string
+	UserAgent string
+	Dto       usermodel.ResetPasswordForm
+}
+
+// IResetPasswordRepo interface cho repository operations cần thiết
+type IResetPasswordRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	FindPasswordResetTokenByHash(ctx context.Context, tokenHash string) (*usermodel.PasswordResetToken, error)
+	ConsumePasswordResetToken(ctx context.Context, id uuid.UUID, now time.Time) (bool, error)
+	InvalidateUserPasswordResetTokens(ctx context.Context, userID uuid.UUID, now time.Time) error
+	UpdatePassword(ctx context.Context, userID uuid.UUID, hashedPassword string, updatedAt time.Time) error
+	MarkEmailVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error
+	RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// ResetPasswordCommandHandler đặt mật khẩu mới và đăng xuất user khỏi mọi phiên
+type ResetPasswordCommandHandler struct {
+	repo           IResetPasswordRepo
+	tokenRevoker   ITokenRevoker
+	passwordHasher IPasswordHasher
+	passwordPolicy IPasswordPolicy
+}
+
+// NewResetPasswordCommandHandler khởi tạo handler mới
+func NewResetPasswordCommandHandler(repo IResetPasswordRepo, tokenRevoker ITokenRevoker, passwordHasher IPasswordHasher, passwordPolicy IPasswordPolicy) *ResetPasswordCommandHandler {
+	return &ResetPasswordCommandHandler{repo: repo, tokenRevoker: tokenRevoker, passwordHasher: passwordHasher, passwordPolicy: passwordPolicy}
+}
+
+// Execute kiểm tra token, đổi mật khẩu, vô hiệu hóa các token đặt lại khác và thu hồi mọi phiên đăng nhập
+func (hdl *ResetPasswordCommandHandler) Execute(ctx context.Context, cmd *ResetPasswordCommand) error {
+	now := time.Now()
+
+	token, err := hdl.repo.FindPasswordResetTokenByHash(ctx, shared.HashToken(cmd.Dto.Token))
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return datatype.ErrBadRequest.WithError(usermodel.ErrInvalidResetToken.Error())
+		}
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if token.UsedAt != nil || !now.Before(token.ExpiresAt) {
+		return datatype.ErrBadRequest.WithError(usermodel.ErrInvalidResetToken.Error())
+	}
+
+	user, err := hdl.repo.FindById(ctx, token.UserID)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if user.Status == usermodel.StatusBanned || user.Status == usermodel.StatusDeleted {
+		return datatype.ErrBadRequest.WithError(usermodel.ErrUserBannedOrDeleted.Error())
+	}
+
+	// Kiểm tra mật khẩu mới trước khi dùng token để user có thể thử lại với cùng link
+	if err := validateNewPassword(hdl.passwordPolicy, "new_password", cmd.Dto.NewPassword, user.Email, user.FirstName, user.LastName); err != nil {
+		return err
+	}
+
+	consumed, err := hdl.repo.ConsumePasswordResetToken(ctx, token.ID, now)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if !consumed {
+		return datatype.ErrBadRequest.WithError(usermodel.ErrInvalidResetToken.Error())
+	}
+
+	hashPassword, err := hdl.passwordHasher.Hash(cmd.Dto.NewPassword)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if err := hdl.repo.UpdatePassword(ctx, user.ID, hashPassword, now); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	// Mở link trong email chứng minh quyền sở hữu email nên tài khoản chờ xác minh được kích hoạt
+	if user.Status == usermodel.StatusPending {
+		if err := hdl.repo.MarkEmailVerified(ctx, user.ID, now); err != nil {
+			return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+		}
+	}
+
+	if err := hdl.repo.InvalidateUserPasswordResetTokens(ctx, user.ID, now); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	// Đăng xuất mọi phiên hiện có vì mật khẩu cũ có thể đã bị lộ
+	if err := hdl.repo.RevokeUserRefreshTokens(ctx, user.ID); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if err := hdl.tokenRevoker.RevokeAllUserTokens(ctx, user.ID); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &user.ID,
+		ActorID:   &user.ID,
+		Event:     usermodel.AuditEventPasswordReset,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		CreatedAt: now,
+	})
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+)
+
+// RevokeAPIKeyCommand đại diện cho command thu hồi API key của user
+type RevokeAPIKeyCommand struct {
+	UserID    uuid.UUID
+	KeyID     uuid.UUID
+	IPAddress string
+	UserAgent string
+}
+
+// IRevokeAPIKeyRepo interface cho repository operations cần thiết
+type IRevokeAPIKeyRepo interface {
+	RevokeAPIKey(ctx context.Context, userID uuid.UUID, id uuid.UUID, now time.Time) (bool, error)
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// RevokeAPIKeyCommandHandler thu hồi API key, khóa bị từ chối ngay ở request tiếp theo
+type RevokeAPIKeyCommandHandler struct {
+	repo IRevokeAPIKeyRepo
+}
+
+// NewRevokeAPIKeyCommandHandler khởi tạo handler mới
+func NewRevokeAPIKeyCommandHandler(repo IRevokeAPIKeyRepo) *RevokeAPIKeyCommandHandler {
+	return &RevokeAPIKeyCommandHandler{repo: repo}
+}
+
+// Execute thu hồi khóa, chỉ chủ sở hữu mới thu hồi được khóa của mình
+func (hdl *RevokeAPIKeyCommandHandler) Execute(ctx context.Context, cmd *RevokeAPIKeyCommand) error {
+	now := time.Now()
+
+	revoked, err := hdl.repo.RevokeAPIKey(ctx, cmd.UserID, cmd.KeyID, now)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if !revoked {
+		return datatype.ErrNotFound.WithError(usermodel.ErrAPIKeyNotFound.Error())
+	}
+
+	metadata, _ := json.Marshal(map[string]any{"key_id": cmd.KeyID})
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &cmd.UserID,
+		ActorID:   &cmd.UserID,
+		Event:     usermodel.AuditEventAPIKeyRevoked,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: now,
+	})
+
+	return nil
+}
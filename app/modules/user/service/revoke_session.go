@@ -0,0 +1,61 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+)
+
+// RevokeSessionCommand đại diện cho command đăng xuất một phiên đăng nhập của user
+type RevokeSessionCommand struct {
+	UserID    uuid.UUID
+	SessionID uuid.UUID
+	IPAddress string
+	UserAgent string
+}
+
+// IRevokeSessionRepo interface cho repository operations cần thiết
+type IRevokeSessionRepo interface {
+	RevokeSession(ctx context.Context, userID uuid.UUID, id uuid.UUID) (bool, error)
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// RevokeSessionCommandHandler thu hồi phiên đăng nhập trên một thiết bị
+type RevokeSessionCommandHandler struct {
+	repo IRevokeSessionRepo
+}
+
+// NewRevokeSessionCommandHandler khởi tạo handler mới
+func NewRevokeSessionCommandHandler(repo IRevokeSessionRepo) *RevokeSessionCommandHandler {
+	return &RevokeSessionCommandHandler{repo: repo}
+}
+
+// Execute thu hồi phiên cùng refresh token của phiên, access token của phiên bị từ chối ngay ở request tiếp theo.
+// Chỉ chủ sở hữu mới thu hồi được phiên của mình.
+func (hdl *RevokeSessionCommandHandler) Execute(ctx context.Context, cmd *RevokeSessionCommand) error {
+	revoked, err := hdl.repo.RevokeSession(ctx, cmd.UserID, cmd.SessionID)
+	if err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if !revoked {
+		return datatype.ErrNotFound.WithError(usermodel.ErrSessionNotFound.Error())
+	}
+
+	metadata, _ := json.Marshal(map[string]any{"session_id": cmd.SessionID})
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &cmd.UserID,
+		ActorID:   &cmd.UserID,
+		Event:     usermodel.AuditEventSessionRevoked,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: time.Now(),
+	})
+
+	return nil
+}
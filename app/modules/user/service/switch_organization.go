@@ -0,0 +1,84 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+)
+
+// SwitchOrganizationCommand đại diện cho command đổi tổ chức đang hoạt động của phiên đăng nhập hiện tại
+type SwitchOrganizationCommand struct {
+	UserID         uuid.UUID
+	SessionID      string
+	OrganizationID uuid.UUID
+	IPAddress      string
+	UserAgent      string
+}
+
+// ISwitchOrganizationRepo interface cho repository operations cần thiết
+type ISwitchOrganizationRepo interface {
+	IOrganizationMemberFinder
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// IOrganizationSwitcher interface đổi tổ chức của phiên và cấp access token mới (xem TokenPairIssuer)
+type IOrganizationSwitcher interface {
+	SwitchOrganization(ctx context.Context, user *usermodel.User, sessionID uuid.UUID, member *usermodel.OrganizationMember) (*AuthenticateResult, error)
+}
+
+// SwitchOrganizationCommandHandler đổi tổ chức đang hoạt động của phiên
+type SwitchOrganizationCommandHandler struct {
+	repo            ISwitchOrganizationRepo
+	tokenPairIssuer IOrganizationSwitcher
+}
+
+// NewSwitchOrganizationCommandHandler khởi tạo handler mới
+func NewSwitchOrganizationCommandHandler(repo ISwitchOrganizationRepo, tokenPairIssuer IOrganizationSwitcher) *SwitchOrganizationCommandHandler {
+	return &SwitchOrganizationCommandHandler{repo: repo, tokenPairIssuer: tokenPairIssuer}
+}
+
+// Execute kiểm tra user là thành viên của tổ chức rồi trả về access token mới mang tổ chức (claim "org").
+// Chỉ phiên đăng nhập bằng JWT có tổ chức đang hoạt động, token cấp trước khi có quản lý phiên không đổi được.
+func (hdl *SwitchOrganizationCommandHandler) Execute(ctx context.Context, cmd *SwitchOrganizationCommand) (*AuthenticateResult, error) {
+	sessionID, err := uuid.Parse(cmd.SessionID)
+	if err != nil {
+		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrSessionRequired.Error())
+	}
+
+	member, err := requireOrganizationRole(ctx, hdl.repo, cmd.OrganizationID, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := hdl.repo.FindById(ctx, cmd.UserID)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	result, err := hdl.tokenPairIssuer.SwitchOrganization(ctx, user, sessionID, member)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, _ := json.Marshal(map[string]string{
+		"organization_id": member.OrganizationID.String(),
+		"session_id":      sessionID.String(),
+	})
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &user.ID,
+		ActorID:   &user.ID,
+		Event:     usermodel.AuditEventOrganizationSwitched,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		Metadata:  string(metadata),
+		CreatedAt: time.Now(),
+	})
+
+	return result, nil
+}
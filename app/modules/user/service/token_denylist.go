@@ -0,0 +1,177 @@
+package userservice
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// ITokenRevocationRepo interface cho repository lưu denylist
+type ITokenRevocationRepo interface {
+	InsertTokenRevocation(ctx context.Context, revocation *usermodel.TokenRevocation) error
+	ListActiveTokenRevocations(ctx context.Context, now time.Time) ([]usermodel.TokenRevocation, error)
+	DeleteExpiredTokenRevocations(ctx context.Context, now time.Time) (int64, error)
+}
+
+// TokenDenylist quản lý các access token bị thu hồi trước hạn.
+// Dữ liệu được lưu ở Postgres và cache toàn bộ trong bộ nhớ để kiểm tra mỗi request
+// không phải truy vấn database; cache được đồng bộ định kỳ để nhận thu hồi từ instance khác.
+type TokenDenylist struct {
+	repo       ITokenRevocationRepo
+	tokenExpIn int
+
+	mu     sync.RWMutex
+	tokens map[string]time.Time    // jti -> thời điểm hết hạn của bản ghi
+	users  map[uuid.UUID]time.Time // user ID -> token cấp trước thời điểm này bị thu hồi
+}
+
+// NewTokenDenylist khởi tạo denylist, tokenExpIn là thời hạn tối đa (giây) của access token
+func NewTokenDenylist(repo ITokenRevocationRepo, tokenExpIn int) *TokenDenylist {
+	return &TokenDenylist{
+		repo:       repo,
+		tokenExpIn: tokenExpIn,
+		tokens:     make(map[string]time.Time),
+		users:      make(map[uuid.UUID]time.Time),
+	}
+}
+
+// RevokeToken thu hồi một access token theo jti
+func (d *TokenDenylist) RevokeToken(ctx context.Context, userID uuid.UUID, jti string) error {
+	now := time.Now()
+	newId, _ := uuid.NewV7()
+	revocation := &usermodel.TokenRevocation{
+		ID:        newId,
+		JTI:       &jti,
+		UserID:    userID,
+		RevokedAt: now,
+		ExpiresAt: now.Add(d.maxTokenLifetime()),
+	}
+
+	if err := d.repo.InsertTokenRevocation(ctx, revocation); err != nil {
+		return err
+	}
+
+	d.apply(revocation)
+	return nil
+}
+
+// RevokeAllUserTokens thu hồi mọi access token của user đã được cấp tới thời điểm hiện tại
+func (d *TokenDenylist) RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error {
+	// Cắt theo giây vì claim iat chỉ có độ chính xác tới giây
+	now := time.Now().Truncate(time.Second)
+	newId, _ := uuid.NewV7()
+	revocation := &usermodel.TokenRevocation{
+		ID:        newId,
+		UserID:    userID,
+		RevokedAt: now,
+		ExpiresAt: now.Add(d.maxTokenLifetime()),
+	}
+
+	if err := d.repo.InsertTokenRevocation(ctx, revocation); err != nil {
+		return err
+	}
+
+	d.apply(revocation)
+	return nil
+}
+
+// IsRevoked kiểm tra token có nằm trong denylist không
+func (d *TokenDenylist) IsRevoked(ctx context.Context, jti string, subject string, issuedAt time.Time) (bool, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if _, ok := d.tokens[jti]; ok {
+		return true, nil
+	}
+
+	userID, err := uuid.Parse(subject)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	if revokedBefore, ok := d.users[userID]; ok && !issuedAt.After(revokedBefore) {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// Sync nạp lại denylist từ database và xóa các bản ghi đã hết hạn
+func (d *TokenDenylist) Sync(ctx context.Context) error {
+	now := time.Now()
+
+	if _, err := d.repo.DeleteExpiredTokenRevocations(ctx, now); err != nil {
+		return err
+	}
+
+	revocations, err := d.repo.ListActiveTokenRevocations(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	tokens := make(map[string]time.Time)
+	users := make(map[uuid.UUID]time.Time)
+	for _, revocation := range revocations {
+		if revocation.JTI != nil {
+			tokens[*revocation.JTI] = revocation.ExpiresAt
+			continue
+		}
+		if revokedBefore, ok := users[revocation.UserID]; !ok || revocation.RevokedAt.After(revokedBefore) {
+			users[revocation.UserID] = revocation.RevokedAt
+		}
+	}
+
+	d.mu.Lock()
+	d.tokens = tokens
+	d.users = users
+	d.mu.Unlock()
+
+	return nil
+}
+
+// Run đồng bộ denylist định kỳ cho tới khi ctx bị hủy
+func (d *TokenDenylist) Run(ctx context.Context, interval time.Duration) {
+	if err := d.Sync(ctx); err != nil {
+		log.Printf("Error syncing token denylist: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.Sync(ctx); err != nil {
+				log.Printf("Error syncing token denylist: %v", err)
+			}
+		}
+	}
+}
+
+// apply cập nhật cache ngay sau khi ghi database để thu hồi có hiệu lực tức thì trên instance hiện tại
+func (d *TokenDenylist) apply(revocation *usermodel.TokenRevocation) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if revocation.JTI != nil {
+		d.tokens[*revocation.JTI] = revocation.ExpiresAt
+		return
+	}
+
+	if revokedBefore, ok := d.users[revocation.UserID]; !ok || revocation.RevokedAt.After(revokedBefore) {
+		d.users[revocation.UserID] = revocation.RevokedAt
+	}
+}
+
+// maxTokenLifetime là thời gian cần giữ bản ghi thu hồi, sau đó mọi token liên quan đã hết hạn
+func (d *TokenDenylist) maxTokenLifetime() time.Duration {
+	return time.Second * time.Duration(d.tokenExpIn)
+}
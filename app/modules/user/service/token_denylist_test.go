@@ -0,0 +1,144 @@
+package userservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+
+	"github.com/google/uuid"
+)
+
+// fakeTokenRevocationRepo giả lập bảng denylist dùng chung giữa các instance
+type fakeTokenRevocationRepo struct {
+	revocations []usermodel.TokenRevocation
+}
+
+func (r *fakeTokenRevocationRepo) InsertTokenRevocation(_ context.Context, revocation *usermodel.TokenRevocation) error {
+	r.revocations = append(r.revocations, *revocation)
+	return nil
+}
+
+func (r *fakeTokenRevocationRepo) ListActiveTokenRevocations(_ context.Context, now time.Time) ([]usermodel.TokenRevocation, error) {
+	var active []usermodel.TokenRevocation
+	for _, revocation := range r.revocations {
+		if revocation.ExpiresAt.After(now) {
+			active = append(active, revocation)
+		}
+	}
+	return active, nil
+}
+
+func (r *fakeTokenRevocationRepo) DeleteExpiredTokenRevocations(_ context.Context, now time.Time) (int64, error) {
+	active, _ := r.ListActiveTokenRevocations(context.Background(), now)
+	deleted := int64(len(r.revocations) - len(active))
+	r.revocations = active
+	return deleted, nil
+}
+
+func TestTokenDenylistRevokeToken(t *testing.T) {
+	ctx := context.Background()
+	denylist := NewTokenDenylist(&fakeTokenRevocationRepo{}, 900)
+	userID, _ := uuid.NewV7()
+
+	if err := denylist.RevokeToken(ctx, userID, "jti-1"); err != nil {
+		t.Fatalf("RevokeToken() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		jti  string
+		want bool
+	}{
+		{name: "revoked jti", jti: "jti-1", want: true},
+		{name: "other jti", jti: "jti-2", want: false},
+	}
+
+	for _, tt := range tests {
+		revoked, err := denylist.IsRevoked(ctx, tt.jti, userID.String(), time.Now())
+		if err != nil {
+			t.Fatalf("%s: IsRevoked() error = %v", tt.name, err)
+		}
+		if revoked != tt.want {
+			t.Errorf("%s: IsRevoked() = %v, want %v", tt.name, revoked, tt.want)
+		}
+	}
+}
+
+func TestTokenDenylistRevokeAllUserTokens(t *testing.T) {
+	ctx := context.Background()
+	denylist := NewTokenDenylist(&fakeTokenRevocationRepo{}, 900)
+	userID, _ := uuid.NewV7()
+	otherUserID, _ := uuid.NewV7()
+
+	before := time.Now().Truncate(time.Second)
+	if err := denylist.RevokeAllUserTokens(ctx, userID); err != nil {
+		t.Fatalf("RevokeAllUserTokens() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		subject  string
+		issuedAt time.Time
+		want     bool
+	}{
+		{name: "issued before revocation", subject: userID.String(), issuedAt: before.Add(-time.Minute), want: true},
+		{name: "issued in the same second", subject: userID.String(), issuedAt: before, want: true},
+		{name: "issued after revocation", subject: userID.String(), issuedAt: before.Add(2 * time.Second), want: false},
+		{name: "other user", subject: otherUserID.String(), issuedAt: before.Add(-time.Minute), want: false},
+	}
+
+	for _, tt := range tests {
+		revoked, err := denylist.IsRevoked(ctx, "jti", tt.subject, tt.issuedAt)
+		if err != nil {
+			t.Fatalf("%s: IsRevoked() error = %v", tt.name, err)
+		}
+		if revoked != tt.want {
+			t.Errorf("%s: IsRevoked() = %v, want %v", tt.name, revoked, tt.want)
+		}
+	}
+
+	if _, err := denylist.IsRevoked(ctx, "jti", "not-a-uuid", before); err == nil {
+		t.Error("IsRevoked() with invalid subject error = nil, want error")
+	}
+}
+
+func TestTokenDenylistSync(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeTokenRevocationRepo{}
+	userID, _ := uuid.NewV7()
+
+	// Thu hồi trên một instance được instance khác nhận sau khi đồng bộ
+	if err := NewTokenDenylist(repo, 900).RevokeToken(ctx, userID, "jti-1"); err != nil {
+		t.Fatalf("RevokeToken() error = %v", err)
+	}
+
+	expiredJTI := "jti-expired"
+	now := time.Now()
+	repo.revocations = append(repo.revocations, usermodel.TokenRevocation{
+		JTI:       &expiredJTI,
+		UserID:    userID,
+		RevokedAt: now.Add(-time.Hour),
+		ExpiresAt: now.Add(-time.Minute),
+	})
+
+	other := NewTokenDenylist(repo, 900)
+	if revoked, _ := other.IsRevoked(ctx, "jti-1", userID.String(), now); revoked {
+		t.Fatal("IsRevoked() before Sync = true, want false")
+	}
+
+	if err := other.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	if revoked, _ := other.IsRevoked(ctx, "jti-1", userID.String(), now); !revoked {
+		t.Error("IsRevoked() after Sync = false, want true")
+	}
+	if revoked, _ := other.IsRevoked(ctx, expiredJTI, userID.String(), now); revoked {
+		t.Error("IsRevoked() of expired revocation = true, want false")
+	}
+	if len(repo.revocations) != 1 {
+		t.Errorf("Sync() kept %d revocations, want expired ones deleted", len(repo.revocations))
+	}
+}
@@ -0,0 +1,135 @@
+package userservice
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared"
+	sharecomponent "fat2fast/ikv/shared/component"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// LoginChallengePurpose phân biệt challenge token đăng nhập hai bước với các loại token ký khác
+const LoginChallengePurpose = "login_challenge"
+
+// totpSkew là số chu kỳ TOTP chấp nhận lệch so với đồng hồ server
+const totpSkew = 1
+
+// ILoginChallenge interface cấp và kiểm tra challenge token cho bước xác thực thứ hai
+type ILoginChallenge interface {
+	Issue(user *usermodel.User) *AuthenticateResult
+	Verify(token string, now time.Time) (uuid.UUID, int, error)
+}
+
+// LoginChallenge cấp challenge token ngắn hạn sau khi user nhập đúng password nhưng còn phải xác thực 2FA.
+// Token gắn với credential version nên đổi mật khẩu sẽ làm challenge đang chờ mất hiệu lực.
+type LoginChallenge struct {
+	signer ITokenSigner
+	expIn  time.Duration
+}
+
+// NewLoginChallenge khởi tạo LoginChallenge mới
+func NewLoginChallenge(signer ITokenSigner, expIn time.Duration) *LoginChallenge {
+	return &LoginChallenge{signer: signer, expIn: expIn}
+}
+
+// Issue tạo kết quả đăng nhập chỉ chứa challenge token, chưa có access token
+func (c *LoginChallenge) Issue(user *usermodel.User) *AuthenticateResult {
+	subject := user.ID.String() + ":" + strconv.Itoa(user.CredentialVersion)
+
+	return &AuthenticateResult{
+		MfaRequired:    true,
+		ChallengeToken: c.signer.Sign(LoginChallengePurpose, subject, time.Now().Add(c.expIn)),
+		ChallengeExpIn: int(c.expIn.Seconds()),
+	}
+}
+
+// Verify kiểm tra challenge token, trả về user ID và credential version lúc cấp token
+func (c *LoginChallenge) Verify(token string, now time.Time) (uuid.UUID, int, error) {
+	subject, err := c.signer.Verify(LoginChallengePurpose, token, now)
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+
+	rawID, rawVersion, ok := strings.Cut(subject, ":")
+	if !ok {
+		return uuid.Nil, 0, sharecomponent.ErrSignedTokenInvalid
+	}
+	userID, err := uuid.Parse(rawID)
+	if err != nil {
+		return uuid.Nil, 0, sharecomponent.ErrSignedTokenInvalid
+	}
+	version, err := strconv.Atoi(rawVersion)
+	if err != nil {
+		return uuid.Nil, 0, sharecomponent.ErrSignedTokenInvalid
+	}
+
+	return userID, version, nil
+}
+
+// ITotpStepRepo interface ghi nhận chu kỳ TOTP đã dùng
+type ITotpStepRepo interface {
+	UseTotpStep(ctx context.Context, userID uuid.UUID, step int64, now time.Time) (bool, error)
+}
+
+// verifyTotpCode kiểm tra mã TOTP và đánh dấu chu kỳ đã dùng để mã không dùng lại được
+func verifyTotpCode(ctx context.Context, repo ITotpStepRepo, credential *usermodel.TotpCredential, code string, now time.Time) (bool, error) {
+	step, ok := sharecomponent.ValidateTOTP(credential.Secret, code, now, totpSkew)
+	if !ok {
+		return false, nil
+	}
+	if credential.LastUsedStep != nil && step <= *credential.LastUsedStep {
+		return false, nil
+	}
+
+	return repo.UseTotpStep(ctx, credential.UserID, step, now)
+}
+
+// generateRecoveryCodes sinh count mã khôi phục, trả về mã gốc (hiển thị một lần cho user) và bản ghi đã băm
+func generateRecoveryCodes(userID uuid.UUID, count int, now time.Time) ([]string, []usermodel.RecoveryCode, error) {
+	codes := make([]string, 0, count)
+	records := make([]usermodel.RecoveryCode, 0, count)
+
+	for i := 0; i < count; i++ {
+		raw, err := shared.RandomStr(5)
+		if err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		code := raw[:5] + "-" + raw[5:]
+
+		newId, _ := uuid.NewV7()
+		codes = append(codes, code)
+		records = append(records, usermodel.RecoveryCode{
+			ID:        newId,
+			UserID:    userID,
+			CodeHash:  shared.HashToken(normalizeRecoveryCode(code)),
+			CreatedAt: now,
+		})
+	}
+
+	return codes, records, nil
+}
+
+// normalizeRecoveryCode bỏ dấu gạch, khoảng trắng và chuyển về chữ thường trước khi băm
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// isTotpCode kiểm tra chuỗi có dạng mã TOTP (6 chữ số) hay không
+func isTotpCode(code string) bool {
+	if len(code) != sharecomponent.TOTPDigits {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,150 @@
+package userservice
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactorLoginCommand đại diện cho bước thứ hai của đăng nhập khi user đã bật 2FA
+type TwoFactorLoginCommand struct {
+	IPAddress string
+	UserAgent string
+	Dto       usermodel.TwoFactorLoginForm
+}
+
+// ITwoFactorLoginRepo interface cho repository operations cần thiết
+type ITwoFactorLoginRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	FindTotpCredential(ctx context.Context, userID uuid.UUID) (*usermodel.TotpCredential, error)
+	UseTotpStep(ctx context.Context, userID uuid.UUID, step int64, now time.Time) (bool, error)
+	ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string, now time.Time) (bool, error)
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// TwoFactorLoginCommandHandler đổi challenge token và mã TOTP (hoặc mã khôi phục) lấy cặp token thật
+type TwoFactorLoginCommandHandler struct {
+	repo            ITwoFactorLoginRepo
+	loginChallenge  ILoginChallenge
+	loginThrottle   ILoginThrottle
+	tokenPairIssuer ITokenPairIssuer
+}
+
+// NewTwoFactorLoginCommandHandler khởi tạo handler mới
+func NewTwoFactorLoginCommandHandler(repo ITwoFactorLoginRepo, loginChallenge ILoginChallenge, loginThrottle ILoginThrottle, tokenPairIssuer ITokenPairIssuer) *TwoFactorLoginCommandHandler {
+	return &TwoFactorLoginCommandHandler{
+		repo:            repo,
+		loginChallenge:  loginChallenge,
+		loginThrottle:   loginThrottle,
+		tokenPairIssuer: tokenPairIssuer,
+	}
+}
+
+// Execute kiểm tra challenge token và mã xác thực. Mã sai được đếm chung với số lần sai password
+// nên không thể dò mã TOTP bằng cách đăng nhập lại nhiều lần.
+func (hdl *TwoFactorLoginCommandHandler) Execute(ctx context.Context, cmd *TwoFactorLoginCommand) (*AuthenticateResult, error) {
+	now := time.Now()
+
+	userID, credentialVersion, err := hdl.loginChallenge.Verify(cmd.Dto.ChallengeToken, now)
+	if err != nil {
+		return nil, datatype.ErrUnauthorized.WithError(usermodel.ErrInvalidLoginChallenge.Error())
+	}
+
+	user, err := hdl.repo.FindById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, datatype.ErrUnauthorized.WithError(usermodel.ErrInvalidLoginChallenge.Error())
+		}
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if user.CredentialVersion != credentialVersion || user.Status != usermodel.StatusActive || !user.IsTwoFactorEnabled() {
+		return nil, datatype.ErrUnauthorized.WithError(usermodel.ErrInvalidLoginChallenge.Error())
+	}
+
+	client := SessionClient{IPAddress: cmd.IPAddress, UserAgent: cmd.UserAgent}
+	attempt := loginAttempt{Method: usermodel.LoginMethodTwoFactor, Client: client, Now: now}
+	if err := hdl.loginThrottle.Check(ctx, user.Email, cmd.IPAddress, now); err != nil {
+		if isLoginThrottled(err) {
+			recordLoginFailed(ctx, hdl.repo, user, "", usermodel.LoginFailureReasonThrottled, attempt)
+		}
+		return nil, err
+	}
+
+	code := strings.TrimSpace(cmd.Dto.Code)
+	usedRecoveryCode := !isTotpCode(code)
+
+	var valid bool
+	if usedRecoveryCode {
+		valid, err = hdl.repo.ConsumeRecoveryCode(ctx, user.ID, shared.HashToken(normalizeRecoveryCode(code)), now)
+	} else {
+		valid, err = hdl.verifyTotp(ctx, user.ID, code, now)
+	}
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if !valid {
+		recordLoginFailed(ctx, hdl.repo, user, "", usermodel.LoginFailureReasonInvalidTwoFactorCode, attempt)
+		locked, err := hdl.loginThrottle.RecordFailure(ctx, user.Email, cmd.IPAddress, now)
+		if err != nil {
+			return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+		}
+		if locked {
+			recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+				UserID:    &user.ID,
+				Event:     usermodel.AuditEventAccountLocked,
+				IPAddress: cmd.IPAddress,
+				UserAgent: cmd.UserAgent,
+				CreatedAt: now,
+			})
+		}
+		return nil, datatype.ErrBadRequest.WithError(usermodel.ErrInvalidTwoFactorCode.Error())
+	}
+
+	if err := hdl.loginThrottle.RecordSuccess(ctx, user.Email); err != nil {
+		log.Printf("Error clearing login failures for user %s: %v", user.ID, err)
+	}
+
+	if usedRecoveryCode {
+		recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+			UserID:    &user.ID,
+			ActorID:   &user.ID,
+			Event:     usermodel.AuditEventRecoveryCodeUsed,
+			IPAddress: cmd.IPAddress,
+			UserAgent: cmd.UserAgent,
+			CreatedAt: now,
+		})
+	}
+
+	result, err := hdl.tokenPairIssuer.StartSession(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	recordLoginSucceeded(ctx, hdl.repo, user, attempt)
+
+	return result, nil
+}
+
+func (hdl *TwoFactorLoginCommandHandler) verifyTotp(ctx context.Context, userID uuid.UUID, code string, now time.Time) (bool, error) {
+	credential, err := hdl.repo.FindTotpCredential(ctx, userID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	if credential.ConfirmedAt == nil {
+		return false, nil
+	}
+
+	return verifyTotpCode(ctx, hdl.repo, credential, code, now)
+}
@@ -0,0 +1,63 @@
+package userservice
+
+import (
+	"context"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// UnlockAccountCommand đại diện cho command admin mở khóa đăng nhập của một tài khoản
+type UnlockAccountCommand struct {
+	UserID    uuid.UUID
+	ActorID   uuid.UUID
+	IPAddress string
+	UserAgent string
+}
+
+// IUnlockAccountRepo interface cho repository operations cần thiết
+type IUnlockAccountRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	InsertAuditEvent(ctx context.Context, event *usermodel.AuditEvent) error
+}
+
+// UnlockAccountCommandHandler xóa lượt khóa và bộ đếm đăng nhập sai của tài khoản
+type UnlockAccountCommandHandler struct {
+	repo          IUnlockAccountRepo
+	loginThrottle ILoginThrottle
+}
+
+// NewUnlockAccountCommandHandler khởi tạo handler mới
+func NewUnlockAccountCommandHandler(repo IUnlockAccountRepo, loginThrottle ILoginThrottle) *UnlockAccountCommandHandler {
+	return &UnlockAccountCommandHandler{repo: repo, loginThrottle: loginThrottle}
+}
+
+// Execute mở khóa đăng nhập theo email của user
+func (hdl *UnlockAccountCommandHandler) Execute(ctx context.Context, cmd *UnlockAccountCommand) error {
+	user, err := hdl.repo.FindById(ctx, cmd.UserID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return datatype.ErrNotFound.WithError(usermodel.ErrUserNotFound.Error())
+		}
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if err := hdl.loginThrottle.Unlock(ctx, user.Email); err != nil {
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	recordAuditEvent(ctx, hdl.repo, &usermodel.AuditEvent{
+		UserID:    &user.ID,
+		ActorID:   &cmd.ActorID,
+		Event:     usermodel.AuditEventAccountUnlocked,
+		IPAddress: cmd.IPAddress,
+		UserAgent: cmd.UserAgent,
+		CreatedAt: time.Now(),
+	})
+
+	return nil
+}
@@ -11,10 +11,12 @@ import (
 	"github.com/pkg/errors"
 )
 
-// UpdateProfileCommand đại diện cho command cập nhật profile user
+// UpdateProfileCommand đại diện cho command cập nhật profile user.
+// Requester là người gọi API, chỉ được sửa profile của chính mình trừ khi là admin.
 type UpdateProfileCommand struct {
-	UserID uuid.UUID
-	Dto    usermodel.UpdateProfileRequest
+	UserID    uuid.UUID
+	Requester datatype.Requester
+	Dto       usermodel.UpdateProfileRequest
 }
 
 // IUpdateProfileRepo interface cho repository operations cần thiết
@@ -25,12 +27,13 @@ type IUpdateProfileRepo interface {
 
 // UpdateProfileCommandHandler xử lý command cập nhật profile
 type UpdateProfileCommandHandler struct {
-	repo IUpdateProfileRepo
+	repo             IUpdateProfileRepo
+	phoneCountryCode string
 }
 
-// NewUpdateProfileCommandHandler khởi tạo handler mới
-func NewUpdateProfileCommandHandler(repo IUpdateProfileRepo) *UpdateProfileCommandHandler {
-	return &UpdateProfileCommandHandler{repo: repo}
+// NewUpdateProfileCommandHandler khởi tạo handler mới, phoneCountryCode là mã quốc gia gắn cho số điện thoại nhập theo định dạng trong nước
+func NewUpdateProfileCommandHandler(repo IUpdateProfileRepo, phoneCountryCode string) *UpdateProfileCommandHandler {
+	return &UpdateProfileCommandHandler{repo: repo, phoneCountryCode: phoneCountryCode}
 }
 
 // Execute thực thi command cập nhật profile
@@ -40,6 +43,10 @@ func (hdl *UpdateProfileCommandHandler) Execute(ctx context.Context, cmd *Update
 		return datatype.ErrBadRequest.WithError("User ID is required")
 	}
 
+	if err := authorizeProfileAccess(cmd.Requester, cmd.UserID, usermodel.ScopeUsersWrite); err != nil {
+		return err
+	}
+
 	// Kiểm tra user có tồn tại không
 	user, err := hdl.repo.FindById(ctx, cmd.UserID)
 	if err != nil {
@@ -70,12 +77,20 @@ func (hdl *UpdateProfileCommandHandler) Execute(ctx context.Context, cmd *Update
 	}
 
 	if cmd.Dto.Phone != "" {
-		updates["phone"] = cmd.Dto.Phone
+		phone, err := usermodel.NormalizePhone(cmd.Dto.Phone, hdl.phoneCountryCode)
+		if err != nil {
+			return datatype.ErrBadRequest.WithError(err.Error())
+		}
+		updates["phone"] = phone
+		// Số điện thoại mới phải được xác minh lại
+		if phone != user.Phone {
+			updates["phone_verified_at"] = nil
+		}
 	}
 
 	// Thêm thông tin audit
 	updates["updated_at"] = time.Now()
-	updates["updated_by"] = cmd.UserID.String()
+	updates["updated_by"] = datatype.RealActorID(cmd.Requester).String()
 
 	// Kiểm tra có thay đổi gì không
 	if len(updates) <= 2 { // Chỉ có updated_at và updated_by
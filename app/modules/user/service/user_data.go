@@ -0,0 +1,125 @@
+package userservice
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+)
+
+// erasedEmailDomain là domain của email thay thế khi xóa dữ liệu user (.invalid không bao giờ nhận được email)
+const erasedEmailDomain = "erased.invalid"
+
+// UserDataQuery đại diện cho query lấy dữ liệu module User lưu về một user
+type UserDataQuery struct {
+	UserID uuid.UUID
+}
+
+// IUserDataRepo interface cho repository operations cần thiết
+type IUserDataRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	ListUserIdentities(ctx context.Context, userID uuid.UUID) ([]*usermodel.UserIdentity, error)
+	ListUserSessions(ctx context.Context, userID uuid.UUID) ([]*usermodel.Session, error)
+	ListUserAPIKeys(ctx context.Context, userID uuid.UUID) ([]*usermodel.APIKey, error)
+	ListUserAuditEvents(ctx context.Context, userID uuid.UUID) ([]*usermodel.AuditEvent, error)
+	ListUserOrganizations(ctx context.Context, userID uuid.UUID) ([]*usermodel.UserOrganization, error)
+}
+
+// UserDataQueryHandler thu thập dữ liệu của module User cho bản trích xuất dữ liệu cá nhân
+type UserDataQueryHandler struct {
+	repo  IUserDataRepo
+	files IFileURLResolver
+}
+
+// NewUserDataQueryHandler khởi tạo handler mới
+func NewUserDataQueryHandler(repo IUserDataRepo, files IFileURLResolver) *UserDataQueryHandler {
+	return &UserDataQueryHandler{repo: repo, files: files}
+}
+
+// Execute trả về dữ liệu của user, nil nếu user không tồn tại
+func (hdl *UserDataQueryHandler) Execute(ctx context.Context, query *UserDataQuery) (*usermodel.UserData, error) {
+	user, err := hdl.repo.FindById(ctx, query.UserID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	data := &usermodel.UserData{
+		Profile:          toProfileResponse(user, hdl.files),
+		EmailVerifiedAt:  user.EmailVerifiedAt,
+		TwoFactorEnabled: user.IsTwoFactorEnabled(),
+	}
+
+	if data.Identities, err = hdl.repo.ListUserIdentities(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	if data.Sessions, err = hdl.repo.ListUserSessions(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	if data.APIKeys, err = hdl.repo.ListUserAPIKeys(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	if data.AuditEvents, err = hdl.repo.ListUserAuditEvents(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	if data.Organizations, err = hdl.repo.ListUserOrganizations(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// AnonymizeUserCommand đại diện cho command xóa dữ liệu module User lưu về một user
+type AnonymizeUserCommand struct {
+	UserID uuid.UUID
+}
+
+// IAnonymizeUserRepo interface cho repository operations cần thiết
+type IAnonymizeUserRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	AnonymizeUser(ctx context.Context, userID uuid.UUID, anonymizedEmail string, loginIdentifier string, now time.Time) error
+}
+
+// AnonymizeUserCommandHandler ẩn danh bản ghi user và xóa dữ liệu phụ của user, kể cả file ảnh đại diện.
+// Bản ghi user được giữ lại (trạng thái deleted) để các sự kiện audit và dữ liệu của module khác vẫn tham chiếu được.
+type AnonymizeUserCommandHandler struct {
+	repo    IAnonymizeUserRepo
+	storage IFileStorage
+}
+
+// NewAnonymizeUserCommandHandler khởi tạo handler mới
+func NewAnonymizeUserCommandHandler(repo IAnonymizeUserRepo, storage IFileStorage) *AnonymizeUserCommandHandler {
+	return &AnonymizeUserCommandHandler{repo: repo, storage: storage}
+}
+
+// Execute ẩn danh user, bỏ qua nếu user không tồn tại hoặc đã bị xóa dữ liệu
+func (hdl *AnonymizeUserCommandHandler) Execute(ctx context.Context, cmd *AnonymizeUserCommand) error {
+	user, err := hdl.repo.FindById(ctx, cmd.UserID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.IsErased() {
+		return nil
+	}
+
+	// Email vẫn phải duy nhất và vừa cột varchar(50) nên dùng ID bỏ dấu gạch
+	anonymizedEmail := strings.ReplaceAll(user.ID.String(), "-", "") + "@" + erasedEmailDomain
+
+	if err := hdl.repo.AnonymizeUser(ctx, user.ID, anonymizedEmail, normalizeLoginEmail(user.Email), time.Now()); err != nil {
+		return err
+	}
+
+	deleteAvatarFiles(ctx, hdl.storage, user.AvatarKey, user.AvatarThumbnailKey)
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package userservice
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// VerifyEmailCommand đại diện cho command xác minh email từ link đã gửi
+type VerifyEmailCommand struct {
+	Dto usermodel.VerifyEmailForm
+}
+
+// IVerifyEmailRepo interface cho repository operations cần thiết
+type IVerifyEmailRepo interface {
+	FindById(ctx context.Context, id uuid.UUID) (*usermodel.User, error)
+	MarkEmailVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error
+}
+
+// VerifyEmailCommandHandler kích hoạt tài khoản khi link xác minh hợp lệ
+type VerifyEmailCommandHandler struct {
+	repo   IVerifyEmailRepo
+	signer ITokenSigner
+}
+
+// NewVerifyEmailCommandHandler khởi tạo handler mới
+func NewVerifyEmailCommandHandler(repo IVerifyEmailRepo, signer ITokenSigner) *VerifyEmailCommandHandler {
+	return &VerifyEmailCommandHandler{repo: repo, signer: signer}
+}
+
+// Execute kiểm tra token và kích hoạt user, mở lại link của tài khoản đã active không gây lỗi
+func (hdl *VerifyEmailCommandHandler) Execute(ctx context.Context, cmd *VerifyEmailCommand) error {
+	now := time.Now()
+
+	subject, err := hdl.signer.Verify(EmailVerificationPurpose, cmd.Dto.Token, now)
+	if err != nil {
+		return datatype.ErrBadRequest.WithError(usermodel.ErrInvalidVerifyToken.Error()).WithDebug(err.Error())
+	}
+
+	rawUserID, email, _ := strings.Cut(subject, ":")
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		return datatype.ErrBadRequest.WithError(usermodel.ErrInvalidVerifyToken.Error())
+	}
+
+	user, err := hdl.repo.FindById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, datatype.ErrRecordNotFound) {
+			return datatype.ErrBadRequest.WithError(usermodel.ErrInvalidVerifyToken.Error())
+		}
+		return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	// Link cũ không còn hiệu lực nếu email của user đã thay đổi
+	if user.Email != email {
+		return datatype.ErrBadRequest.WithError(usermodel.ErrInvalidVerifyToken.Error())
+	}
+
+	switch user.Status {
+	case usermodel.StatusPending:
+		if err := hdl.repo.MarkEmailVerified(ctx, user.ID, now); err != nil {
+			return datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+		}
+	case usermodel.StatusBanned, usermodel.StatusDeleted:
+		return datatype.ErrBadRequest.WithError(usermodel.ErrUserBannedOrDeleted.Error())
+	}
+
+	return nil
+}
@@ -4,33 +4,323 @@ import (
 	"net/http"
 
 	userhttpgin "fat2fast/ikv/modules/user/infras/controller/http-gin"
+	usermodel "fat2fast/ikv/modules/user/model"
+	"fat2fast/ikv/shared"
+	"fat2fast/ikv/shared/datatype"
+	sharedinfras "fat2fast/ikv/shared/infras"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetUserRoutes trả về danh sách routes cho user module v1
-func GetRoutes(controller *userhttpgin.UserHTTPController) []gin.RouteInfo {
+func GetRoutes(controller *userhttpgin.UserHTTPController, mldProvider sharedinfras.IMiddlewareProvider) []shared.Route {
 
-	return []gin.RouteInfo{
+	return []shared.Route{
 		{
 			Method:      http.MethodPost,
 			Path:        "/authenticate",
 			HandlerFunc: controller.ActionAuthenticate,
 		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/authenticate/2fa",
+			HandlerFunc: controller.ActionAuthenticateTwoFactor,
+		},
 		{
 			Method:      http.MethodPost,
 			Path:        "/register",
 			HandlerFunc: controller.ActionRegister,
 		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/token/refresh",
+			HandlerFunc: controller.ActionRefreshToken,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/introspect",
+			HandlerFunc: controller.ActionIntrospect,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/email/verify",
+			HandlerFunc: controller.ActionVerifyEmail,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/email/verification/resend",
+			HandlerFunc: controller.ActionResendVerification,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/email/change/confirm",
+			HandlerFunc: controller.ActionConfirmEmailChange,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/password/forgot",
+			HandlerFunc: controller.ActionForgotPassword,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/password/reset",
+			HandlerFunc: controller.ActionResetPassword,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/oauth/:provider/authorize",
+			HandlerFunc: controller.ActionOAuthAuthorize,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/oauth/:provider/callback",
+			HandlerFunc: controller.ActionOAuthCallback,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/logout",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage)},
+			HandlerFunc: controller.ActionLogout,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/logout/all",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage), mldProvider.DenyImpersonation()},
+			HandlerFunc: controller.ActionLogoutAll,
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/password",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage), mldProvider.DenyImpersonation()},
+			HandlerFunc: controller.ActionChangePassword,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/email/change",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage), mldProvider.DenyImpersonation()},
+			HandlerFunc: controller.ActionChangeEmail,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/phone/verification/send",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage), mldProvider.DenyImpersonation()},
+			HandlerFunc: controller.ActionSendPhoneVerification,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/phone/verification/verify",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage), mldProvider.DenyImpersonation()},
+			HandlerFunc: controller.ActionVerifyPhone,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/2fa/totp/enroll",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage), mldProvider.DenyImpersonation()},
+			HandlerFunc: controller.ActionEnrollTotp,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/2fa/totp/confirm",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage), mldProvider.DenyImpersonation()},
+			HandlerFunc: controller.ActionConfirmTotp,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/api-keys",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage), mldProvider.DenyImpersonation()},
+			HandlerFunc: controller.ActionCreateAPIKey,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/api-keys",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage)},
+			HandlerFunc: controller.ActionListAPIKeys,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/api-keys/:keyId",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage), mldProvider.DenyImpersonation()},
+			HandlerFunc: controller.ActionRevokeAPIKey,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/sessions",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage)},
+			HandlerFunc: controller.ActionListSessions,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/sessions/:sessionId",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage), mldProvider.DenyImpersonation()},
+			HandlerFunc: controller.ActionRevokeSession,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/data-export",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage), mldProvider.DenyImpersonation()},
+			HandlerFunc: controller.ActionExportMyData,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/organizations",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage)},
+			HandlerFunc: controller.ActionCreateOrganization,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/organizations",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage)},
+			HandlerFunc: controller.ActionListOrganizations,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/organizations/invitations/accept",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage)},
+			HandlerFunc: controller.ActionAcceptOrganizationInvitation,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/organizations/:orgId/members",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage)},
+			HandlerFunc: controller.ActionListOrganizationMembers,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/organizations/:orgId/invitations",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage)},
+			HandlerFunc: controller.ActionInviteOrganizationMember,
+		},
+		{
+			Method:      http.MethodPatch,
+			Path:        "/organizations/:orgId/members/:userId/role",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage)},
+			HandlerFunc: controller.ActionChangeOrganizationMemberRole,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/organizations/:orgId/members/:userId",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage)},
+			HandlerFunc: controller.ActionRemoveOrganizationMember,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/organizations/:orgId/switch",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(datatype.ScopeAccountManage), mldProvider.DenyImpersonation()},
+			HandlerFunc: controller.ActionSwitchOrganization,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.CheckRoles(datatype.RoleAdmin), mldProvider.RequireScopes(usermodel.ScopeUsersRead)},
+			HandlerFunc: controller.ActionListUsers,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/audit-events",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.CheckRoles(datatype.RoleAdmin), mldProvider.RequireScopes(usermodel.ScopeUsersRead)},
+			HandlerFunc: controller.ActionListAuditEvents,
+		},
+		{
+			Method:      http.MethodPatch,
+			Path:        "/:id/role",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.CheckRoles(datatype.RoleAdmin), mldProvider.RequireScopes(usermodel.ScopeUsersWrite)},
+			HandlerFunc: controller.ActionChangeUserRole,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/:id/impersonate",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.CheckRoles(datatype.RoleAdmin), mldProvider.RequireScopes(datatype.ScopeAccountManage)},
+			HandlerFunc: controller.ActionImpersonateUser,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/:id/ban",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.CheckRoles(datatype.RoleAdmin), mldProvider.RequireScopes(usermodel.ScopeUsersWrite)},
+			HandlerFunc: controller.ActionBanUser,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/:id/unban",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.CheckRoles(datatype.RoleAdmin), mldProvider.RequireScopes(usermodel.ScopeUsersWrite)},
+			HandlerFunc: controller.ActionUnbanUser,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/:id",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.CheckRoles(datatype.RoleAdmin), mldProvider.RequireScopes(usermodel.ScopeUsersWrite)},
+			HandlerFunc: controller.ActionDeleteUser,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/:id/restore",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.CheckRoles(datatype.RoleAdmin), mldProvider.RequireScopes(usermodel.ScopeUsersWrite)},
+			HandlerFunc: controller.ActionRestoreUser,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/:id/unlock",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.CheckRoles(datatype.RoleAdmin), mldProvider.RequireScopes(usermodel.ScopeUsersWrite)},
+			HandlerFunc: controller.ActionUnlockAccount,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/:id/data-export",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.CheckRoles(datatype.RoleAdmin), mldProvider.RequireScopes(usermodel.ScopeUsersRead)},
+			HandlerFunc: controller.ActionExportUserData,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/:id/erase",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.CheckRoles(datatype.RoleAdmin), mldProvider.RequireScopes(usermodel.ScopeUsersWrite)},
+			HandlerFunc: controller.ActionEraseUserData,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/me",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(usermodel.ScopeProfileRead)},
+			HandlerFunc: controller.ActionGetMyProfile,
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/me",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(usermodel.ScopeProfileWrite)},
+			HandlerFunc: controller.ActionUpdateMyProfile,
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/me/avatar",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(usermodel.ScopeProfileWrite)},
+			HandlerFunc: controller.ActionUpdateMyAvatar,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/me/avatar",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(usermodel.ScopeProfileWrite)},
+			HandlerFunc: controller.ActionDeleteMyAvatar,
+		},
 		{
 			Method:      http.MethodGet,
 			Path:        "/profile/:id",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(usermodel.ScopeProfileRead)},
 			HandlerFunc: controller.ActionGetProfile,
 		},
 		{
 			Method:      http.MethodPut,
 			Path:        "/profile/:id",
+			Middlewares: []gin.HandlerFunc{mldProvider.Auth(), mldProvider.RequireScopes(usermodel.ScopeProfileWrite)},
 			HandlerFunc: controller.ActionUpdateProfile,
 		},
 	}
 }
+
+// GetWellKnownRoutes trả về các route công khai đăng ký ở gốc router (không nằm dưới /v1/users)
+func GetWellKnownRoutes(controller *userhttpgin.UserHTTPController) []shared.Route {
+
+	return []shared.Route{
+		{
+			Method:      http.MethodGet,
+			Path:        "/.well-known/jwks.json",
+			HandlerFunc: controller.ActionJWKS,
+		},
+	}
+}
@@ -0,0 +1,122 @@
+package sharecomponent
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// sha1HexLength là độ dài SHA-1 dạng hex ở đầu mỗi dòng
+const sha1HexLength = 40
+
+// BreachedPasswordList tra cứu offline danh sách mật khẩu bị lộ. File gồm các dòng
+// "<SHA-1 hex>[:<số lần xuất hiện>]" sắp xếp tăng dần theo hash (định dạng "ordered by hash" của Pwned Passwords),
+// được tìm kiếm nhị phân trực tiếp trên file nên không cần nạp vào bộ nhớ.
+type BreachedPasswordList struct {
+	file *os.File
+	size int64
+}
+
+// OpenBreachedPasswordList mở file danh sách mật khẩu bị lộ
+func OpenBreachedPasswordList(path string) (*BreachedPasswordList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, errors.WithStack(err)
+	}
+
+	return &BreachedPasswordList{file: file, size: info.Size()}, nil
+}
+
+// Contains kiểm tra SHA-1 của password có trong danh sách không
+func (l *BreachedPasswordList) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	target := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	// Bất biến: nếu có dòng khớp thì vị trí bắt đầu của dòng nằm trong [lo, hi)
+	lo, hi := int64(0), l.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+
+		start := mid
+		if mid > 0 {
+			newline, err := l.indexNewline(mid - 1)
+			if err != nil {
+				return false, err
+			}
+			start = newline + 1
+		}
+		if start >= hi {
+			hi = mid
+			continue
+		}
+
+		line, end, err := l.readLine(start)
+		if err != nil {
+			return false, err
+		}
+
+		hash, _, _ := strings.Cut(line, ":")
+		switch hash = strings.ToUpper(strings.TrimSpace(hash)); {
+		case hash == target:
+			return true, nil
+		case hash < target:
+			lo = end + 1
+		default:
+			hi = start
+		}
+	}
+
+	return false, nil
+}
+
+// Close đóng file danh sách
+func (l *BreachedPasswordList) Close() error {
+	return l.file.Close()
+}
+
+// indexNewline trả về vị trí ký tự xuống dòng đầu tiên từ offset, hoặc kích thước file nếu không còn
+func (l *BreachedPasswordList) indexNewline(offset int64) (int64, error) {
+	buf := make([]byte, 256)
+	for offset < l.size {
+		n, err := l.file.ReadAt(buf, offset)
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			return offset + int64(i), nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, errors.WithStack(err)
+		}
+		if n == 0 {
+			break
+		}
+		offset += int64(n)
+	}
+
+	return l.size, nil
+}
+
+// readLine đọc phần đầu (đủ chứa hash) của dòng bắt đầu tại start, trả về kèm vị trí kết thúc dòng
+func (l *BreachedPasswordList) readLine(start int64) (string, int64, error) {
+	end, err := l.indexNewline(start)
+	if err != nil {
+		return "", 0, err
+	}
+
+	length := min(end-start, sha1HexLength+1)
+	buf := make([]byte, length)
+	if _, err := l.file.ReadAt(buf, start); err != nil && err != io.EOF {
+		return "", 0, errors.WithStack(err)
+	}
+
+	return string(buf), end, nil
+}
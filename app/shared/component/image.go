@@ -0,0 +1,110 @@
+package sharecomponent
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrImageInvalid  = errors.New("image is invalid or in an unsupported format")
+	ErrImageTooLarge = errors.New("image dimensions are too large")
+)
+
+// DecodeImage giải mã ảnh JPEG, PNG hoặc GIF (frame đầu tiên).
+// Kích thước được đọc từ header trước khi giải mã để từ chối ảnh quá maxPixels điểm ảnh (chống decompression bomb).
+func DecodeImage(data []byte, maxPixels int64) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(ErrImageInvalid, err.Error())
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, ErrImageInvalid
+	}
+	if int64(config.Width)*int64(config.Height) > maxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(ErrImageInvalid, err.Error())
+	}
+
+	return img, nil
+}
+
+// SquareThumbnail cắt phần vuông ở giữa ảnh rồi thu nhỏ về cạnh size bằng bộ lọc trung bình vùng.
+// Ảnh nhỏ hơn size không bị phóng to.
+func SquareThumbnail(src image.Image, size int) *image.RGBA {
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	offset := image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2)
+
+	cropped := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(cropped, cropped.Bounds(), src, offset, draw.Src)
+
+	if side <= size {
+		return cropped
+	}
+
+	return downscale(cropped, size, size)
+}
+
+// EncodeJPEG mã hóa ảnh thành JPEG, vùng trong suốt được phủ nền trắng.
+// Ảnh được vẽ lại từ điểm ảnh nên metadata (EXIF...) của file gốc không còn.
+func EncodeJPEG(img image.Image, quality int) ([]byte, error) {
+	bounds := img.Bounds()
+	canvas := image.NewRGBA(bounds)
+	draw.Draw(canvas, bounds, image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(canvas, bounds, img, bounds.Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, canvas, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// downscale thu nhỏ ảnh (gốc tọa độ 0,0) về width x height, mỗi điểm ảnh đích là trung bình các điểm ảnh nguồn nó phủ
+func downscale(src *image.RGBA, width int, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+
+	for y := 0; y < height; y++ {
+		sy0 := y * srcHeight / height
+		sy1 := max((y+1)*srcHeight/height, sy0+1)
+
+		for x := 0; x < width; x++ {
+			sx0 := x * srcWidth / width
+			sx1 := max((x+1)*srcWidth/width, sx0+1)
+
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				offset := src.PixOffset(sx0, sy)
+				for sx := sx0; sx < sx1; sx++ {
+					r += uint64(src.Pix[offset])
+					g += uint64(src.Pix[offset+1])
+					b += uint64(src.Pix[offset+2])
+					a += uint64(src.Pix[offset+3])
+					offset += 4
+				}
+				n += uint64(sx1 - sx0)
+			}
+
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
@@ -5,32 +5,107 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 )
 
+// ITokenDenylist kiểm tra token đã bị thu hồi phía server chưa
+type ITokenDenylist interface {
+	IsRevoked(ctx context.Context, jti string, subject string, issuedAt time.Time) (bool, error)
+}
+
+// TokenClaims là claims của access token: registered claims kèm các claim riêng của hệ thống
+type TokenClaims struct {
+	jwt.RegisteredClaims
+	// CredentialVersion tăng mỗi khi user đổi mật khẩu, token mang version cũ bị từ chối
+	CredentialVersion int `json:"ver"`
+	// SessionID là ID phiên đăng nhập đã cấp token, token của phiên bị thu hồi bị từ chối
+	SessionID string `json:"sid,omitempty"`
+	// OrganizationID là tổ chức đang hoạt động của phiên, OrganizationRole là role của user trong tổ chức lúc cấp token.
+	// Service verify offline chỉ nên dùng role để hiển thị, quyền thực tế được kiểm tra lại khi introspect.
+	OrganizationID   string `json:"org,omitempty"`
+	OrganizationRole string `json:"org_role,omitempty"`
+	// Actor là người thực sự dùng token khi admin impersonate user (RFC 8693), Subject khi đó là user bị impersonate
+	Actor *TokenActor `json:"act,omitempty"`
+}
+
+// TokenActor là claim "act" của token impersonation
+type TokenActor struct {
+	Subject string `json:"sub"`
+}
+
+// MinHMACSecretLength là độ dài tối thiểu (byte) của secret HS256, bằng kích thước output của SHA-256 (RFC 7518)
+const MinHMACSecretLength = 32
+
+// ErrHMACSecretTooShort được trả về khi secret HS256 rỗng hoặc ngắn hơn MinHMACSecretLength
+var ErrHMACSecretTooShort = errors.New("hmac secret must be at least 32 bytes")
+
 type JwtComp struct {
-	secretKey string
-	expIn     int
+	keyRing  *KeyRing
+	expIn    int
+	denylist ITokenDenylist
+}
+
+// NewJwtComp tạo JwtComp ký HS256 bằng một secret dùng chung, secret rỗng hoặc quá ngắn bị từ chối
+func NewJwtComp(secretKey string, expIn int) (*JwtComp, error) {
+	if len(secretKey) < MinHMACSecretLength {
+		return nil, ErrHMACSecretTooShort
+	}
+
+	keyRing, err := NewKeyRing("", NewHMACSigningKey("", []byte(secretKey)))
+	if err != nil {
+		return nil, err
+	}
+	return &JwtComp{keyRing: keyRing, expIn: expIn}, nil
+}
+
+// NewJwtCompWithKeyRing tạo JwtComp ký bằng khóa active của key ring (RS256/EdDSA),
+// các khóa còn lại trong ring vẫn được dùng để verify trong giai đoạn rotate
+func NewJwtCompWithKeyRing(keyRing *KeyRing, expIn int) *JwtComp {
+	return &JwtComp{keyRing: keyRing, expIn: expIn}
+}
+
+// JWKS trả về public key của key ring để các service khác verify token offline
+func (j *JwtComp) JWKS() JWKSet {
+	return j.keyRing.JWKS()
 }
 
-func NewJwtComp(secretKey string, expIn int) *JwtComp {
-	return &JwtComp{secretKey: secretKey, expIn: expIn}
+// SetDenylist thiết lập denylist để Validate từ chối các token đã bị thu hồi
+func (j *JwtComp) SetDenylist(denylist ITokenDenylist) {
+	j.denylist = denylist
 }
 
 func (j *JwtComp) IssueToken(ctx context.Context, userID string) (string, error) {
+	claims := TokenClaims{}
+	claims.Subject = userID
+	return j.IssueTokenWithClaims(ctx, claims)
+}
+
+// IssueTokenWithClaims ký access token với các claim riêng, thời hạn và jti do component tự điền.
+// Claims đặt sẵn ExpiresAt sớm hơn thời hạn mặc định thì giữ nguyên (token ngắn hạn như token impersonation).
+func (j *JwtComp) IssueTokenWithClaims(ctx context.Context, claims TokenClaims) (string, error) {
 	now := time.Now()
-	claims := jwt.RegisteredClaims{
-		Subject:   userID,
-		ExpiresAt: jwt.NewNumericDate(now.Add(time.Second * time.Duration(j.expIn))),
-		NotBefore: jwt.NewNumericDate(now),
-		IssuedAt:  jwt.NewNumericDate(now),
-		ID:        userID,
+	jti, err := uuid.NewV7()
+	if err != nil {
+		return "", errors.WithStack(err)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	expiresAt := now.Add(time.Second * time.Duration(j.expIn))
+	if claims.ExpiresAt == nil || claims.ExpiresAt.After(expiresAt) {
+		claims.ExpiresAt = jwt.NewNumericDate(expiresAt)
+	}
+	claims.NotBefore = jwt.NewNumericDate(now)
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.ID = jti.String()
 
-	// Sign and get the complete encoded token as a string using the secret
-	tokenString, err := token.SignedString([]byte(j.secretKey))
+	signingKey := j.keyRing.Active()
+	token := jwt.NewWithClaims(signingKey.Method, claims)
+	if signingKey.Kid != "" {
+		token.Header["kid"] = signingKey.Kid
+	}
+
+	// Sign and get the complete encoded token as a string using the active key
+	tokenString, err := token.SignedString(signingKey.PrivateKey)
 
 	if err != nil {
 		return "", errors.WithStack(err)
@@ -43,19 +118,56 @@ func (j *JwtComp) ExpIn() int {
 	return j.expIn
 }
 
-func (j *JwtComp) Validate(tokenStr string) (string, error) {
-	var rc jwt.RegisteredClaims
+// ParseToken kiểm tra chữ ký, thời hạn và denylist, trả về claims của token
+func (j *JwtComp) ParseToken(ctx context.Context, tokenStr string) (*TokenClaims, error) {
+	var rc TokenClaims
 
 	token, err := jwt.ParseWithClaims(tokenStr, &rc, func(token *jwt.Token) (interface{}, error) {
-		return []byte(j.secretKey), nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		key, ok := j.keyRing.Get(kid)
+		if !ok {
+			return nil, errors.Errorf("unknown signing key %q", kid)
+		}
+		if key.IsExpired(time.Now()) {
+			return nil, errors.Errorf("signing key %q is no longer accepted", kid)
+		}
+		// Chống tấn công đổi thuật toán: alg của token phải khớp với loại khóa
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, errors.Errorf("unexpected signing method %s for key %q", token.Method.Alg(), kid)
+		}
+		return key.PublicKey, nil
+	}, jwt.WithValidMethods(j.keyRing.Algorithms()))
 
 	if err != nil {
-		return "", errors.WithStack(err)
+		return nil, errors.WithStack(err)
 	}
 
 	if !token.Valid {
-		return "", errors.New("invalid token")
+		return nil, errors.New("invalid token")
+	}
+
+	if j.denylist != nil {
+		var issuedAt time.Time
+		if rc.IssuedAt != nil {
+			issuedAt = rc.IssuedAt.Time
+		}
+
+		revoked, err := j.denylist.IsRevoked(ctx, rc.ID, rc.Subject, issuedAt)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if revoked {
+			return nil, errors.New("token has been revoked")
+		}
+	}
+
+	return &rc, nil
+}
+
+func (j *JwtComp) Validate(tokenStr string) (string, error) {
+	rc, err := j.ParseToken(context.Background(), tokenStr)
+	if err != nil {
+		return "", err
 	}
 
 	return rc.Subject, nil
@@ -0,0 +1,241 @@
+package sharecomponent
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+)
+
+// SigningKey là một khóa trong key ring, định danh bằng kid.
+// PrivateKey nil nghĩa là khóa chỉ dùng để verify (khóa cũ đang trong giai đoạn rotate).
+// NotAfter khác zero thì token ký bằng khóa này bị từ chối sau thời điểm đó.
+type SigningKey struct {
+	Kid        string
+	Method     jwt.SigningMethod
+	PrivateKey interface{}
+	PublicKey  interface{}
+	NotAfter   time.Time
+}
+
+// NewHMACSigningKey tạo khóa HS256 từ secret dùng chung
+func NewHMACSigningKey(kid string, secret []byte) *SigningKey {
+	return &SigningKey{
+		Kid:        kid,
+		Method:     jwt.SigningMethodHS256,
+		PrivateKey: secret,
+		PublicKey:  secret,
+	}
+}
+
+// CanSign kiểm tra khóa có private key để ký token không
+func (k *SigningKey) CanSign() bool {
+	return k.PrivateKey != nil
+}
+
+// IsExpired kiểm tra khóa đã hết thời gian được chấp nhận khi verify
+func (k *SigningKey) IsExpired(now time.Time) bool {
+	return !k.NotAfter.IsZero() && now.After(k.NotAfter)
+}
+
+// LoadSigningKeyFromPEM đọc khóa RSA (RS256) hoặc Ed25519 (EdDSA) từ file PEM
+func LoadSigningKeyFromPEM(kid string, path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return ParseSigningKeyPEM(kid, data)
+}
+
+// ParseSigningKeyPEM parse private key (PKCS#8, PKCS#1) hoặc public key (PKIX, PKCS#1) dạng PEM
+func ParseSigningKeyPEM(kid string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Errorf("key %s: no PEM block found", kid)
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return newSigningKeyFromPrivate(kid, key)
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return newSigningKeyFromPrivate(kid, key)
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return newSigningKeyFromPublic(kid, key)
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return newSigningKeyFromPublic(kid, key)
+	default:
+		return nil, errors.Errorf("key %s: unsupported PEM block type %q", kid, block.Type)
+	}
+}
+
+func newSigningKeyFromPrivate(kid string, key interface{}) (*SigningKey, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return &SigningKey{Kid: kid, Method: jwt.SigningMethodRS256, PrivateKey: k, PublicKey: &k.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{Kid: kid, Method: jwt.SigningMethodEdDSA, PrivateKey: k, PublicKey: k.Public()}, nil
+	default:
+		return nil, errors.Errorf("key %s: unsupported private key type %T", kid, key)
+	}
+}
+
+func newSigningKeyFromPublic(kid string, key interface{}) (*SigningKey, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return &SigningKey{Kid: kid, Method: jwt.SigningMethodRS256, PublicKey: k}, nil
+	case ed25519.PublicKey:
+		return &SigningKey{Kid: kid, Method: jwt.SigningMethodEdDSA, PublicKey: k}, nil
+	default:
+		return nil, errors.Errorf("key %s: unsupported public key type %T", kid, key)
+	}
+}
+
+// KeyRing chứa khóa đang dùng để ký (active) và các khóa cũ chỉ còn dùng để verify
+type KeyRing struct {
+	activeKid string
+	keys      map[string]*SigningKey
+}
+
+// NewKeyRing tạo key ring, khóa activeKid bắt buộc phải có private key
+func NewKeyRing(activeKid string, keys ...*SigningKey) (*KeyRing, error) {
+	ring := &KeyRing{activeKid: activeKid, keys: make(map[string]*SigningKey, len(keys))}
+
+	for _, key := range keys {
+		if _, exists := ring.keys[key.Kid]; exists {
+			return nil, errors.Errorf("duplicate key id %q", key.Kid)
+		}
+		ring.keys[key.Kid] = key
+	}
+
+	active, ok := ring.keys[activeKid]
+	if !ok {
+		return nil, errors.Errorf("active key %q not found in key ring", activeKid)
+	}
+	if !active.CanSign() {
+		return nil, errors.Errorf("active key %q has no private key", activeKid)
+	}
+
+	return ring, nil
+}
+
+// LoadKeyRingFromDir nạp mọi file *.pem trong thư mục, kid là tên file bỏ phần mở rộng
+func LoadKeyRingFromDir(dir string, activeKid string, extraKeys ...*SigningKey) (*KeyRing, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	keys := make([]*SigningKey, 0, len(files)+len(extraKeys))
+	for _, file := range files {
+		kid := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		key, err := LoadSigningKeyFromPEM(kid, file)
+		if err != nil {
+			return nil, fmt.Errorf("error loading signing key %s: %w", file, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return NewKeyRing(activeKid, append(keys, extraKeys...)...)
+}
+
+// Active trả về khóa đang dùng để ký token
+func (r *KeyRing) Active() *SigningKey {
+	return r.keys[r.activeKid]
+}
+
+// Get tìm khóa theo kid
+func (r *KeyRing) Get(kid string) (*SigningKey, bool) {
+	key, ok := r.keys[kid]
+	return key, ok
+}
+
+// Algorithms trả về danh sách thuật toán được chấp nhận khi verify
+func (r *KeyRing) Algorithms() []string {
+	seen := make(map[string]bool)
+	algs := make([]string, 0, len(r.keys))
+	for _, key := range r.keys {
+		if alg := key.Method.Alg(); !seen[alg] {
+			seen[alg] = true
+			algs = append(algs, alg)
+		}
+	}
+	sort.Strings(algs)
+	return algs
+}
+
+// JWK biểu diễn public key theo RFC 7517
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSet là nội dung của endpoint /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS trả về public key của các khóa bất đối xứng, khóa HMAC không bao giờ được công bố
+func (r *KeyRing) JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0, len(r.keys))}
+
+	for _, key := range r.keys {
+		switch pub := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "RSA",
+				Kid: key.Kid,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "OKP",
+				Kid: key.Kid,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+
+	sort.Slice(set.Keys, func(i, k int) bool { return set.Keys[i].Kid < set.Keys[k].Kid })
+
+	return set
+}
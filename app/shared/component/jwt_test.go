@@ -0,0 +1,141 @@
+package sharecomponent
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+)
+
+func newTestEd25519Key(t *testing.T, kid string) *SigningKey {
+	t.Helper()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	key, err := newSigningKeyFromPrivate(kid, privateKey)
+	if err != nil {
+		t.Fatalf("newSigningKeyFromPrivate() error = %v", err)
+	}
+	return key
+}
+
+func TestJwtCompKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	oldKey := newTestEd25519Key(t, "2026-01")
+	newKey := newTestEd25519Key(t, "2026-10")
+
+	oldRing, err := NewKeyRing(oldKey.Kid, oldKey)
+	if err != nil {
+		t.Fatalf("NewKeyRing() error = %v", err)
+	}
+	oldToken, err := NewJwtCompWithKeyRing(oldRing, 900).IssueToken(ctx, "user-id")
+	if err != nil {
+		t.Fatalf("IssueToken() error = %v", err)
+	}
+
+	// Sau khi rotate, khóa cũ chỉ còn public key nhưng token cũ vẫn verify được
+	retiredKey := &SigningKey{Kid: oldKey.Kid, Method: oldKey.Method, PublicKey: oldKey.PublicKey}
+	rotatedRing, err := NewKeyRing(newKey.Kid, newKey, retiredKey)
+	if err != nil {
+		t.Fatalf("NewKeyRing() error = %v", err)
+	}
+	rotated := NewJwtCompWithKeyRing(rotatedRing, 900)
+
+	if subject, err := rotated.Validate(oldToken); err != nil || subject != "user-id" {
+		t.Errorf("Validate(old token) = (%q, %v), want (user-id, nil)", subject, err)
+	}
+
+	newToken, err := rotated.IssueToken(ctx, "user-id")
+	if err != nil {
+		t.Fatalf("IssueToken() error = %v", err)
+	}
+	if _, err := NewJwtCompWithKeyRing(oldRing, 900).Validate(newToken); err == nil {
+		t.Error("Validate(token signed with unknown kid) error = nil, want error")
+	}
+}
+
+const testSharedSecret = "0123456789abcdef0123456789abcdef"
+
+func TestNewJwtCompRejectsShortSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr bool
+	}{
+		{name: "empty", secret: "", wantErr: true},
+		{name: "too short", secret: "shared-secret", wantErr: true},
+		{name: "minimum length", secret: testSharedSecret, wantErr: false},
+	}
+
+	for _, tt := range tests {
+		_, err := NewJwtComp(tt.secret, 900)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: NewJwtComp() error = %v, want error = %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestJwtCompLegacyKeyCutoff(t *testing.T) {
+	ctx := context.Background()
+	legacyComp, err := NewJwtComp(testSharedSecret, 900)
+	if err != nil {
+		t.Fatalf("NewJwtComp() error = %v", err)
+	}
+	legacyToken, err := legacyComp.IssueToken(ctx, "user-id")
+	if err != nil {
+		t.Fatalf("IssueToken() error = %v", err)
+	}
+
+	activeKey := newTestEd25519Key(t, "2026-10")
+
+	tests := []struct {
+		name     string
+		notAfter time.Time
+		wantOK   bool
+	}{
+		{name: "before cutoff", notAfter: time.Now().Add(time.Hour), wantOK: true},
+		{name: "after cutoff", notAfter: time.Now().Add(-time.Second), wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			legacyKey := NewHMACSigningKey("", []byte(testSharedSecret))
+			legacyKey.NotAfter = tt.notAfter
+			ring, err := NewKeyRing(activeKey.Kid, activeKey, legacyKey)
+			if err != nil {
+				t.Fatalf("NewKeyRing() error = %v", err)
+			}
+
+			_, err = NewJwtCompWithKeyRing(ring, 900).Validate(legacyToken)
+			if ok := err == nil; ok != tt.wantOK {
+				t.Errorf("Validate(legacy token) error = %v, want ok = %v", err, tt.wantOK)
+			}
+		})
+	}
+
+	// Không có khóa HS256 trong ring thì token ký bằng secret dùng chung bị từ chối
+	ring, _ := NewKeyRing(activeKey.Kid, activeKey)
+	if _, err := NewJwtCompWithKeyRing(ring, 900).Validate(legacyToken); err == nil {
+		t.Error("Validate(legacy token) without legacy key error = nil, want error")
+	}
+}
+
+func TestJwtCompRejectsAlgorithmConfusion(t *testing.T) {
+	ctx := context.Background()
+	activeKey := newTestEd25519Key(t, "2026-10")
+	ring, _ := NewKeyRing(activeKey.Kid, activeKey, NewHMACSigningKey("", []byte(testSharedSecret)))
+
+	// Token HS256 gắn kid của khóa Ed25519 phải bị từ chối dù HS256 có trong danh sách thuật toán
+	forgedRing, _ := NewKeyRing(activeKey.Kid, NewHMACSigningKey(activeKey.Kid, []byte(testSharedSecret)))
+	forged, err := NewJwtCompWithKeyRing(forgedRing, 900).IssueToken(ctx, "user-id")
+	if err != nil {
+		t.Fatalf("IssueToken() error = %v", err)
+	}
+
+	if _, err := NewJwtCompWithKeyRing(ring, 900).Validate(forged); err == nil {
+		t.Error("Validate(forged token) error = nil, want error")
+	}
+}
@@ -0,0 +1,67 @@
+package sharecomponent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Mail là một email dạng text gửi tới một người nhận
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// IMailer interface gửi email, module chọn implementation qua cấu hình (smtp, file)
+type IMailer interface {
+	Send(ctx context.Context, mail *Mail) error
+}
+
+// MailerConfig là cấu hình mailer dùng chung cho các module
+type MailerConfig struct {
+	Driver    string `yaml:"driver"`
+	From      string `yaml:"from"`
+	OutboxDir string `yaml:"outbox_dir"`
+	SMTP      struct {
+		Host     string `yaml:"host"`
+		Port     string `yaml:"port"`
+		Username string `yaml:"username"`
+		Password string `yaml:"password"`
+	} `yaml:"smtp"`
+}
+
+// NewMailer khởi tạo mailer theo driver: "smtp" gửi thật, "file" ghi email ra thư mục outbox (dev)
+func NewMailer(config MailerConfig) (IMailer, error) {
+	switch config.Driver {
+	case "smtp":
+		return NewSMTPMailer(config.SMTP.Host, config.SMTP.Port, config.SMTP.Username, config.SMTP.Password, config.From), nil
+	case "file", "":
+		return NewFileMailer(config.OutboxDir, config.From), nil
+	default:
+		return nil, errors.Errorf("unsupported mailer driver %q", config.Driver)
+	}
+}
+
+// buildMessage tạo nội dung email theo RFC 5322 (text/plain, UTF-8)
+func buildMessage(from string, mail *Mail) ([]byte, error) {
+	// Chặn header injection qua địa chỉ hoặc tiêu đề chứa xuống dòng
+	if strings.ContainsAny(mail.To, "\r\n") || strings.ContainsAny(mail.Subject, "\r\n") {
+		return nil, errors.New("mail header contains line break")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mail.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(mail.Body, "\n", "\r\n"))
+
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,41 @@
+package sharecomponent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// FileMailer ghi mỗi email thành một file .eml trong thư mục outbox thay vì gửi thật (dùng cho dev/test)
+type FileMailer struct {
+	outboxDir string
+	from      string
+}
+
+func NewFileMailer(outboxDir string, from string) *FileMailer {
+	return &FileMailer{outboxDir: outboxDir, from: from}
+}
+
+func (m *FileMailer) Send(ctx context.Context, mail *Mail) error {
+	msg, err := buildMessage(m.from, mail)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.outboxDir, 0o755); err != nil {
+		return errors.WithStack(err)
+	}
+
+	id, _ := uuid.NewV7()
+	filename := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102150405"), id.String())
+	if err := os.WriteFile(filepath.Join(m.outboxDir, filename), msg, 0o600); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
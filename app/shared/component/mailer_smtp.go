@@ -0,0 +1,40 @@
+package sharecomponent
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+
+	"github.com/pkg/errors"
+)
+
+// SMTPMailer gửi email qua SMTP server, dùng STARTTLS nếu server hỗ trợ
+type SMTPMailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+func NewSMTPMailer(host string, port string, username string, password string, from string) *SMTPMailer {
+	return &SMTPMailer{host: host, port: port, username: username, password: password, from: from}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, mail *Mail) error {
+	msg, err := buildMessage(m.from, mail)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	if err := smtp.SendMail(net.JoinHostPort(m.host, m.port), auth, m.from, []string{mail.To}, msg); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package sharecomponent
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrPasswordHashMalformed   = errors.New("password hash is malformed")
+	ErrPasswordHashUnsupported = errors.New("password hash algorithm is not supported")
+)
+
+// IPasswordScheme là một thuật toán băm mật khẩu. Hash trả về chuỗi dạng PHC
+// ($<id>$<tham số>$<salt>$<hash>) để thuật toán và tham số đi kèm giá trị băm.
+type IPasswordScheme interface {
+	// Identify kiểm tra chuỗi băm có thuộc thuật toán này không
+	Identify(encoded string) bool
+	Hash(password string) (string, error)
+	// Verify so khớp password, legacySalt chỉ dùng cho giá trị băm cũ lưu salt ở cột riêng
+	Verify(password string, legacySalt string, encoded string) (bool, error)
+	// NeedsRehash kiểm tra chuỗi băm có tham số khác với cấu hình hiện tại không
+	NeedsRehash(encoded string) bool
+}
+
+// PasswordHasher băm mật khẩu bằng thuật toán hiện tại và vẫn verify được các thuật toán cũ
+// để nâng cấp dần giá trị băm khi user đăng nhập
+type PasswordHasher struct {
+	current IPasswordScheme
+	legacy  []IPasswordScheme
+}
+
+// NewPasswordHasher khởi tạo hasher, current dùng để băm mới, legacy chỉ dùng để verify
+func NewPasswordHasher(current IPasswordScheme, legacy ...IPasswordScheme) *PasswordHasher {
+	return &PasswordHasher{current: current, legacy: legacy}
+}
+
+// Hash băm password bằng thuật toán hiện tại
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+// Verify so khớp password với giá trị băm đã lưu. needsRehash là true khi password đúng
+// nhưng giá trị băm dùng thuật toán cũ hoặc tham số đã lỗi thời.
+func (h *PasswordHasher) Verify(password string, legacySalt string, encoded string) (ok bool, needsRehash bool, err error) {
+	if h.current.Identify(encoded) {
+		ok, err = h.current.Verify(password, legacySalt, encoded)
+		return ok, ok && h.current.NeedsRehash(encoded), err
+	}
+
+	for _, scheme := range h.legacy {
+		if scheme.Identify(encoded) {
+			ok, err = scheme.Verify(password, legacySalt, encoded)
+			return ok, ok, err
+		}
+	}
+
+	return false, false, ErrPasswordHashUnsupported
+}
+
+// Argon2Params là tham số của argon2id, Memory tính bằng KiB
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params trả về tham số mặc định (64 MiB, 3 vòng, 2 luồng)
+func DefaultArgon2Params() Argon2Params {
+	return Argon2Params{
+		Memory:      64 * 1024,
+		Iterations:  3,
+		Parallelism: 2,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+// Argon2idScheme băm mật khẩu bằng argon2id, salt nằm trong chuỗi PHC
+type Argon2idScheme struct {
+	params Argon2Params
+}
+
+func NewArgon2idScheme(params Argon2Params) *Argon2idScheme {
+	return &Argon2idScheme{params: params}
+}
+
+func (s *Argon2idScheme) Identify(encoded string) bool {
+	return strings.HasPrefix(encoded, "$argon2id$")
+}
+
+func (s *Argon2idScheme) Hash(password string) (string, error) {
+	salt := make([]byte, s.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, s.params.Iterations, s.params.Memory, s.params.Parallelism, s.params.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, s.params.Memory, s.params.Iterations, s.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (s *Argon2idScheme) Verify(password string, _ string, encoded string) (bool, error) {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	actual := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+
+	return subtle.ConstantTimeCompare(actual, key) == 1, nil
+}
+
+func (s *Argon2idScheme) NeedsRehash(encoded string) bool {
+	params, _, _, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+
+	return params != s.params
+}
+
+// decodeArgon2id tách tham số, salt và hash từ chuỗi $argon2id$v=19$m=..,t=..,p=..$salt$hash
+func decodeArgon2id(encoded string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, ErrPasswordHashMalformed
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrPasswordHashMalformed
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrPasswordHashMalformed
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrPasswordHashMalformed
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrPasswordHashMalformed
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
+
+// LegacyBcryptScheme verify giá trị băm bcrypt cũ của "salt.password" (salt lưu ở cột riêng).
+// Chỉ dùng để verify, bcrypt cắt password ở 72 byte nên không dùng để băm mới.
+type LegacyBcryptScheme struct{}
+
+func NewLegacyBcryptScheme() *LegacyBcryptScheme {
+	return &LegacyBcryptScheme{}
+}
+
+func (s *LegacyBcryptScheme) Identify(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+func (s *LegacyBcryptScheme) Hash(password string) (string, error) {
+	return "", ErrPasswordHashUnsupported
+}
+
+func (s *LegacyBcryptScheme) Verify(password string, legacySalt string, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(legacySalt+"."+password))
+	if err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return false, errors.WithStack(err)
+	}
+
+	return true, nil
+}
+
+func (s *LegacyBcryptScheme) NeedsRehash(encoded string) bool {
+	return true
+}
@@ -0,0 +1,111 @@
+package sharecomponent
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testArgon2Params giữ chi phí thấp để test chạy nhanh
+func testArgon2Params() Argon2Params {
+	return Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+}
+
+func TestPasswordHasherVerify(t *testing.T) {
+	params := testArgon2Params()
+	hasher := NewPasswordHasher(NewArgon2idScheme(params), NewLegacyBcryptScheme())
+
+	current, err := hasher.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	weakerParams := params
+	weakerParams.Iterations = 2
+	outdated, err := NewArgon2idScheme(weakerParams).Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("salt123.correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt.GenerateFromPassword() error = %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		password        string
+		legacySalt      string
+		encoded         string
+		wantOK          bool
+		wantNeedsRehash bool
+		wantErr         error
+	}{
+		{name: "argon2id current params", password: "correct horse", encoded: current, wantOK: true},
+		{name: "argon2id wrong password", password: "wrong", encoded: current},
+		{name: "argon2id outdated params", password: "correct horse", encoded: outdated, wantOK: true, wantNeedsRehash: true},
+		{name: "argon2id outdated params wrong password", password: "wrong", encoded: outdated},
+		{name: "legacy bcrypt", password: "correct horse", legacySalt: "salt123", encoded: string(bcryptHash), wantOK: true, wantNeedsRehash: true},
+		{name: "legacy bcrypt wrong salt", password: "correct horse", legacySalt: "other", encoded: string(bcryptHash)},
+		{name: "legacy bcrypt wrong password", password: "wrong", legacySalt: "salt123", encoded: string(bcryptHash)},
+		{name: "malformed argon2id", password: "correct horse", encoded: "$argon2id$v=19$m=1024$abc", wantErr: ErrPasswordHashMalformed},
+		{name: "unsupported", password: "correct horse", encoded: "plaintext", wantErr: ErrPasswordHashUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, needsRehash, err := hasher.Verify(tt.password, tt.legacySalt, tt.encoded)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if ok != tt.wantOK || needsRehash != tt.wantNeedsRehash {
+				t.Errorf("Verify() = (%v, %v), want (%v, %v)", ok, needsRehash, tt.wantOK, tt.wantNeedsRehash)
+			}
+		})
+	}
+}
+
+func TestArgon2idSchemeNeedsRehash(t *testing.T) {
+	params := testArgon2Params()
+	scheme := NewArgon2idScheme(params)
+
+	encoded, err := scheme.Hash("password")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		params Argon2Params
+		want   bool
+	}{
+		{name: "same params", params: params, want: false},
+		{name: "more memory", params: Argon2Params{Memory: 2048, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}, want: true},
+		{name: "more iterations", params: Argon2Params{Memory: 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32}, want: true},
+		{name: "more parallelism", params: Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 2, SaltLength: 16, KeyLength: 32}, want: true},
+		{name: "longer key", params: Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 64}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewArgon2idScheme(tt.params).NeedsRehash(encoded); got != tt.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if !scheme.NeedsRehash("$argon2id$malformed") {
+		t.Error("NeedsRehash(malformed) = false, want true")
+	}
+}
+
+func TestArgon2idSchemeUniqueSalt(t *testing.T) {
+	scheme := NewArgon2idScheme(testArgon2Params())
+
+	first, _ := scheme.Hash("password")
+	second, _ := scheme.Hash("password")
+	if first == second {
+		t.Error("Hash() returned the same value twice, want a random salt per hash")
+	}
+}
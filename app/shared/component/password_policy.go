@@ -0,0 +1,142 @@
+package sharecomponent
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Các vi phạm chính sách mật khẩu, trả về cho client trong details của lỗi
+const (
+	PasswordViolationTooShort     = "too_short"
+	PasswordViolationTooLong      = "too_long"
+	PasswordViolationNoUppercase  = "missing_uppercase"
+	PasswordViolationNoLowercase  = "missing_lowercase"
+	PasswordViolationNoDigit      = "missing_digit"
+	PasswordViolationNoSymbol     = "missing_symbol"
+	PasswordViolationContainsUser = "contains_user_info"
+	PasswordViolationBreached     = "breached"
+)
+
+// passwordUserInfoMinLength là độ dài tối thiểu của email/tên để bị kiểm tra xuất hiện trong password
+const passwordUserInfoMinLength = 3
+
+// PasswordPolicyConfig là cấu hình chính sách mật khẩu, độ dài tính theo ký tự
+type PasswordPolicyConfig struct {
+	MinLength        int
+	MaxLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	DisallowUserInfo bool
+	// BreachedList bỏ trống để tắt kiểm tra mật khẩu bị lộ
+	BreachedList IBreachedPasswordList
+}
+
+// IBreachedPasswordList kiểm tra password có nằm trong danh sách mật khẩu đã bị lộ không
+type IBreachedPasswordList interface {
+	Contains(password string) (bool, error)
+}
+
+// PasswordViolation mô tả một quy tắc mà password chưa đạt
+type PasswordViolation struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// PasswordPolicy kiểm tra độ mạnh của mật khẩu khi đặt mới
+type PasswordPolicy struct {
+	config PasswordPolicyConfig
+}
+
+func NewPasswordPolicy(config PasswordPolicyConfig) *PasswordPolicy {
+	return &PasswordPolicy{config: config}
+}
+
+// Validate trả về danh sách quy tắc password chưa đạt (rỗng nếu hợp lệ).
+// userInputs là thông tin của user (email, họ, tên...) không được xuất hiện trong password.
+// Lỗi chỉ xảy ra khi không đọc được danh sách mật khẩu bị lộ.
+func (p *PasswordPolicy) Validate(password string, userInputs ...string) ([]PasswordViolation, error) {
+	var violations []PasswordViolation
+
+	length := utf8.RuneCountInString(password)
+	if length < p.config.MinLength {
+		violations = append(violations, PasswordViolation{
+			Code:    PasswordViolationTooShort,
+			Message: fmt.Sprintf("must be at least %d characters", p.config.MinLength),
+		})
+	}
+	if p.config.MaxLength > 0 && length > p.config.MaxLength {
+		violations = append(violations, PasswordViolation{
+			Code:    PasswordViolationTooLong,
+			Message: fmt.Sprintf("must be at most %d characters", p.config.MaxLength),
+		})
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.config.RequireUppercase && !hasUpper {
+		violations = append(violations, PasswordViolation{Code: PasswordViolationNoUppercase, Message: "must contain an uppercase letter"})
+	}
+	if p.config.RequireLowercase && !hasLower {
+		violations = append(violations, PasswordViolation{Code: PasswordViolationNoLowercase, Message: "must contain a lowercase letter"})
+	}
+	if p.config.RequireDigit && !hasDigit {
+		violations = append(violations, PasswordViolation{Code: PasswordViolationNoDigit, Message: "must contain a digit"})
+	}
+	if p.config.RequireSymbol && !hasSymbol {
+		violations = append(violations, PasswordViolation{Code: PasswordViolationNoSymbol, Message: "must contain a symbol"})
+	}
+
+	if p.config.DisallowUserInfo && containsUserInfo(password, userInputs) {
+		violations = append(violations, PasswordViolation{Code: PasswordViolationContainsUser, Message: "must not contain your email or name"})
+	}
+
+	if p.config.BreachedList != nil {
+		breached, err := p.config.BreachedList.Contains(password)
+		if err != nil {
+			return nil, err
+		}
+		if breached {
+			violations = append(violations, PasswordViolation{Code: PasswordViolationBreached, Message: "has appeared in a data breach, choose a different password"})
+		}
+	}
+
+	return violations, nil
+}
+
+// containsUserInfo kiểm tra password có chứa (không phân biệt hoa thường) email, phần trước @ của email
+// hoặc các từ trong tên. Chuỗi quá ngắn được bỏ qua để không chặn nhầm.
+func containsUserInfo(password string, userInputs []string) bool {
+	lowered := strings.ToLower(password)
+
+	for _, input := range userInputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+
+		candidates := strings.Fields(input)
+		if local, _, ok := strings.Cut(input, "@"); ok {
+			candidates = append(candidates, local)
+		}
+
+		for _, candidate := range candidates {
+			if utf8.RuneCountInString(candidate) >= passwordUserInfoMinLength && strings.Contains(lowered, candidate) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,120 @@
+package sharecomponent
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type stubBreachedList struct {
+	passwords map[string]bool
+	err       error
+}
+
+func (l stubBreachedList) Contains(password string) (bool, error) {
+	return l.passwords[password], l.err
+}
+
+func violationCodes(violations []PasswordViolation) []string {
+	codes := make([]string, 0, len(violations))
+	for _, v := range violations {
+		codes = append(codes, v.Code)
+	}
+	return codes
+}
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	policy := NewPasswordPolicy(PasswordPolicyConfig{
+		MinLength:        10,
+		MaxLength:        20,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+		DisallowUserInfo: true,
+		BreachedList:     stubBreachedList{passwords: map[string]bool{"Password123!": true}},
+	})
+
+	tests := []struct {
+		name       string
+		password   string
+		userInputs []string
+		want       []string
+	}{
+		{name: "valid", password: "Tr0ub4dor&3x", userInputs: []string{"john.doe@example.com", "John", "Doe"}, want: []string{}},
+		{name: "unicode length counts runes", password: "Mật khẩu 1!", want: []string{}},
+		{name: "too short", password: "Ab1!", want: []string{PasswordViolationTooShort}},
+		{name: "too long", password: "Abcdefghij1!abcdefghij", want: []string{PasswordViolationTooLong}},
+		{name: "missing classes", password: "abcdefghijkl", want: []string{PasswordViolationNoUppercase, PasswordViolationNoDigit, PasswordViolationNoSymbol}},
+		{name: "missing lowercase", password: "ABCDEFGHIJ1!", want: []string{PasswordViolationNoLowercase}},
+		{name: "contains email local part", password: "xJohn.Doe9!x", userInputs: []string{"john.doe@example.com"}, want: []string{PasswordViolationContainsUser}},
+		{name: "contains name case insensitive", password: "myNGUYEN-pass1", userInputs: []string{"Nguyen Van"}, want: []string{PasswordViolationContainsUser}},
+		{name: "short name ignored", password: "Al-Tr0ub4dor!", userInputs: []string{"Al"}, want: []string{}},
+		{name: "breached", password: "Password123!", want: []string{PasswordViolationBreached}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := policy.Validate(tt.password, tt.userInputs...)
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if got := violationCodes(violations); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicyBreachedListError(t *testing.T) {
+	listErr := errors.New("read failed")
+	policy := NewPasswordPolicy(PasswordPolicyConfig{BreachedList: stubBreachedList{err: listErr}})
+
+	if _, err := policy.Validate("anything"); !errors.Is(err, listErr) {
+		t.Errorf("Validate() error = %v, want %v", err, listErr)
+	}
+}
+
+func TestBreachedPasswordListContains(t *testing.T) {
+	breached := []string{"password", "123456", "qwerty", "letmein", "dragon", "monkey", "abc123"}
+
+	lines := make([]string, 0, len(breached))
+	for i, password := range breached {
+		sum := sha1.Sum([]byte(password))
+		line := strings.ToUpper(hex.EncodeToString(sum[:]))
+		// Số lần xuất hiện có độ dài khác nhau để các dòng không đều nhau
+		if i%2 == 0 {
+			line += ":" + strings.Repeat("9", i+1)
+		}
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	list, err := OpenBreachedPasswordList(path)
+	if err != nil {
+		t.Fatalf("OpenBreachedPasswordList() error = %v", err)
+	}
+	defer list.Close()
+
+	for _, password := range breached {
+		if ok, err := list.Contains(password); err != nil || !ok {
+			t.Errorf("Contains(%q) = (%v, %v), want (true, nil)", password, ok, err)
+		}
+	}
+	for _, password := range []string{"", "correct horse battery staple", "Password"} {
+		if ok, err := list.Contains(password); err != nil || ok {
+			t.Errorf("Contains(%q) = (%v, %v), want (false, nil)", password, ok, err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package sharecomponent
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// SMS là một tin nhắn văn bản gửi tới một số điện thoại định dạng E.164
+type SMS struct {
+	To   string
+	Body string
+}
+
+// ISMSSender interface gửi SMS, module chọn implementation qua cấu hình (console, file).
+// Nhà cung cấp SMS thật được bổ sung bằng một driver mới implement interface này.
+type ISMSSender interface {
+	Send(ctx context.Context, sms *SMS) error
+}
+
+// SMSConfig là cấu hình gửi SMS dùng chung cho các module
+type SMSConfig struct {
+	Driver    string `yaml:"driver"`
+	OutboxDir string `yaml:"outbox_dir"`
+}
+
+// NewSMSSender khởi tạo SMS sender theo driver: "console" in tin nhắn ra log, "file" ghi tin nhắn ra thư mục outbox (dev/test)
+func NewSMSSender(config SMSConfig) (ISMSSender, error) {
+	switch config.Driver {
+	case "console", "":
+		return NewConsoleSMSSender(), nil
+	case "file":
+		return NewFileSMSSender(config.OutboxDir), nil
+	default:
+		return nil, errors.Errorf("unsupported sms driver %q", config.Driver)
+	}
+}
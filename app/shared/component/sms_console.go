@@ -0,0 +1,18 @@
+package sharecomponent
+
+import (
+	"context"
+	"log"
+)
+
+// ConsoleSMSSender in tin nhắn ra log thay vì gửi thật (dùng cho dev)
+type ConsoleSMSSender struct{}
+
+func NewConsoleSMSSender() *ConsoleSMSSender {
+	return &ConsoleSMSSender{}
+}
+
+func (s *ConsoleSMSSender) Send(ctx context.Context, sms *SMS) error {
+	log.Printf("[sms] to=%s body=%q", sms.To, sms.Body)
+	return nil
+}
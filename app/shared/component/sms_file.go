@@ -0,0 +1,36 @@
+package sharecomponent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// FileSMSSender ghi mỗi tin nhắn thành một file .txt trong thư mục outbox thay vì gửi thật (dùng cho dev/test)
+type FileSMSSender struct {
+	outboxDir string
+}
+
+func NewFileSMSSender(outboxDir string) *FileSMSSender {
+	return &FileSMSSender{outboxDir: outboxDir}
+}
+
+func (s *FileSMSSender) Send(ctx context.Context, sms *SMS) error {
+	if err := os.MkdirAll(s.outboxDir, 0o755); err != nil {
+		return errors.WithStack(err)
+	}
+
+	id, _ := uuid.NewV7()
+	filename := fmt.Sprintf("%s_%s.txt", time.Now().Format("20060102150405"), id.String())
+	content := fmt.Sprintf("To: %s\nDate: %s\n\n%s\n", sms.To, time.Now().Format(time.RFC1123Z), sms.Body)
+	if err := os.WriteFile(filepath.Join(s.outboxDir, filename), []byte(content), 0o600); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package sharecomponent
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrSignedTokenInvalid = errors.New("signed token is invalid")
+	ErrSignedTokenExpired = errors.New("signed token has expired")
+)
+
+// TokenSigner ký token ngắn dùng trong link gửi qua email (xác minh email...) bằng HMAC-SHA256.
+// Purpose được đưa vào chữ ký để token của luồng này không dùng được cho luồng khác.
+type TokenSigner struct {
+	secret []byte
+}
+
+func NewTokenSigner(secret []byte) *TokenSigner {
+	return &TokenSigner{secret: secret}
+}
+
+// Sign tạo token chứa subject và thời điểm hết hạn
+func (s *TokenSigner) Sign(purpose string, subject string, expiresAt time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(subject + "|" + strconv.FormatInt(expiresAt.Unix(), 10)))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.mac(purpose, payload))
+}
+
+// Verify kiểm tra chữ ký, thời hạn và trả về subject của token
+func (s *TokenSigner) Verify(purpose string, token string, now time.Time) (string, error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrSignedTokenInvalid
+	}
+
+	expected, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, s.mac(purpose, payload)) {
+		return "", ErrSignedTokenInvalid
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", ErrSignedTokenInvalid
+	}
+
+	sep := strings.LastIndex(string(raw), "|")
+	if sep < 0 {
+		return "", ErrSignedTokenInvalid
+	}
+
+	expiresAt, err := strconv.ParseInt(string(raw[sep+1:]), 10, 64)
+	if err != nil {
+		return "", ErrSignedTokenInvalid
+	}
+	if !now.Before(time.Unix(expiresAt, 0)) {
+		return "", ErrSignedTokenExpired
+	}
+
+	return string(raw[:sep]), nil
+}
+
+func (s *TokenSigner) mac(purpose string, payload string) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(purpose + "." + payload))
+	return h.Sum(nil)
+}
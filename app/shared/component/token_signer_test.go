@@ -0,0 +1,57 @@
+package sharecomponent
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenSignerVerify(t *testing.T) {
+	signer := NewTokenSigner([]byte("test-secret"))
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	token := signer.Sign("email_verification", "user-id|user@example.com", now.Add(time.Hour))
+
+	tests := []struct {
+		name    string
+		signer  *TokenSigner
+		purpose string
+		token   string
+		now     time.Time
+		want    string
+		wantErr error
+	}{
+		{name: "valid", signer: signer, purpose: "email_verification", token: token, now: now, want: "user-id|user@example.com"},
+		{name: "other purpose", signer: signer, purpose: "password_reset", token: token, now: now, wantErr: ErrSignedTokenInvalid},
+		{name: "other secret", signer: NewTokenSigner([]byte("other-secret")), purpose: "email_verification", token: token, now: now, wantErr: ErrSignedTokenInvalid},
+		{name: "expired", signer: signer, purpose: "email_verification", token: token, now: now.Add(time.Hour), wantErr: ErrSignedTokenExpired},
+		{name: "tampered payload", signer: signer, purpose: "email_verification", token: "x" + token, now: now, wantErr: ErrSignedTokenInvalid},
+		{name: "tampered signature", signer: signer, purpose: "email_verification", token: token + "x", now: now, wantErr: ErrSignedTokenInvalid},
+		{name: "no signature", signer: signer, purpose: "email_verification", token: "abc", now: now, wantErr: ErrSignedTokenInvalid},
+		{name: "empty", signer: signer, purpose: "email_verification", token: "", now: now, wantErr: ErrSignedTokenInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.signer.Verify(tt.purpose, tt.token, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Verify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenSignerPayloadWithoutExpiry(t *testing.T) {
+	signer := NewTokenSigner([]byte("test-secret"))
+
+	// Payload được ký đúng nhưng không có phần thời hạn
+	payload := "dXNlci1pZA" // base64url("user-id")
+	token := payload + "." + base64.RawURLEncoding.EncodeToString(signer.mac("email_verification", payload))
+
+	if _, err := signer.Verify("email_verification", token, time.Now()); !errors.Is(err, ErrSignedTokenInvalid) {
+		t.Errorf("Verify() error = %v, want %v", err, ErrSignedTokenInvalid)
+	}
+}
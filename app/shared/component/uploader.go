@@ -0,0 +1,82 @@
+package sharecomponent
+
+import (
+	"context"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IUploader interface lưu trữ file theo key (đường dẫn tương đối dạng "avatars/<id>/a.jpg"),
+// module chọn implementation qua cấu hình (local, s3)
+type IUploader interface {
+	Upload(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// UploaderConfig là cấu hình lưu trữ file dùng chung cho các module.
+// PublicURL là URL gốc để client tải file, URL của file là PublicURL + "/" + key;
+// bỏ trống thì dùng Local.ServePath (local) hoặc URL của bucket (s3).
+type UploaderConfig struct {
+	Driver    string `yaml:"driver"`
+	PublicURL string `yaml:"public_url"`
+	Local     struct {
+		Dir       string `yaml:"dir"`
+		ServePath string `yaml:"serve_path"`
+	} `yaml:"local"`
+	S3 struct {
+		Endpoint     string `yaml:"endpoint"`
+		Region       string `yaml:"region"`
+		Bucket       string `yaml:"bucket"`
+		AccessKey    string `yaml:"access_key"`
+		SecretKey    string `yaml:"secret_key"`
+		UsePathStyle bool   `yaml:"use_path_style"`
+	} `yaml:"s3"`
+}
+
+// NewUploader khởi tạo uploader theo driver: "local" ghi vào thư mục trên máy chủ,
+// "s3" ghi vào bucket của dịch vụ tương thích S3 (AWS S3, MinIO...)
+func NewUploader(config UploaderConfig) (IUploader, error) {
+	switch config.Driver {
+	case "local", "":
+		publicURL := config.PublicURL
+		if publicURL == "" {
+			publicURL = config.Local.ServePath
+		}
+		return NewLocalUploader(config.Local.Dir, publicURL), nil
+	case "s3":
+		return NewS3Uploader(S3UploaderConfig{
+			Endpoint:     config.S3.Endpoint,
+			Region:       config.S3.Region,
+			Bucket:       config.S3.Bucket,
+			AccessKey:    config.S3.AccessKey,
+			SecretKey:    config.S3.SecretKey,
+			UsePathStyle: config.S3.UsePathStyle,
+			PublicURL:    config.PublicURL,
+		})
+	default:
+		return nil, errors.Errorf("unsupported uploader driver %q", config.Driver)
+	}
+}
+
+// cleanFileKey chuẩn hóa key và chặn key thoát ra ngoài thư mục gốc (path traversal)
+func cleanFileKey(key string) (string, error) {
+	if key == "" || strings.ContainsAny(key, "\\\x00") {
+		return "", errors.Errorf("invalid file key %q", key)
+	}
+
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned == "" || cleaned != strings.TrimPrefix(key, "/") {
+		return "", errors.Errorf("invalid file key %q", key)
+	}
+
+	return cleaned, nil
+}
+
+// joinPublicURL ghép URL gốc với key
+func joinPublicURL(publicURL string, key string) string {
+	return strings.TrimRight(publicURL, "/") + "/" + key
+}
@@ -0,0 +1,83 @@
+package sharecomponent
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// LocalUploader lưu file vào thư mục trên máy chủ, file được phục vụ bởi route tĩnh trỏ vào thư mục này
+type LocalUploader struct {
+	dir       string
+	publicURL string
+}
+
+func NewLocalUploader(dir string, publicURL string) *LocalUploader {
+	return &LocalUploader{dir: dir, publicURL: publicURL}
+}
+
+// Upload ghi ra file tạm rồi đổi tên để người đọc không thấy file ghi dở
+func (u *LocalUploader) Upload(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	target, err := u.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return errors.WithStack(err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return errors.WithStack(err)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// Delete xóa file, file không tồn tại không gây lỗi
+func (u *LocalUploader) Delete(ctx context.Context, key string) error {
+	target, err := u.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+func (u *LocalUploader) URL(key string) string {
+	return joinPublicURL(u.publicURL, key)
+}
+
+// path trả về đường dẫn file của key bên trong thư mục lưu trữ
+func (u *LocalUploader) path(key string) (string, error) {
+	cleaned, err := cleanFileKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(u.dir, filepath.FromSlash(cleaned)), nil
+}
@@ -0,0 +1,219 @@
+package sharecomponent
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// s3ErrorBodyLimit là số byte tối đa đọc từ body lỗi của S3 để đưa vào thông báo lỗi
+const s3ErrorBodyLimit = 4 << 10
+
+// S3UploaderConfig là cấu hình kết nối dịch vụ tương thích S3.
+// Endpoint bỏ trống thì dùng AWS S3 theo Region; với MinIO hoặc dịch vụ tự host cần bật UsePathStyle.
+type S3UploaderConfig struct {
+	Endpoint     string
+	Region       string
+	Bucket       string
+	AccessKey    string
+	SecretKey    string
+	UsePathStyle bool
+	// PublicURL bỏ trống thì dùng URL của bucket, bucket phải cho phép đọc công khai
+	PublicURL string
+}
+
+// S3Uploader lưu file vào bucket S3 qua REST API, request được ký bằng AWS Signature Version 4
+type S3Uploader struct {
+	config   S3UploaderConfig
+	endpoint *url.URL
+	client   *http.Client
+}
+
+func NewS3Uploader(config S3UploaderConfig) (*S3Uploader, error) {
+	if config.Bucket == "" || config.AccessKey == "" || config.SecretKey == "" {
+		return nil, errors.New("s3 uploader requires bucket, access key and secret key")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1" // Default: us-east-1
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", config.Region)
+	}
+
+	endpoint, err := url.Parse(strings.TrimRight(config.Endpoint, "/"))
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, errors.Errorf("invalid s3 endpoint %q", config.Endpoint)
+	}
+
+	uploader := &S3Uploader{
+		config:   config,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+	if uploader.config.PublicURL == "" {
+		uploader.config.PublicURL = uploader.bucketURL()
+	}
+
+	return uploader, nil
+}
+
+// Upload đọc toàn bộ body vào bộ nhớ để tính SHA-256 payload khi ký, phù hợp với file nhỏ (ảnh, tài liệu)
+func (u *S3Uploader) Upload(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	buf := bytes.NewBuffer(make([]byte, 0, max(size, 0)))
+	if _, err := io.Copy(buf, body); err != nil {
+		return errors.WithStack(err)
+	}
+	payload := buf.Bytes()
+
+	req, err := u.newRequest(ctx, http.MethodPut, key, payload)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	return u.do(req, payload)
+}
+
+// Delete xóa object, S3 trả về thành công cả khi object không tồn tại
+func (u *S3Uploader) Delete(ctx context.Context, key string) error {
+	req, err := u.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	return u.do(req, nil)
+}
+
+func (u *S3Uploader) URL(key string) string {
+	return joinPublicURL(u.config.PublicURL, key)
+}
+
+// bucketURL trả về URL gốc của bucket theo kiểu path-style (endpoint/bucket) hoặc virtual-hosted (bucket.endpoint)
+func (u *S3Uploader) bucketURL() string {
+	if u.config.UsePathStyle {
+		return fmt.Sprintf("%s://%s%s/%s", u.endpoint.Scheme, u.endpoint.Host, u.endpoint.Path, u.config.Bucket)
+	}
+	return fmt.Sprintf("%s://%s.%s%s", u.endpoint.Scheme, u.config.Bucket, u.endpoint.Host, u.endpoint.Path)
+}
+
+func (u *S3Uploader) newRequest(ctx context.Context, method string, key string, payload []byte) (*http.Request, error) {
+	cleaned, err := cleanFileKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.bucketURL()+"/"+s3EncodePath(cleaned), bytes.NewReader(payload))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return req, nil
+}
+
+// do ký và gửi request, status ngoài 2xx được trả về thành lỗi kèm nội dung phản hồi của S3
+func (u *S3Uploader) do(req *http.Request, payload []byte) error {
+	u.sign(req, payload, time.Now())
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, s3ErrorBodyLimit))
+		return errors.Errorf("s3 %s %s failed with status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// sign thêm header Authorization theo AWS Signature Version 4 (service "s3", không có query string)
+func (u *S3Uploader) sign(req *http.Request, payload []byte, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + u.config.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+u.config.SecretKey), date)
+	signingKey = hmacSHA256(signingKey, u.config.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		u.config.AccessKey, scope, signedHeaders, signature))
+}
+
+// s3EncodePath mã hóa từng đoạn của key theo quy tắc URI encode của SigV4 (giữ nguyên "/" và ký tự unreserved)
+func s3EncodePath(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,302 @@
+package sharecomponent
+
+import (
+	"context"
+	"crypto/hmac"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCleanFileKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{key: "avatars/1/a.jpg", want: "avatars/1/a.jpg"},
+		{key: "/avatars/1/a.jpg", want: "avatars/1/a.jpg"},
+		{key: "a.jpg", want: "a.jpg"},
+		{key: "", wantErr: true},
+		{key: "/", wantErr: true},
+		{key: "../secret", wantErr: true},
+		{key: "avatars/../../secret", wantErr: true},
+		{key: "avatars/../a.jpg", wantErr: true},
+		{key: "avatars/./a.jpg", wantErr: true},
+		{key: "avatars//a.jpg", wantErr: true},
+		{key: "avatars/", wantErr: true},
+		{key: "avatars\\..\\secret", wantErr: true},
+		{key: "avatars/a.jpg\x00.png", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := cleanFileKey(tt.key)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("cleanFileKey(%q) error = %v, want error = %v", tt.key, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("cleanFileKey(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestLocalUploader(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	uploader := NewLocalUploader(filepath.Join(dir, "uploads"), "/static/")
+
+	if err := uploader.Upload(ctx, "avatars/1/a.jpg", strings.NewReader("image"), 5, "image/jpeg"); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "uploads", "avatars", "1", "a.jpg"))
+	if err != nil || string(data) != "image" {
+		t.Fatalf("uploaded file = (%q, %v), want (%q, nil)", data, err, "image")
+	}
+	if got := uploader.URL("avatars/1/a.jpg"); got != "/static/avatars/1/a.jpg" {
+		t.Errorf("URL() = %q, want %q", got, "/static/avatars/1/a.jpg")
+	}
+
+	// Key thoát ra ngoài thư mục lưu trữ bị từ chối, không có file nào được ghi
+	if err := uploader.Upload(ctx, "../escaped.jpg", strings.NewReader("image"), 5, "image/jpeg"); err == nil {
+		t.Error("Upload(../escaped.jpg) error = nil, want error")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escaped.jpg")); !os.IsNotExist(err) {
+		t.Errorf("file outside storage dir exists, stat error = %v", err)
+	}
+
+	if err := uploader.Delete(ctx, "avatars/1/a.jpg"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := uploader.Delete(ctx, "avatars/1/a.jpg"); err != nil {
+		t.Errorf("Delete() of missing file error = %v, want nil", err)
+	}
+}
+
+// s3Request là request mà S3 giả lập nhận được
+type s3Request struct {
+	Method      string
+	Host        string
+	Path        string
+	ContentType string
+	Body        string
+	SignatureOK bool
+}
+
+// newFakeS3Server giả lập S3: ghi lại request, kiểm tra chữ ký SigV4 độc lập với uploader và trả về status cho trước
+func newFakeS3Server(t *testing.T, secretKey string, status int) (*httptest.Server, *[]s3Request) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var requests []s3Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, s3Request{
+			Method:      r.Method,
+			Host:        r.Host,
+			Path:        r.URL.EscapedPath(),
+			ContentType: r.Header.Get("Content-Type"),
+			Body:        string(body),
+			SignatureOK: verifySigV4(r, body, secretKey),
+		})
+		mu.Unlock()
+
+		w.WriteHeader(status)
+		if status >= 300 {
+			_, _ = io.WriteString(w, "<Error><Code>AccessDenied</Code></Error>")
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+// verifySigV4 dựng lại canonical request từ request nhận được qua mạng và so chữ ký theo AWS Signature Version 4
+func verifySigV4(r *http.Request, body []byte, secretKey string) bool {
+	auth := strings.TrimPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ")
+	fields := map[string]string{}
+	for _, field := range strings.Split(auth, ", ") {
+		name, value, _ := strings.Cut(field, "=")
+		fields[name] = value
+	}
+	credential := strings.SplitN(fields["Credential"], "/", 2)
+	if len(credential) != 2 || sha256Hex(body) != r.Header.Get("X-Amz-Content-Sha256") {
+		return false
+	}
+	scope := credential[1]
+	scopeParts := strings.Split(scope, "/")
+	if len(scopeParts) != 4 || scopeParts[2] != "s3" || scopeParts[3] != "aws4_request" {
+		return false
+	}
+
+	signedHeaders := strings.Split(fields["SignedHeaders"], ";")
+	if !sort.StringsAreSorted(signedHeaders) {
+		return false
+	}
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaders {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		r.URL.RawQuery,
+		canonicalHeaders.String(),
+		fields["SignedHeaders"],
+		r.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", r.Header.Get("X-Amz-Date"), scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := []byte("AWS4" + secretKey)
+	for _, part := range scopeParts {
+		key = hmacSHA256(key, part)
+	}
+	want := hmacSHA256(key, stringToSign)
+
+	got := make([]byte, 0, len(want))
+	for i := 0; i+1 < len(fields["Signature"]); i += 2 {
+		var b byte
+		for _, c := range fields["Signature"][i : i+2] {
+			b <<= 4
+			switch {
+			case '0' <= c && c <= '9':
+				b |= byte(c - '0')
+			case 'a' <= c && c <= 'f':
+				b |= byte(c - 'a' + 10)
+			}
+		}
+		got = append(got, b)
+	}
+	return hmac.Equal(got, want)
+}
+
+func TestS3UploaderPathStyle(t *testing.T) {
+	ctx := context.Background()
+	server, requests := newFakeS3Server(t, "secret-key", http.StatusOK)
+
+	uploader, err := NewS3Uploader(S3UploaderConfig{
+		Endpoint:     server.URL,
+		Region:       "ap-southeast-1",
+		Bucket:       "media",
+		AccessKey:    "access-key",
+		SecretKey:    "secret-key",
+		UsePathStyle: true,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Uploader() error = %v", err)
+	}
+
+	if err := uploader.Upload(ctx, "avatars/1/ảnh đại diện.jpg", strings.NewReader("image"), 5, "image/jpeg"); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if err := uploader.Delete(ctx, "avatars/1/ảnh đại diện.jpg"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	// Key không hợp lệ bị từ chối trước khi gửi request
+	if err := uploader.Upload(ctx, "../other-bucket/a.jpg", strings.NewReader("image"), 5, "image/jpeg"); err == nil {
+		t.Error("Upload(../other-bucket/a.jpg) error = nil, want error")
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("S3 received %d requests, want 2", len(*requests))
+	}
+	wantPath := "/media/avatars/1/%E1%BA%A3nh%20%C4%91%E1%BA%A1i%20di%E1%BB%87n.jpg"
+	put, del := (*requests)[0], (*requests)[1]
+	if put.Method != http.MethodPut || put.Path != wantPath || put.Body != "image" || put.ContentType != "image/jpeg" || !put.SignatureOK {
+		t.Errorf("PUT request = %+v, want PUT %s with signed body", put, wantPath)
+	}
+	if del.Method != http.MethodDelete || del.Path != wantPath || del.Body != "" || !del.SignatureOK {
+		t.Errorf("DELETE request = %+v, want DELETE %s with valid signature", del, wantPath)
+	}
+
+	if got, want := uploader.URL("avatars/1/a.jpg"), server.URL+"/media/avatars/1/a.jpg"; got != want {
+		t.Errorf("URL() = %q, want %q", got, want)
+	}
+}
+
+func TestS3UploaderVirtualHostedStyle(t *testing.T) {
+	server, requests := newFakeS3Server(t, "secret-key", http.StatusOK)
+
+	uploader, err := NewS3Uploader(S3UploaderConfig{
+		Endpoint:  "http://s3.test",
+		Bucket:    "media",
+		AccessKey: "access-key",
+		SecretKey: "secret-key",
+		PublicURL: "https://cdn.example.com/",
+	})
+	if err != nil {
+		t.Fatalf("NewS3Uploader() error = %v", err)
+	}
+	// Mọi kết nối đi tới S3 giả lập, Host của request vẫn là bucket.endpoint
+	uploader.client = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network string, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+		},
+	}}
+
+	if err := uploader.Upload(context.Background(), "avatars/1/a.jpg", strings.NewReader("image"), 5, ""); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	if len(*requests) != 1 {
+		t.Fatalf("S3 received %d requests, want 1", len(*requests))
+	}
+	put := (*requests)[0]
+	if put.Host != "media.s3.test" || put.Path != "/avatars/1/a.jpg" || !put.SignatureOK {
+		t.Errorf("PUT request = %+v, want host media.s3.test, path /avatars/1/a.jpg and valid signature", put)
+	}
+	if got, want := uploader.URL("avatars/1/a.jpg"), "https://cdn.example.com/avatars/1/a.jpg"; got != want {
+		t.Errorf("URL() = %q, want %q", got, want)
+	}
+}
+
+func TestS3UploaderErrorStatus(t *testing.T) {
+	server, _ := newFakeS3Server(t, "secret-key", http.StatusForbidden)
+
+	uploader, err := NewS3Uploader(S3UploaderConfig{
+		Endpoint:     server.URL,
+		Bucket:       "media",
+		AccessKey:    "access-key",
+		SecretKey:    "secret-key",
+		UsePathStyle: true,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Uploader() error = %v", err)
+	}
+
+	err = uploader.Upload(context.Background(), "avatars/1/a.jpg", strings.NewReader("image"), 5, "image/jpeg")
+	if err == nil || !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("Upload() error = %v, want error with status 403 and S3 error body", err)
+	}
+}
+
+func TestNewS3UploaderRequiresCredentials(t *testing.T) {
+	tests := []struct {
+		name   string
+		config S3UploaderConfig
+	}{
+		{name: "missing bucket", config: S3UploaderConfig{AccessKey: "a", SecretKey: "s"}},
+		{name: "missing access key", config: S3UploaderConfig{Bucket: "b", SecretKey: "s"}},
+		{name: "missing secret key", config: S3UploaderConfig{Bucket: "b", AccessKey: "a"}},
+		{name: "invalid endpoint", config: S3UploaderConfig{Bucket: "b", AccessKey: "a", SecretKey: "s", Endpoint: "s3.test"}},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewS3Uploader(tt.config); err == nil {
+			t.Errorf("%s: NewS3Uploader() error = nil, want error", tt.name)
+		}
+	}
+}
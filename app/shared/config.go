@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -83,3 +84,27 @@ func GetModuleConfig(modulePath string, config interface{}) error {
 
 	return nil
 }
+
+// IntOrDefault trả về value nếu dương, ngược lại dùng fallback (giá trị thiếu trong config.yaml là 0)
+func IntOrDefault[T ~int | ~int64 | ~uint8 | ~uint32](value T, fallback T) T {
+	if value > 0 {
+		return value
+	}
+	return fallback
+}
+
+// SecondsOrDefault đổi số giây trong config thành time.Duration, giá trị không dương dùng fallback
+func SecondsOrDefault(seconds int, fallback time.Duration) time.Duration {
+	if seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return fallback
+}
+
+// DurationOrDefault parse chuỗi duration trong config (ví dụ "15m"), giá trị thiếu, không hợp lệ hoặc không dương dùng fallback
+func DurationOrDefault(raw string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+		return d
+	}
+	return fallback
+}
@@ -0,0 +1,62 @@
+package shared
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIntOrDefault(t *testing.T) {
+	tests := []struct {
+		name  string
+		value int
+		want  int
+	}{
+		{name: "configured", value: 7, want: 7},
+		{name: "missing", value: 0, want: 5},
+		{name: "negative", value: -1, want: 5},
+	}
+
+	for _, tt := range tests {
+		if got := IntOrDefault(tt.value, 5); got != tt.want {
+			t.Errorf("%s: IntOrDefault(%d, 5) = %d, want %d", tt.name, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSecondsOrDefault(t *testing.T) {
+	tests := []struct {
+		name    string
+		seconds int
+		want    time.Duration
+	}{
+		{name: "configured", seconds: 90, want: 90 * time.Second},
+		{name: "missing", seconds: 0, want: time.Hour},
+		{name: "negative", seconds: -30, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := SecondsOrDefault(tt.seconds, time.Hour); got != tt.want {
+			t.Errorf("%s: SecondsOrDefault(%d) = %v, want %v", tt.name, tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func TestDurationOrDefault(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want time.Duration
+	}{
+		{name: "configured", raw: "15m", want: 15 * time.Minute},
+		{name: "missing", raw: "", want: time.Minute},
+		{name: "invalid", raw: "soon", want: time.Minute},
+		{name: "zero", raw: "0s", want: time.Minute},
+		{name: "negative", raw: "-5s", want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := DurationOrDefault(tt.raw, time.Minute); got != tt.want {
+			t.Errorf("%s: DurationOrDefault(%q) = %v, want %v", tt.name, tt.raw, got, tt.want)
+		}
+	}
+}
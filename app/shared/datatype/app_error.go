@@ -180,7 +180,7 @@ func (e DefaultError) WithDetail(key string, detail interface{}) *DefaultError {
 	if e.DetailsField == nil {
 		e.DetailsField = map[string]interface{}{}
 	}
-
+	e.DetailsField[key] = detail
 	return &e
 }
 
@@ -351,11 +351,23 @@ var ErrUnsupportedMediaType = DefaultError{
 	CodeField:   http.StatusUnsupportedMediaType,
 }
 
+var ErrRequestEntityTooLarge = DefaultError{
+	StatusField: http.StatusText(http.StatusRequestEntityTooLarge),
+	ErrorField:  "The request body is too large",
+	CodeField:   http.StatusRequestEntityTooLarge,
+}
+
 var ErrConflict = DefaultError{
 	StatusField: http.StatusText(http.StatusConflict),
 	ErrorField:  "The resource could not be created due to a conflict",
 	CodeField:   http.StatusConflict,
 }
 
+var ErrTooManyRequests = DefaultError{
+	StatusField: http.StatusText(http.StatusTooManyRequests),
+	ErrorField:  "Too many requests, please try again later",
+	CodeField:   http.StatusTooManyRequests,
+}
+
 // ErrRecordNotFound is used to make our application logic independent of other libraries errors
 var ErrRecordNotFound = errors.New("record not found")
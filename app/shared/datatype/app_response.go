@@ -11,3 +11,37 @@ func ResponseSuccess(data any) *AppResponse {
 		Data: data,
 	}
 }
+
+// ResponseWithPaging trả về danh sách kèm thông tin phân trang và filter đã áp dụng
+func ResponseWithPaging(data any, paging *Paging, filter any) *AppResponse {
+	return &AppResponse{
+		Data:   data,
+		Paging: paging,
+		Filter: filter,
+	}
+}
+
+// Paging là tham số phân trang nhận từ query string (page, limit) và tổng số bản ghi trả về cho client
+type Paging struct {
+	Page  int   `json:"page" form:"page"`
+	Limit int   `json:"limit" form:"limit"`
+	Total int64 `json:"total" form:"-"`
+}
+
+// Process chuẩn hóa page và limit về khoảng hợp lệ
+func (p *Paging) Process() {
+	if p.Page <= 0 {
+		p.Page = 1
+	}
+	if p.Limit <= 0 {
+		p.Limit = 10
+	}
+	if p.Limit > 100 {
+		p.Limit = 100
+	}
+}
+
+// Offset trả về số bản ghi bỏ qua của trang hiện tại
+func (p *Paging) Offset() int {
+	return (p.Page - 1) * p.Limit
+}
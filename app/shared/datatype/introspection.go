@@ -0,0 +1,104 @@
+package datatype
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Loại token trong yêu cầu/kết quả introspection (token_type_hint, token_type)
+const (
+	TokenTypeAccessToken = "access_token"
+	TokenTypeAPIKey      = "api_key"
+)
+
+// IntrospectionActor là người thực sự thao tác khi token là token impersonation (claim "act")
+type IntrospectionActor struct {
+	Subject string `json:"sub"`
+}
+
+// IntrospectionResult là kết quả introspection token theo RFC 7662, dùng chung giữa module User (server)
+// và các service gọi endpoint introspect (client). Token không hợp lệ chỉ có Active = false.
+// Scopes rỗng với access token (không giới hạn scope), là danh sách scope được cấp với API key.
+type IntrospectionResult struct {
+	Active           bool                `json:"active"`
+	TokenType        string              `json:"token_type,omitempty"`
+	Subject          string              `json:"sub,omitempty"`
+	Role             string              `json:"role,omitempty"`
+	Status           string              `json:"status,omitempty"`
+	FirstName        string              `json:"first_name,omitempty"`
+	LastName         string              `json:"last_name,omitempty"`
+	ExpiresAt        int64               `json:"exp,omitempty"`
+	TokenID          string              `json:"jti,omitempty"`
+	SessionID        string              `json:"sid,omitempty"`
+	Scopes           []string            `json:"scopes,omitempty"`
+	OrganizationID   string              `json:"org,omitempty"`
+	OrganizationRole string              `json:"org_role,omitempty"`
+	Actor            *IntrospectionActor `json:"act,omitempty"`
+}
+
+// NewIntrospectionResult tạo kết quả introspection của token hợp lệ từ Requester, expiresAt rỗng nếu token không hết hạn
+func NewIntrospectionResult(requester Requester, tokenType string, expiresAt *time.Time) *IntrospectionResult {
+	result := &IntrospectionResult{
+		Active:           true,
+		TokenType:        tokenType,
+		Subject:          requester.UserID().String(),
+		Role:             requester.Role(),
+		Status:           requester.Status(),
+		FirstName:        requester.FirstName(),
+		LastName:         requester.LastName(),
+		TokenID:          requester.TokenID(),
+		SessionID:        requester.SessionID(),
+		Scopes:           requester.Scopes(),
+		OrganizationRole: requester.OrganizationRole(),
+	}
+	if expiresAt != nil {
+		result.ExpiresAt = expiresAt.Unix()
+	}
+	if requester.OrganizationID() != uuid.Nil {
+		result.OrganizationID = requester.OrganizationID().String()
+	}
+	if IsImpersonated(requester) {
+		result.Actor = &IntrospectionActor{Subject: requester.ImpersonatorID().String()}
+	}
+
+	return result
+}
+
+// Requester dựng lại Requester từ kết quả introspection của token hợp lệ
+func (r *IntrospectionResult) Requester() (Requester, error) {
+	if !r.Active {
+		return nil, errors.New("token is not active")
+	}
+
+	userID, err := uuid.Parse(r.Subject)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid introspection subject")
+	}
+
+	var requester Requester
+	if r.TokenType == TokenTypeAPIKey {
+		requester = NewScopedRequester(userID, r.TokenID, r.FirstName, r.LastName, r.Role, r.Status, r.Scopes)
+	} else {
+		requester = NewRequester(userID, r.TokenID, r.SessionID, r.FirstName, r.LastName, r.Role, r.Status)
+	}
+
+	if r.OrganizationID != "" {
+		organizationID, err := uuid.Parse(r.OrganizationID)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid introspection organization")
+		}
+		requester = WithOrganization(requester, organizationID, r.OrganizationRole)
+	}
+
+	if r.Actor != nil {
+		impersonatorID, err := uuid.Parse(r.Actor.Subject)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid introspection actor")
+		}
+		requester = WithImpersonator(requester, impersonatorID)
+	}
+
+	return requester, nil
+}
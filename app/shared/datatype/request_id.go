@@ -0,0 +1,21 @@
+package datatype
+
+import "context"
+
+// KeyRequestID là key lưu request ID của request hiện tại trong gin.Context
+const KeyRequestID = "request_id"
+
+type requestIDCtxKey struct{}
+
+// ContextWithRequestID gắn request ID vào context để tầng service ghi kèm vào log và audit
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey{}, requestID)
+}
+
+// GetRequestID lấy request ID từ context, trả về chuỗi rỗng nếu request chưa được gắn ID
+func GetRequestID(ctx context.Context) string {
+	if requestID, ok := ctx.Value(requestIDCtxKey{}).(string); ok {
+		return requestID
+	}
+	return ""
+}
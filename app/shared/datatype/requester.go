@@ -0,0 +1,230 @@
+package datatype
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// KeyRequester là key lưu thông tin người gọi (principal) trong gin.Context
+const KeyRequester = "requester"
+
+// Các role dùng chung giữa các module, khớp với user_role_enum của module User
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Các role của thành viên trong tổ chức, khớp với cột role của bảng user_organization_members
+const (
+	OrgRoleOwner  = "owner"
+	OrgRoleAdmin  = "admin"
+	OrgRoleMember = "member"
+)
+
+// ScopeAccountManage là scope cho các thao tác quản lý thông tin đăng nhập (đổi mật khẩu, 2FA, API key...).
+// Scope này không bao giờ được cấp cho API key nên chỉ phiên đăng nhập bằng JWT mới có.
+const ScopeAccountManage = "account:manage"
+
+// Requester đại diện cho người dùng đã được xác thực của request hiện tại
+type Requester interface {
+	UserID() uuid.UUID
+	TokenID() string
+	// SessionID trả về ID phiên đăng nhập của access token, rỗng với API key
+	SessionID() string
+	FirstName() string
+	LastName() string
+	Role() string
+	Status() string
+	// Scopes trả về nil với phiên đăng nhập (không giới hạn), danh sách scope được cấp với API key
+	Scopes() []string
+	// OrganizationID trả về tổ chức đang hoạt động của phiên (claim "org"), uuid.Nil nếu chưa chọn tổ chức
+	OrganizationID() uuid.UUID
+	// OrganizationRole trả về role của requester trong tổ chức đang hoạt động, rỗng nếu chưa chọn tổ chức
+	OrganizationRole() string
+	// ImpersonatorID trả về admin đang đăng nhập dưới danh nghĩa user (claim "act"), uuid.Nil nếu không impersonate
+	ImpersonatorID() uuid.UUID
+}
+
+// IsAdmin kiểm tra requester có role admin không
+func IsAdmin(requester Requester) bool {
+	return requester != nil && requester.Role() == RoleAdmin
+}
+
+// HasScopes kiểm tra requester có đủ các scope yêu cầu không, phiên đăng nhập không bị giới hạn scope
+func HasScopes(requester Requester, scopes ...string) bool {
+	if requester == nil {
+		return false
+	}
+
+	granted := requester.Scopes()
+	if granted == nil {
+		return true
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(granted, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsImpersonated kiểm tra requester là phiên impersonation của admin
+func IsImpersonated(requester Requester) bool {
+	return requester != nil && requester.ImpersonatorID() != uuid.Nil
+}
+
+// RealActorID trả về người thực sự thao tác để ghi created_by/updated_by và audit:
+// admin khi đang impersonate, ngược lại là chính user
+func RealActorID(requester Requester) uuid.UUID {
+	if IsImpersonated(requester) {
+		return requester.ImpersonatorID()
+	}
+	return requester.UserID()
+}
+
+// HasOrganizationRole kiểm tra requester đang hoạt động trong một tổ chức với một trong các role chỉ định,
+// không chỉ định role thì mọi thành viên đều thỏa
+func HasOrganizationRole(requester Requester, roles ...string) bool {
+	if requester == nil || requester.OrganizationID() == uuid.Nil {
+		return false
+	}
+
+	return len(roles) == 0 || slices.Contains(roles, requester.OrganizationRole())
+}
+
+type requesterData struct {
+	userID    uuid.UUID
+	tokenID   string
+	sessionID string
+	firstName string
+	lastName  string
+	role      string
+	status    string
+	scopes    []string
+
+	organizationID   uuid.UUID
+	organizationRole string
+	impersonatorID   uuid.UUID
+}
+
+// NewRequester tạo Requester mới từ thông tin user, ID (jti) và phiên đăng nhập (sid) của access token
+func NewRequester(userID uuid.UUID, tokenID, sessionID, firstName, lastName, role, status string) Requester {
+	return &requesterData{
+		userID:    userID,
+		tokenID:   tokenID,
+		sessionID: sessionID,
+		firstName: firstName,
+		lastName:  lastName,
+		role:      role,
+		status:    status,
+	}
+}
+
+// NewScopedRequester tạo Requester bị giới hạn trong các scope được cấp (xác thực bằng API key)
+func NewScopedRequester(userID uuid.UUID, keyID, firstName, lastName, role, status string, scopes []string) Requester {
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	return &requesterData{
+		userID:    userID,
+		tokenID:   keyID,
+		firstName: firstName,
+		lastName:  lastName,
+		role:      role,
+		status:    status,
+		scopes:    scopes,
+	}
+}
+
+// WithOrganization trả về bản sao của requester gắn với tổ chức đang hoạt động và role trong tổ chức đó
+func WithOrganization(requester Requester, organizationID uuid.UUID, organizationRole string) Requester {
+	r := copyRequester(requester)
+	r.organizationID = organizationID
+	r.organizationRole = organizationRole
+	return r
+}
+
+// WithImpersonator trả về bản sao của requester đánh dấu admin đang impersonate user
+func WithImpersonator(requester Requester, impersonatorID uuid.UUID) Requester {
+	r := copyRequester(requester)
+	r.impersonatorID = impersonatorID
+	return r
+}
+
+func copyRequester(requester Requester) *requesterData {
+	return &requesterData{
+		userID:           requester.UserID(),
+		tokenID:          requester.TokenID(),
+		sessionID:        requester.SessionID(),
+		firstName:        requester.FirstName(),
+		lastName:         requester.LastName(),
+		role:             requester.Role(),
+		status:           requester.Status(),
+		scopes:           requester.Scopes(),
+		organizationID:   requester.OrganizationID(),
+		organizationRole: requester.OrganizationRole(),
+		impersonatorID:   requester.ImpersonatorID(),
+	}
+}
+
+func (r *requesterData) UserID() uuid.UUID {
+	return r.userID
+}
+
+func (r *requesterData) TokenID() string {
+	return r.tokenID
+}
+
+func (r *requesterData) SessionID() string {
+	return r.sessionID
+}
+
+func (r *requesterData) FirstName() string {
+	return r.firstName
+}
+
+func (r *requesterData) LastName() string {
+	return r.lastName
+}
+
+func (r *requesterData) Role() string {
+	return r.role
+}
+
+func (r *requesterData) Status() string {
+	return r.status
+}
+
+func (r *requesterData) Scopes() []string {
+	return r.scopes
+}
+
+func (r *requesterData) OrganizationID() uuid.UUID {
+	return r.organizationID
+}
+
+func (r *requesterData) OrganizationRole() string {
+	return r.organizationRole
+}
+
+func (r *requesterData) ImpersonatorID() uuid.UUID {
+	return r.impersonatorID
+}
+
+type requesterCtxKey struct{}
+
+// ContextWithRequester gắn Requester vào context để truyền xuống tầng service
+func ContextWithRequester(ctx context.Context, requester Requester) context.Context {
+	return context.WithValue(ctx, requesterCtxKey{}, requester)
+}
+
+// GetRequester lấy Requester từ context, trả về nil nếu request chưa được xác thực
+func GetRequester(ctx context.Context) Requester {
+	if requester, ok := ctx.Value(requesterCtxKey{}).(Requester); ok {
+		return requester
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package sharedinfras
 
 import (
+	sharecomponent "fat2fast/ikv/shared/component"
+
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -11,34 +13,35 @@ type IDbContext interface {
 type IMiddlewareProvider interface {
 	Auth() gin.HandlerFunc
 	CheckRoles(roles ...string) gin.HandlerFunc
+	RequireScopes(scopes ...string) gin.HandlerFunc
+	RequireOrganization(roles ...string) gin.HandlerFunc
+	DenyImpersonation() gin.HandlerFunc
 }
 
 type IAppContext interface {
 	DbContext() IDbContext
 	MiddlewareProvider() IMiddlewareProvider
 	// GetConfig() *datatype.Config
-	// Uploader() IUploader
+	// Uploader trả về nil nếu module không cấu hình lưu trữ file
+	Uploader() sharecomponent.IUploader
 	// MsgBroker() IMsgBroker
 }
 
 type appContext struct {
 	dbContext   IDbContext
 	mldProvider IMiddlewareProvider
+	uploader    sharecomponent.IUploader
 	// config      *datatype.Config
-	// uploader    IUploader
 	// msgBroker   IMsgBroker
 }
 
-func NewAppContext(db *gorm.DB) IAppContext {
+func NewAppContext(db *gorm.DB, mldProvider IMiddlewareProvider, uploader sharecomponent.IUploader) IAppContext {
 	dbCtx := NewDbContext(db)
 
-	// introspectRpcClient := sharedrpc.NewIntrospectRpcClient(config.UserServiceURL)
-
-	// provider := middleware.NewMiddlewareProvider(introspectRpcClient)
-
 	return &appContext{
-		dbContext: dbCtx,
-		// mldProvider: provider,
+		dbContext:   dbCtx,
+		mldProvider: mldProvider,
+		uploader:    uploader,
 	}
 }
 
@@ -49,3 +52,7 @@ func (c *appContext) MiddlewareProvider() IMiddlewareProvider {
 func (c *appContext) DbContext() IDbContext {
 	return c.dbContext
 }
+
+func (c *appContext) Uploader() sharecomponent.IUploader {
+	return c.uploader
+}
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ITokenIntrospector xác thực access token và trả về thông tin người gọi
+type ITokenIntrospector interface {
+	IntrospectToken(ctx context.Context, accessToken string) (datatype.Requester, error)
+}
+
+// IAPIKeyIntrospector xác thực API key và trả về thông tin người gọi kèm scope được cấp
+type IAPIKeyIntrospector interface {
+	IntrospectAPIKey(ctx context.Context, apiKey string) (datatype.Requester, error)
+}
+
+// HeaderAPIKey là header chứa API key, dùng thay cho "Authorization: Bearer <token>"
+const HeaderAPIKey = "X-API-Key"
+
+// MiddlewareProvider cung cấp các middleware xác thực/phân quyền cho các module
+type MiddlewareProvider struct {
+	tokenIntrospector  ITokenIntrospector
+	apiKeyIntrospector IAPIKeyIntrospector
+}
+
+// NewMiddlewareProvider khởi tạo MiddlewareProvider mới
+func NewMiddlewareProvider(tokenIntrospector ITokenIntrospector) *MiddlewareProvider {
+	return &MiddlewareProvider{tokenIntrospector: tokenIntrospector}
+}
+
+// SetAPIKeyIntrospector bật xác thực bằng header X-API-Key
+func (p *MiddlewareProvider) SetAPIKeyIntrospector(apiKeyIntrospector IAPIKeyIntrospector) {
+	p.apiKeyIntrospector = apiKeyIntrospector
+}
+
+// Auth yêu cầu request phải có header "Authorization: Bearer <token>" hoặc "X-API-Key: <key>" hợp lệ
+func (p *MiddlewareProvider) Auth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requester, err := p.authenticate(c)
+		if err != nil {
+			panic(err)
+		}
+
+		c.Set(datatype.KeyRequester, requester)
+		c.Request = c.Request.WithContext(datatype.ContextWithRequester(c.Request.Context(), requester))
+
+		c.Next()
+	}
+}
+
+// CheckRoles chỉ cho phép requester có một trong các role được chỉ định, phải dùng sau Auth()
+func (p *MiddlewareProvider) CheckRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get(datatype.KeyRequester)
+		requester, ok := value.(datatype.Requester)
+		if !exists || !ok {
+			panic(datatype.ErrUnauthorized)
+		}
+
+		if !slices.Contains(roles, requester.Role()) {
+			panic(datatype.ErrForbidden.WithReasonf("requires one of roles: %s", strings.Join(roles, ", ")))
+		}
+
+		c.Next()
+	}
+}
+
+// RequireScopes chỉ cho phép requester có đủ các scope được chỉ định, phải dùng sau Auth().
+// Phiên đăng nhập bằng JWT không bị giới hạn scope, chỉ API key bị kiểm tra.
+func (p *MiddlewareProvider) RequireScopes(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get(datatype.KeyRequester)
+		requester, ok := value.(datatype.Requester)
+		if !exists || !ok {
+			panic(datatype.ErrUnauthorized)
+		}
+
+		if !datatype.HasScopes(requester, scopes...) {
+			panic(datatype.ErrForbidden.WithReasonf("requires scopes: %s", strings.Join(scopes, ", ")))
+		}
+
+		c.Next()
+	}
+}
+
+// RequireOrganization chỉ cho phép requester đang hoạt động trong một tổ chức (claim "org" của access token)
+// với một trong các role chỉ định, không chỉ định role thì mọi thành viên đều được qua; phải dùng sau Auth().
+// API key không gắn với tổ chức nên luôn bị từ chối.
+func (p *MiddlewareProvider) RequireOrganization(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get(datatype.KeyRequester)
+		requester, ok := value.(datatype.Requester)
+		if !exists || !ok {
+			panic(datatype.ErrUnauthorized)
+		}
+
+		if requester.OrganizationID() == uuid.Nil {
+			panic(datatype.ErrForbidden.WithReason("requires an active organization"))
+		}
+		if !datatype.HasOrganizationRole(requester, roles...) {
+			panic(datatype.ErrForbidden.WithReasonf("requires one of organization roles: %s", strings.Join(roles, ", ")))
+		}
+
+		c.Next()
+	}
+}
+
+// DenyImpersonation từ chối token impersonation của admin ở các thao tác nhạy cảm
+// (đổi mật khẩu/email, 2FA, API key...) mà chỉ chính chủ tài khoản được thực hiện; phải dùng sau Auth().
+func (p *MiddlewareProvider) DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get(datatype.KeyRequester)
+		requester, ok := value.(datatype.Requester)
+		if !exists || !ok {
+			panic(datatype.ErrUnauthorized)
+		}
+
+		if datatype.IsImpersonated(requester) {
+			panic(datatype.ErrForbidden.WithReason("not allowed while impersonating"))
+		}
+
+		c.Next()
+	}
+}
+
+func (p *MiddlewareProvider) authenticate(c *gin.Context) (datatype.Requester, error) {
+	if apiKey := c.GetHeader(HeaderAPIKey); apiKey != "" {
+		if p.apiKeyIntrospector == nil {
+			return nil, datatype.ErrUnauthorized.WithError("API key authentication is not enabled")
+		}
+		return p.apiKeyIntrospector.IntrospectAPIKey(c.Request.Context(), apiKey)
+	}
+
+	token, err := extractTokenFromHeaderString(c.GetHeader("Authorization"))
+	if err != nil {
+		return nil, err
+	}
+
+	return p.tokenIntrospector.IntrospectToken(c.Request.Context(), token)
+}
+
+// extractTokenFromHeaderString tách token từ header Authorization
+func extractTokenFromHeaderString(s string) (string, error) {
+	parts := strings.Split(s, " ")
+
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") || strings.TrimSpace(parts[1]) == "" {
+		return "", datatype.ErrUnauthorized.WithError("Missing or invalid bearer token")
+	}
+
+	return parts[1], nil
+}
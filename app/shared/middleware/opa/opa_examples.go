@@ -141,9 +141,13 @@ func CustomOrgInputCreator(c *gin.Context) (map[string]interface{}, error) {
 		"path":   c.Request.URL.Path,
 	}
 
-	// Thêm organization ID từ header
-	if orgID := c.GetHeader("X-Org-ID"); orgID != "" {
-		input["organization_id"] = orgID
+	// Thêm tổ chức đang hoạt động từ requester (claim org/org_role trong access token),
+	// không đọc từ header để client không tự chọn tổ chức mà mình không phải thành viên
+	if requester, ok := c.Get(datatype.KeyRequester); ok {
+		if r := requester.(datatype.Requester); r.OrganizationID() != uuid.Nil {
+			input["organization_id"] = r.OrganizationID().String()
+			input["organization_role"] = r.OrganizationRole()
+		}
 	}
 
 	// Thêm user context
@@ -19,6 +19,9 @@ func RecoverMiddleware() gin.HandlerFunc {
 			isProduction := os.Getenv("ENV") == "prod" || os.Getenv("GIN_MODE") == "release"
 
 			if r := recover(); r != nil {
+				// Dừng chuỗi handler, tránh handler phía sau vẫn chạy khi middleware (Auth, CheckRoles...) đã từ chối request
+				c.Abort()
+
 				if appError, ok := r.(CanGetStatusCode); ok {
 					c.JSON(appError.StatusCode(), appError)
 
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"fat2fast/ikv/shared/datatype"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// HeaderRequestID là header mang request ID giữa client, proxy và service
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLength là độ dài tối đa của request ID nhận từ client, khớp với cột request_id của bảng audit
+const maxRequestIDLength = 64
+
+// RequestID gắn request ID cho mỗi request: dùng lại X-Request-ID do proxy/client gửi lên nếu hợp lệ,
+// nếu không thì tạo mới. ID được trả về trong header phản hồi và lưu vào context của request.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(HeaderRequestID)
+		if !isValidRequestID(requestID) {
+			id, _ := uuid.NewV7()
+			requestID = id.String()
+		}
+
+		c.Set(datatype.KeyRequestID, requestID)
+		c.Request = c.Request.WithContext(datatype.ContextWithRequestID(c.Request.Context(), requestID))
+		c.Header(HeaderRequestID, requestID)
+
+		c.Next()
+	}
+}
+
+// isValidRequestID chỉ chấp nhận chữ, số và "-", "_", ".", ":" để giá trị từ client không chèn được nội dung lạ vào log
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(requestID); i++ {
+		c := requestID[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -1,7 +1,11 @@
 package shared
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // Module là interface mà tất cả các module phải implement
@@ -14,6 +18,13 @@ type Module interface {
 
 	// IsEnabled kiểm tra module có được kích hoạt không
 	IsEnabled() bool
+
+	// ExportUserData trả về dữ liệu module đang lưu về user để đưa vào bản trích xuất dữ liệu cá nhân,
+	// trả về nil nếu module không lưu gì về user
+	ExportUserData(ctx context.Context, userID uuid.UUID) (any, error)
+
+	// EraseUserData xóa hoặc ẩn danh dữ liệu module đang lưu về user khi có yêu cầu xóa dữ liệu cá nhân
+	EraseUserData(ctx context.Context, userID uuid.UUID) error
 }
 
 // ModuleRegistry quản lý tất cả các module trong hệ thống
@@ -70,3 +81,30 @@ func (r *ModuleRegistry) GetModuleByName(name string) Module {
 	}
 	return nil
 }
+
+// ExportUserData thu thập dữ liệu của user từ các module được kích hoạt, key là tên module
+func (r *ModuleRegistry) ExportUserData(ctx context.Context, userID uuid.UUID) (map[string]any, error) {
+	data := make(map[string]any)
+	for _, module := range r.GetEnabledModules() {
+		moduleData, err := module.ExportUserData(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("error exporting user data of module %s: %w", module.GetName(), err)
+		}
+		if moduleData != nil {
+			data[module.GetName()] = moduleData
+		}
+	}
+	return data, nil
+}
+
+// EraseUserData xóa dữ liệu của user ở các module được kích hoạt theo thứ tự ngược với lúc đăng ký
+// để module phụ thuộc (đăng ký sau) được xử lý trước module mà nó phụ thuộc
+func (r *ModuleRegistry) EraseUserData(ctx context.Context, userID uuid.UUID) error {
+	modules := r.GetEnabledModules()
+	for i := len(modules) - 1; i >= 0; i-- {
+		if err := modules[i].EraseUserData(ctx, userID); err != nil {
+			return fmt.Errorf("error erasing user data of module %s: %w", modules[i].GetName(), err)
+		}
+	}
+	return nil
+}
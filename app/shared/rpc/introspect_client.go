@@ -0,0 +1,206 @@
+package sharedrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"fat2fast/ikv/shared"
+	"fat2fast/ikv/shared/datatype"
+	"fat2fast/ikv/shared/middleware"
+
+	"github.com/pkg/errors"
+)
+
+// introspectCacheMaxEntries giới hạn số kết quả được cache để bộ nhớ không tăng vô hạn
+const introspectCacheMaxEntries = 10000
+
+// IntrospectClientConfig là cấu hình gọi endpoint introspect của module User từ service khác.
+// CacheTTL và Timeout là chuỗi duration (ví dụ "30s"), thiếu hoặc không hợp lệ dùng mặc định.
+type IntrospectClientConfig struct {
+	URL          string `yaml:"url"`
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	CacheTTL     string `yaml:"cache_ttl"`
+	Timeout      string `yaml:"timeout"`
+}
+
+type introspectCacheEntry struct {
+	requester datatype.Requester
+	expiresAt time.Time
+}
+
+// IntrospectRpcClient xác thực access token/API key bằng endpoint introspect của module User (RFC 7662),
+// implement ITokenIntrospector và IAPIKeyIntrospector của middleware để module chạy tách khỏi module User.
+// Token hợp lệ được cache trong CacheTTL (không quá thời hạn của token) nên token bị thu hồi
+// có thể vẫn được chấp nhận tối đa CacheTTL.
+type IntrospectRpcClient struct {
+	url          string
+	clientID     string
+	clientSecret string
+	cacheTTL     time.Duration
+	httpClient   *http.Client
+
+	mu    sync.Mutex
+	cache map[string]introspectCacheEntry
+}
+
+// NewIntrospectRpcClient khởi tạo client mới
+func NewIntrospectRpcClient(config IntrospectClientConfig) *IntrospectRpcClient {
+	cacheTTL, err := time.ParseDuration(config.CacheTTL)
+	if err != nil || cacheTTL < 0 {
+		cacheTTL = 30 * time.Second // Default: 30 seconds
+	}
+	timeout, err := time.ParseDuration(config.Timeout)
+	if err != nil || timeout <= 0 {
+		timeout = 5 * time.Second // Default: 5 seconds
+	}
+
+	return &IntrospectRpcClient{
+		url:          config.URL,
+		clientID:     config.ClientID,
+		clientSecret: config.ClientSecret,
+		cacheTTL:     cacheTTL,
+		httpClient:   &http.Client{Timeout: timeout},
+		cache:        map[string]introspectCacheEntry{},
+	}
+}
+
+// IntrospectToken kiểm tra access token qua endpoint introspect
+func (c *IntrospectRpcClient) IntrospectToken(ctx context.Context, accessToken string) (datatype.Requester, error) {
+	requester, err := c.introspect(ctx, accessToken, datatype.TokenTypeAccessToken)
+	if err != nil {
+		return nil, err
+	}
+	if requester == nil {
+		return nil, datatype.ErrUnauthorized.WithError("Invalid or expired token")
+	}
+	return requester, nil
+}
+
+// IntrospectAPIKey kiểm tra API key qua endpoint introspect
+func (c *IntrospectRpcClient) IntrospectAPIKey(ctx context.Context, apiKey string) (datatype.Requester, error) {
+	requester, err := c.introspect(ctx, apiKey, datatype.TokenTypeAPIKey)
+	if err != nil {
+		return nil, err
+	}
+	if requester == nil {
+		return nil, datatype.ErrUnauthorized.WithError("invalid, expired or revoked API key")
+	}
+	return requester, nil
+}
+
+// introspect trả về Requester của token hợp lệ, nil nếu token không còn hiệu lực (active = false)
+func (c *IntrospectRpcClient) introspect(ctx context.Context, token string, tokenType string) (datatype.Requester, error) {
+	cacheKey := shared.HashToken(tokenType + ":" + token)
+	if requester := c.cached(cacheKey); requester != nil {
+		return requester, nil
+	}
+
+	result, err := c.call(ctx, token, tokenType)
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+	if !result.Active {
+		return nil, nil
+	}
+
+	requester, err := result.Requester()
+	if err != nil {
+		return nil, datatype.ErrInternalServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	expiresAt := time.Now().Add(c.cacheTTL)
+	if result.ExpiresAt > 0 && time.Unix(result.ExpiresAt, 0).Before(expiresAt) {
+		expiresAt = time.Unix(result.ExpiresAt, 0)
+	}
+	c.store(cacheKey, requester, expiresAt)
+
+	return requester, nil
+}
+
+// call gửi yêu cầu introspect dạng application/x-www-form-urlencoded, xác thực bằng HTTP Basic
+func (c *IntrospectRpcClient) call(ctx context.Context, token string, tokenType string) (*datatype.IntrospectionResult, error) {
+	form := url.Values{}
+	form.Set("token", token)
+	form.Set("token_type_hint", tokenType)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(c.clientID, c.clientSecret)
+	// Truyền request ID để log và audit của hai service đối chiếu được với nhau
+	if requestID := datatype.GetRequestID(ctx); requestID != "" {
+		req.Header.Set(middleware.HeaderRequestID, requestID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspect endpoint returned status %d: %s", resp.StatusCode, body)
+	}
+
+	var result datatype.IntrospectionResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &result, nil
+}
+
+// cached trả về Requester còn hạn trong cache, nil nếu chưa có hoặc đã hết hạn
+func (c *IntrospectRpcClient) cached(key string) datatype.Requester {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.cache[key]
+	if !ok {
+		return nil
+	}
+	if !time.Now().Before(entry.expiresAt) {
+		delete(c.cache, key)
+		return nil
+	}
+	return entry.requester
+}
+
+// store lưu Requester vào cache tới expiresAt, không cache khi CacheTTL = 0
+func (c *IntrospectRpcClient) store(key string, requester datatype.Requester, expiresAt time.Time) {
+	if c.cacheTTL <= 0 || !time.Now().Before(expiresAt) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.cache) >= introspectCacheMaxEntries {
+		now := time.Now()
+		for k, entry := range c.cache {
+			if !now.Before(entry.expiresAt) {
+				delete(c.cache, k)
+			}
+		}
+		// Vẫn đầy thì bỏ toàn bộ cache, các token sẽ được kiểm tra lại ở request sau
+		if len(c.cache) >= introspectCacheMaxEntries {
+			c.cache = map[string]introspectCacheEntry{}
+		}
+	}
+	c.cache[key] = introspectCacheEntry{requester: requester, expiresAt: expiresAt}
+}
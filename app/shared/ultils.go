@@ -1,12 +1,11 @@
 package shared
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
-	"fmt"
-	"math/rand"
 
 	"github.com/pkg/errors"
-	"golang.org/x/crypto/bcrypt"
 )
 
 func RandomStr(length int) (string, error) {
@@ -21,21 +20,8 @@ func RandomStr(length int) (string, error) {
 	return hex.EncodeToString(b), nil
 }
 
-func HashPassword(password string, salt string) (string, error) {
-	saltPass := fmt.Sprintf("%s.%s", salt, password)
-	hashPassword, err := bcrypt.GenerateFromPassword([]byte(saltPass), 8)
-	if err != nil {
-		return "", errors.WithStack(err)
-	}
-	return string(hashPassword), nil
-}
-
-// VerifyPassword kiểm tra password có khớp với hash đã lưu không
-func VerifyPassword(password string, salt string, hashedPassword string) error {
-	saltPass := fmt.Sprintf("%s.%s", salt, password)
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(saltPass))
-	if err != nil {
-		return errors.WithStack(err)
-	}
-	return nil
+// HashToken băm token (refresh token, one-time token...) bằng SHA-256 trước khi lưu database
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
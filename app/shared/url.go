@@ -6,6 +6,21 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// Route mô tả một route của module kèm các middleware riêng của route đó
+type Route struct {
+	Method      string
+	Path        string
+	Middlewares []gin.HandlerFunc
+	HandlerFunc gin.HandlerFunc
+}
+
+// Handlers trả về chuỗi middleware và handler để đăng ký với gin
+func (r Route) Handlers() []gin.HandlerFunc {
+	handlers := make([]gin.HandlerFunc, 0, len(r.Middlewares)+1)
+	handlers = append(handlers, r.Middlewares...)
+	return append(handlers, r.HandlerFunc)
+}
+
 // RouteRegistry quản lý tất cả modules
 type RouteRegistry struct {
 	modules map[string]func() []gin.RouteInfo